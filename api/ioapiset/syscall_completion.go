@@ -0,0 +1,65 @@
+//go:build windows
+
+package ioapiset
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel = windows.NewLazySystemDLL("kernel32.dll")
+
+	procGetQueuedCompletionStatusEx = modkernel.NewProc("GetQueuedCompletionStatusEx")
+)
+
+// OverlappedEntry is an OVERLAPPED_ENTRY structure, which describes a
+// completion packet dequeued from an I/O completion port.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/minwinbase/ns-minwinbase-overlapped_entry
+type OverlappedEntry struct {
+	CompletionKey            uintptr
+	Overlapped               *windows.Overlapped
+	Internal                 uintptr
+	NumberOfBytesTransferred uint32
+}
+
+// GetQueuedCompletionStatusEx dequeues up to len(entries) completion
+// packets from an I/O completion port, waiting up to the given number of
+// milliseconds for at least one to arrive. It returns the number of
+// entries that were filled in.
+//
+// If the wait times out, it returns windows.WAIT_TIMEOUT.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/ioapiset/nf-ioapiset-getqueuedcompletionstatusex
+func GetQueuedCompletionStatusEx(port syscall.Handle, entries []OverlappedEntry, milliseconds uint32, alertable bool) (n int, err error) {
+	if len(entries) == 0 {
+		return 0, syscall.EINVAL
+	}
+
+	var fAlertable uintptr
+	if alertable {
+		fAlertable = 1
+	}
+
+	var removed uint32
+	r0, _, e := syscall.SyscallN(
+		procGetQueuedCompletionStatusEx.Addr(),
+		uintptr(port),
+		uintptr(unsafe.Pointer(&entries[0])),
+		uintptr(len(entries)),
+		uintptr(unsafe.Pointer(&removed)),
+		uintptr(milliseconds),
+		fAlertable)
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, e
+	}
+
+	return int(removed), nil
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package memoryapi
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel = windows.NewLazySystemDLL("kernel32.dll")
+
+	procOpenFileMapping = modkernel.NewProc("OpenFileMappingW")
+)
+
+// OpenFileMapping attempts to open an existing named file mapping object,
+// also known as a section, with the given desired access rights. If the
+// file mapping does not already exist, it returns a non-nil error.
+//
+// The desired access is typically windows.FILE_MAP_READ,
+// windows.FILE_MAP_WRITE, or both.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/memoryapi/nf-memoryapi-openfilemappingw
+func OpenFileMapping(name string, desiredAccess uint32, inheritHandle bool) (syscall.Handle, error) {
+	utf16Name, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	var bInheritHandle uintptr
+	if inheritHandle {
+		bInheritHandle = 1
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procOpenFileMapping.Addr(),
+		uintptr(desiredAccess),
+		bInheritHandle,
+		uintptr(unsafe.Pointer(utf16Name)))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, e
+	}
+
+	return syscall.Handle(r0), nil
+}
@@ -0,0 +1,53 @@
+//go:build windows
+
+package namedpipeapi
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel = windows.NewLazySystemDLL("kernel32.dll")
+
+	procWaitNamedPipe = modkernel.NewProc("WaitNamedPipeW")
+)
+
+// Special timeout values for WaitNamedPipe.
+const (
+	NamedPipeUseDefaultWait = 0x00000000 // NMPWAIT_USE_DEFAULT_WAIT
+	NamedPipeWaitForever    = 0xFFFFFFFF // NMPWAIT_WAIT_FOREVER
+)
+
+// WaitNamedPipe waits until the timeout elapses or an instance of the
+// named pipe with the given name is available for connection. The name
+// must have the form \\server\pipe\name.
+//
+// If no pipe with the given name exists, it returns
+// syscall.ERROR_FILE_NOT_FOUND immediately. If the pipe exists but no
+// instance became available before the timeout elapsed, it returns
+// windows.ERROR_SEM_TIMEOUT.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/namedpipeapi/nf-namedpipeapi-waitnamedpipew
+func WaitNamedPipe(name string, timeout uint32) error {
+	utf16Name, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procWaitNamedPipe.Addr(),
+		uintptr(unsafe.Pointer(utf16Name)),
+		uintptr(timeout))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return e
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+//go:build windows
+
+package namespaceapi
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel = windows.NewLazySystemDLL("kernel32.dll")
+
+	procCreateBoundaryDescriptor   = modkernel.NewProc("CreateBoundaryDescriptorW")
+	procAddSIDToBoundaryDescriptor = modkernel.NewProc("AddSIDToBoundaryDescriptor")
+	procDeleteBoundaryDescriptor   = modkernel.NewProc("DeleteBoundaryDescriptor")
+	procCreatePrivateNamespace     = modkernel.NewProc("CreatePrivateNamespaceW")
+	procOpenPrivateNamespace       = modkernel.NewProc("OpenPrivateNamespaceW")
+	procClosePrivateNamespace      = modkernel.NewProc("ClosePrivateNamespace")
+)
+
+// PrivateNamespaceFlagDestroy causes ClosePrivateNamespace to destroy the
+// namespace. It corresponds to PRIVATE_NAMESPACE_FLAG_DESTROY.
+const PrivateNamespaceFlagDestroy = 0x00000001
+
+// CreateBoundaryDescriptor creates a boundary descriptor with the given
+// name. The descriptor must be freed with DeleteBoundaryDescriptor.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/namespaceapi/nf-namespaceapi-createboundarydescriptorw
+func CreateBoundaryDescriptor(name string) (syscall.Handle, error) {
+	utf16Name, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procCreateBoundaryDescriptor.Addr(),
+		uintptr(unsafe.Pointer(utf16Name)),
+		0) // Flags
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, e
+	}
+
+	return syscall.Handle(r0), nil
+}
+
+// AddSIDToBoundaryDescriptor adds sid to the boundary descriptor that
+// boundary refers to. The descriptor may be reallocated, in which case
+// boundary is updated.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-addsidtoboundarydescriptor
+func AddSIDToBoundaryDescriptor(boundary *syscall.Handle, sid *windows.SID) error {
+	r0, _, e := syscall.SyscallN(
+		procAddSIDToBoundaryDescriptor.Addr(),
+		uintptr(unsafe.Pointer(boundary)),
+		uintptr(unsafe.Pointer(sid)))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return e
+	}
+
+	return nil
+}
+
+// DeleteBoundaryDescriptor frees a boundary descriptor that was created
+// by CreateBoundaryDescriptor.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/namespaceapi/nf-namespaceapi-deleteboundarydescriptor
+func DeleteBoundaryDescriptor(boundary syscall.Handle) {
+	syscall.SyscallN(procDeleteBoundaryDescriptor.Addr(), uintptr(boundary))
+}
+
+// CreatePrivateNamespace creates a private namespace that is isolated by
+// the given boundary descriptor, and makes it accessible to the calling
+// process by the given alias. Objects are created within the namespace by
+// prefixing their names with the alias and a backslash.
+//
+// If the namespace already exists, it returns ERROR_ALREADY_EXISTS and
+// the namespace must be opened with OpenPrivateNamespace instead.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/namespaceapi/nf-namespaceapi-createprivatenamespacew
+func CreatePrivateNamespace(attrs *syscall.SecurityAttributes, boundary syscall.Handle, alias string) (syscall.Handle, error) {
+	utf16Alias, err := syscall.UTF16PtrFromString(alias)
+	if err != nil {
+		return 0, err
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procCreatePrivateNamespace.Addr(),
+		uintptr(unsafe.Pointer(attrs)),
+		uintptr(boundary),
+		uintptr(unsafe.Pointer(utf16Alias)))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, e
+	}
+
+	return syscall.Handle(r0), nil
+}
+
+// OpenPrivateNamespace opens an existing private namespace that is
+// isolated by the given boundary descriptor, and makes it accessible to
+// the calling process by the given alias.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/namespaceapi/nf-namespaceapi-openprivatenamespacew
+func OpenPrivateNamespace(boundary syscall.Handle, alias string) (syscall.Handle, error) {
+	utf16Alias, err := syscall.UTF16PtrFromString(alias)
+	if err != nil {
+		return 0, err
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procOpenPrivateNamespace.Addr(),
+		uintptr(boundary),
+		uintptr(unsafe.Pointer(utf16Alias)))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, e
+	}
+
+	return syscall.Handle(r0), nil
+}
+
+// ClosePrivateNamespace closes the handle to a private namespace. If flags
+// includes PrivateNamespaceFlagDestroy, the namespace is destroyed.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/namespaceapi/nf-namespaceapi-closeprivatenamespace
+func ClosePrivateNamespace(h syscall.Handle, flags uint32) error {
+	r0, _, e := syscall.SyscallN(procClosePrivateNamespace.Addr(), uintptr(h), uintptr(flags))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return e
+	}
+
+	return nil
+}
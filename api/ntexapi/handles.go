@@ -0,0 +1,28 @@
+//go:build windows
+
+package ntexapi
+
+// SystemHandleInformationEx is the header of the data returned by
+// NtQuerySystemInformation for the SystemExtendedHandleInformation class.
+// It corresponds to the SYSTEM_HANDLE_INFORMATION_EX structure.
+//
+// The header is followed by NumberOfHandles entries of type
+// SystemHandleTableEntryInfoEx.
+type SystemHandleInformationEx struct {
+	NumberOfHandles uintptr
+	Reserved        uintptr
+}
+
+// SystemHandleTableEntryInfoEx describes a single handle in the system
+// handle table. It corresponds to the SYSTEM_HANDLE_TABLE_ENTRY_INFO_EX
+// structure.
+type SystemHandleTableEntryInfoEx struct {
+	Object                uintptr
+	UniqueProcessID       uintptr
+	HandleValue           uintptr
+	GrantedAccess         uint32
+	CreatorBackTraceIndex uint16
+	ObjectTypeIndex       uint16
+	HandleAttributes      uint32
+	Reserved              uint32
+}
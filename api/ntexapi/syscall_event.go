@@ -0,0 +1,61 @@
+//go:build windows
+
+package ntexapi
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modntdll = windows.NewLazySystemDLL("ntdll.dll")
+
+	procNtQueryEvent = modntdll.NewProc("NtQueryEvent")
+)
+
+// EventQueryState is the access right required to query the state of an
+// event. It corresponds to EVENT_QUERY_STATE.
+//
+// https://learn.microsoft.com/en-us/windows/win32/sync/synchronization-object-security-and-access-rights
+const EventQueryState = 0x0001
+
+// Event types reported by NtQueryEvent. They correspond to the EVENT_TYPE
+// enumeration.
+//
+// https://learn.microsoft.com/en-us/windows-hardware/drivers/ddi/wdm/ne-wdm-_event_type
+const (
+	NotificationEvent    = 0 // A manual-reset event
+	SynchronizationEvent = 1 // An auto-reset event
+)
+
+// EventBasicInformation describes the type and state of an event. It
+// corresponds to the EVENT_BASIC_INFORMATION structure.
+type EventBasicInformation struct {
+	EventType  uint32
+	EventState int32 // Non-zero if the event is signaled
+}
+
+// NtQueryEvent returns the type and state of the event with the given
+// handle. The handle must have the EventQueryState access right.
+//
+// https://learn.microsoft.com/en-us/windows/win32/devnotes/ntqueryevent
+func NtQueryEvent(h syscall.Handle) (EventBasicInformation, error) {
+	const eventBasicInformation = 0 // EVENT_INFORMATION_CLASS
+
+	var info EventBasicInformation
+	r0, _, _ := syscall.SyscallN(
+		procNtQueryEvent.Addr(),
+		uintptr(h),
+		eventBasicInformation,
+		uintptr(unsafe.Pointer(&info)),
+		unsafe.Sizeof(info),
+		0)
+
+	if status := windows.NTStatus(r0); status != windows.STATUS_SUCCESS {
+		return EventBasicInformation{}, status
+	}
+
+	return info, nil
+}
@@ -0,0 +1,165 @@
+//go:build windows
+
+package ntexapi
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	procNtCreateMutant = modntdll.NewProc("NtCreateMutant")
+	procNtOpenMutant   = modntdll.NewProc("NtOpenMutant")
+	procNtQueryMutant  = modntdll.NewProc("NtQueryMutant")
+)
+
+// MutantQueryState is the access right required to query the state of a
+// mutex. It corresponds to MUTANT_QUERY_STATE.
+const MutantQueryState = 0x0001
+
+// MutantBasicInformation describes the state of a mutex. It corresponds to
+// the MUTANT_BASIC_INFORMATION structure.
+type MutantBasicInformation struct {
+	CurrentCount   int32 // One if the mutex is free, otherwise one minus its recursion count
+	OwnedByCaller  bool  // True if the mutex is owned by the calling thread
+	AbandonedState bool  // True if the mutex was abandoned by its previous owner
+}
+
+// ClientID identifies a thread and the process it belongs to. It
+// corresponds to the CLIENT_ID structure.
+type ClientID struct {
+	UniqueProcess uintptr
+	UniqueThread  uintptr
+}
+
+// NtCreateMutant creates or opens the mutex at the given object manager
+// path, such as `\BaseNamedObjects\MyApp-Lock`, with the requested access
+// rights. Unlike CreateMutex, it accepts paths longer than MAX_PATH. If
+// path is empty, an unnamed mutex is created.
+//
+// The attributes are OBJ_* flags such as windows.OBJ_INHERIT. The
+// windows.OBJ_OPENIF flag is always included, so that an existing mutex
+// is opened instead of causing an error, in which case existed is true.
+// If sd is not nil, it is assigned to a newly created mutex.
+//
+// When successful, a handle to the mutex is returned. It is the caller's
+// responsibility to close the handle.
+//
+// https://learn.microsoft.com/en-us/windows/win32/devnotes/ntcreatemutant
+func NtCreateMutant(path string, access, attributes uint32, sd *windows.SECURITY_DESCRIPTOR, initialOwner bool) (h syscall.Handle, existed bool, err error) {
+	attrs, err := objectAttributes(path, attributes|windows.OBJ_OPENIF, sd)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var owner uintptr
+	if initialOwner {
+		owner = 1
+	}
+
+	r0, _, _ := syscall.SyscallN(
+		procNtCreateMutant.Addr(),
+		uintptr(unsafe.Pointer(&h)),
+		uintptr(access),
+		uintptr(unsafe.Pointer(attrs)),
+		owner)
+
+	switch status := windows.NTStatus(r0); status {
+	case windows.STATUS_SUCCESS:
+		return h, false, nil
+	case windows.STATUS_OBJECT_NAME_EXISTS:
+		return h, true, nil
+	default:
+		return 0, false, status
+	}
+}
+
+// NtOpenMutant opens the existing mutex at the given object manager path
+// with the requested access rights. The attributes are OBJ_* flags such
+// as windows.OBJ_INHERIT.
+//
+// When successful, a handle to the mutex is returned. It is the caller's
+// responsibility to close the handle.
+//
+// https://learn.microsoft.com/en-us/windows/win32/devnotes/ntopenmutant
+func NtOpenMutant(path string, access, attributes uint32) (syscall.Handle, error) {
+	attrs, err := objectAttributes(path, attributes, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var h syscall.Handle
+	r0, _, _ := syscall.SyscallN(
+		procNtOpenMutant.Addr(),
+		uintptr(unsafe.Pointer(&h)),
+		uintptr(access),
+		uintptr(unsafe.Pointer(attrs)))
+
+	if status := windows.NTStatus(r0); status != windows.STATUS_SUCCESS {
+		return 0, status
+	}
+	return h, nil
+}
+
+// objectAttributes returns object attributes for the given path. Like the
+// Win32 functions, they match names with regard to case.
+func objectAttributes(path string, attributes uint32, sd *windows.SECURITY_DESCRIPTOR) (*windows.OBJECT_ATTRIBUTES, error) {
+	attrs := &windows.OBJECT_ATTRIBUTES{
+		Attributes:         attributes,
+		SecurityDescriptor: sd,
+	}
+	attrs.Length = uint32(unsafe.Sizeof(*attrs))
+	if path != "" {
+		name, err := windows.NewNTUnicodeString(path)
+		if err != nil {
+			return nil, err
+		}
+		attrs.ObjectName = name
+	}
+	return attrs, nil
+}
+
+// NtQueryMutant returns the state of the mutex with the given handle. The
+// handle must have the MutantQueryState access right.
+func NtQueryMutant(h syscall.Handle) (MutantBasicInformation, error) {
+	const mutantBasicInformation = 0 // MUTANT_INFORMATION_CLASS
+
+	var info MutantBasicInformation
+	if err := ntQueryMutant(h, mutantBasicInformation, unsafe.Pointer(&info), unsafe.Sizeof(info)); err != nil {
+		return MutantBasicInformation{}, err
+	}
+	return info, nil
+}
+
+// NtQueryMutantOwner returns the thread that owns the mutex with the given
+// handle. Both fields of the returned ClientID are zero if the mutex is not
+// owned. The handle must have the MutantQueryState access right.
+//
+// The information class it relies upon was introduced in Windows 8. On
+// earlier versions, it returns STATUS_INVALID_INFO_CLASS.
+func NtQueryMutantOwner(h syscall.Handle) (ClientID, error) {
+	const mutantOwnerInformation = 1 // MUTANT_INFORMATION_CLASS
+
+	var owner ClientID
+	if err := ntQueryMutant(h, mutantOwnerInformation, unsafe.Pointer(&owner), unsafe.Sizeof(owner)); err != nil {
+		return ClientID{}, err
+	}
+	return owner, nil
+}
+
+func ntQueryMutant(h syscall.Handle, class uintptr, info unsafe.Pointer, size uintptr) error {
+	r0, _, _ := syscall.SyscallN(
+		procNtQueryMutant.Addr(),
+		uintptr(h),
+		class,
+		uintptr(info),
+		size,
+		0)
+
+	if status := windows.NTStatus(r0); status != windows.STATUS_SUCCESS {
+		return status
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+//go:build windows
+
+package ntobapi
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modntdll = windows.NewLazySystemDLL("ntdll.dll")
+
+	procNtOpenDirectoryObject  = modntdll.NewProc("NtOpenDirectoryObject")
+	procNtQueryDirectoryObject = modntdll.NewProc("NtQueryDirectoryObject")
+)
+
+// Access rights for object directories.
+//
+// https://learn.microsoft.com/en-us/windows-hardware/drivers/ddi/wdm/nf-wdm-zwopendirectoryobject
+const (
+	DirectoryQuery    = 0x0001 // DIRECTORY_QUERY
+	DirectoryTraverse = 0x0002 // DIRECTORY_TRAVERSE
+)
+
+// DirectoryInformation is an entry returned by NtQueryDirectoryObject. It
+// corresponds to the OBJECT_DIRECTORY_INFORMATION structure.
+type DirectoryInformation struct {
+	Name     windows.NTUnicodeString
+	TypeName windows.NTUnicodeString
+}
+
+// NtOpenDirectoryObject opens the object directory at the given object
+// manager path, such as "\BaseNamedObjects", with the requested access
+// rights.
+//
+// When successful, a handle to the directory is returned. It is the
+// caller's responsibility to close the handle.
+//
+// https://learn.microsoft.com/en-us/windows/win32/devnotes/ntopendirectoryobject
+func NtOpenDirectoryObject(path string, access uint32) (syscall.Handle, error) {
+	name, err := windows.NewNTUnicodeString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	attrs := windows.OBJECT_ATTRIBUTES{
+		ObjectName: name,
+		Attributes: windows.OBJ_CASE_INSENSITIVE,
+	}
+	attrs.Length = uint32(unsafe.Sizeof(attrs))
+
+	var h syscall.Handle
+	r0, _, _ := syscall.SyscallN(
+		procNtOpenDirectoryObject.Addr(),
+		uintptr(unsafe.Pointer(&h)),
+		uintptr(access),
+		uintptr(unsafe.Pointer(&attrs)))
+
+	if status := windows.NTStatus(r0); status != windows.STATUS_SUCCESS {
+		return 0, status
+	}
+
+	return h, nil
+}
+
+// NtQueryDirectoryObject reads entries from the object directory with the
+// given handle into buffer. The buffer is filled with a sequence of
+// DirectoryInformation values terminated by a zeroed entry, followed by the
+// string data they refer to.
+//
+// The context value tracks the caller's position within the directory and
+// must be preserved between calls. If restart is true, enumeration starts
+// over from the beginning of the directory.
+//
+// The returned status is windows.STATUS_SUCCESS when the final entries have
+// been returned, windows.STATUS_MORE_ENTRIES when the buffer was filled
+// before the end of the directory was reached, and
+// windows.STATUS_NO_MORE_ENTRIES when there was nothing left to return.
+// Any other status indicates failure.
+//
+// https://learn.microsoft.com/en-us/windows/win32/devnotes/ntquerydirectoryobject
+func NtQueryDirectoryObject(dir syscall.Handle, buffer []byte, restart bool, context *uint32) (returnLength uint32, status windows.NTStatus) {
+	var bRestart uintptr
+	if restart {
+		bRestart = 1
+	}
+
+	var ptr unsafe.Pointer
+	if len(buffer) > 0 {
+		ptr = unsafe.Pointer(&buffer[0])
+	}
+
+	r0, _, _ := syscall.SyscallN(
+		procNtQueryDirectoryObject.Addr(),
+		uintptr(dir),
+		uintptr(ptr),
+		uintptr(len(buffer)),
+		0, // ReturnSingleEntry
+		bRestart,
+		uintptr(unsafe.Pointer(context)),
+		uintptr(unsafe.Pointer(&returnLength)))
+
+	return returnLength, windows.NTStatus(r0)
+}
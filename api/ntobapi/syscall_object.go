@@ -0,0 +1,84 @@
+//go:build windows
+
+package ntobapi
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procNtQueryObject = modntdll.NewProc("NtQueryObject")
+
+// Object information classes for NtQueryObject.
+//
+// https://learn.microsoft.com/en-us/windows-hardware/drivers/ddi/ntifs/ne-ntifs-_object_information_class
+const (
+	ObjectBasicInformation = 0
+	ObjectNameInformation  = 1
+	ObjectTypeInformation  = 2
+)
+
+// BasicInformation describes a handle and the object it refers to. It
+// corresponds to the PUBLIC_OBJECT_BASIC_INFORMATION structure.
+type BasicInformation struct {
+	Attributes    uint32 // The handle's attribute flags, such as OBJ_INHERIT
+	GrantedAccess uint32 // The access mask that was granted to the handle
+	HandleCount   uint32
+	PointerCount  uint32
+	Reserved      [10]uint32
+}
+
+// NtQueryObjectBasic returns basic information about the object with the
+// given handle, including the access that was granted to the handle.
+func NtQueryObjectBasic(h syscall.Handle) (BasicInformation, error) {
+	var info BasicInformation
+	buffer := unsafe.Slice((*byte)(unsafe.Pointer(&info)), unsafe.Sizeof(info))
+	if _, status := NtQueryObject(h, ObjectBasicInformation, buffer); status != windows.STATUS_SUCCESS {
+		return BasicInformation{}, status
+	}
+	return info, nil
+}
+
+// NtQueryObject reads information about the object with the given handle
+// into buffer. The class determines the kind of information returned.
+//
+// For ObjectNameInformation and ObjectTypeInformation, the buffer begins
+// with a windows.NTUnicodeString that holds the object's name or the name
+// of its type, respectively. Use ParseUnicodeString to read it.
+//
+// If the buffer is too small, the returned status is
+// windows.STATUS_INFO_LENGTH_MISMATCH or windows.STATUS_BUFFER_OVERFLOW
+// and returnLength is the size required.
+//
+// Querying the name of some kinds of objects, such as synchronous file
+// handles for named pipes, can block indefinitely.
+//
+// https://learn.microsoft.com/en-us/windows-hardware/drivers/ddi/ntifs/nf-ntifs-ntqueryobject
+func NtQueryObject(h syscall.Handle, class uint32, buffer []byte) (returnLength uint32, status windows.NTStatus) {
+	var ptr unsafe.Pointer
+	if len(buffer) > 0 {
+		ptr = unsafe.Pointer(&buffer[0])
+	}
+
+	r0, _, _ := syscall.SyscallN(
+		procNtQueryObject.Addr(),
+		uintptr(h),
+		uintptr(class),
+		uintptr(ptr),
+		uintptr(len(buffer)),
+		uintptr(unsafe.Pointer(&returnLength)))
+
+	return returnLength, windows.NTStatus(r0)
+}
+
+// ParseUnicodeString returns the string described by the
+// windows.NTUnicodeString at the start of buffer, which must have been
+// filled in by NtQueryObject.
+func ParseUnicodeString(buffer []byte) string {
+	if uintptr(len(buffer)) < unsafe.Sizeof(windows.NTUnicodeString{}) {
+		return ""
+	}
+	return (*windows.NTUnicodeString)(unsafe.Pointer(&buffer[0])).String()
+}
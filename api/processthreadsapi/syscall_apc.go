@@ -0,0 +1,34 @@
+//go:build windows
+
+package processthreadsapi
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel = windows.NewLazySystemDLL("kernel32.dll")
+
+	procQueueUserAPC = modkernel.NewProc("QueueUserAPC")
+)
+
+// QueueUserAPC queues an asynchronous procedure call (APC) to the thread
+// with the given handle, which must have THREAD_SET_CONTEXT access. The
+// function fn is called with data the next time the thread enters an
+// alertable state, such as with SleepEx.
+//
+// The function is typically created with windows.NewCallback.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/processthreadsapi/nf-processthreadsapi-queueuserapc
+func QueueUserAPC(fn uintptr, thread syscall.Handle, data uintptr) error {
+	r0, _, e := syscall.SyscallN(procQueueUserAPC.Addr(), fn, uintptr(thread), data)
+	if r0 == 0 {
+		if e == 0 {
+			return syscall.EINVAL
+		}
+		return e
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package securityappcontainer
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel = windows.NewLazySystemDLL("kernel32.dll")
+
+	procGetAppContainerNamedObjectPath = modkernel.NewProc("GetAppContainerNamedObjectPath")
+)
+
+// GetAppContainerNamedObjectPath returns the path of the directory in which
+// the AppContainer identified by token or sid creates named objects. The
+// path can be used as a prefix for object names passed to functions such
+// as CreateMutex.
+//
+// Only one of token and sid should be provided. If both are zero, the
+// AppContainer of the calling process is used.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/securityappcontainer/nf-securityappcontainer-getappcontainernamedobjectpath
+func GetAppContainerNamedObjectPath(token windows.Token, sid *windows.SID) (string, error) {
+	buffer := make([]uint16, windows.MAX_PATH)
+	for {
+		var needed uint32
+		r0, _, e := syscall.SyscallN(
+			procGetAppContainerNamedObjectPath.Addr(),
+			uintptr(token),
+			uintptr(unsafe.Pointer(sid)),
+			uintptr(len(buffer)),
+			uintptr(unsafe.Pointer(&buffer[0])),
+			uintptr(unsafe.Pointer(&needed)))
+
+		if r0 != 0 {
+			return windows.UTF16ToString(buffer), nil
+		}
+		if e == windows.ERROR_INSUFFICIENT_BUFFER && int(needed) > len(buffer) {
+			buffer = make([]uint16, needed)
+			continue
+		}
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return "", e
+	}
+}
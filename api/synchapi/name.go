@@ -0,0 +1,31 @@
+//go:build windows
+
+package synchapi
+
+import (
+	"fmt"
+	"syscall"
+	"unicode/utf16"
+
+	"golang.org/x/sys/windows"
+)
+
+// checkName returns an error if name, together with its null terminator,
+// is longer than MAX_PATH. Like Windows, it measures the name in UTF-16
+// code units. The error wraps windows.ERROR_FILENAME_EXCED_RANGE.
+func checkName(op, name string) error {
+	// A string has at least as many bytes as UTF-16 code units, so short
+	// names need not be measured.
+	if len(name) < syscall.MAX_PATH {
+		return nil
+	}
+
+	var length int
+	for _, r := range name {
+		length += utf16.RuneLen(r)
+	}
+	if length+1 > syscall.MAX_PATH {
+		return fmt.Errorf("%s: name length exceeds the %d character limit specified by MAX_PATH: %s: %w", op, syscall.MAX_PATH, name, windows.ERROR_FILENAME_EXCED_RANGE)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+//go:build windows
+
+package synchapi
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	procCreateEventEx = modkernel.NewProc("CreateEventExW")
+	procOpenEvent     = modkernel.NewProc("OpenEventW")
+	procSetEvent      = modkernel.NewProc("SetEvent")
+	procResetEvent    = modkernel.NewProc("ResetEvent")
+	procPulseEvent    = modkernel.NewProc("PulseEvent")
+)
+
+// Access rights for events.
+//
+// https://learn.microsoft.com/en-us/windows/win32/sync/synchronization-object-security-and-access-rights
+const (
+	EventModifyState = 0x0002     // EVENT_MODIFY_STATE
+	EventAllAccess   = 0x001F0003 // EVENT_ALL_ACCESS
+)
+
+// Flags for CreateEventEx.
+const (
+	CreateEventManualReset = 0x00000001 // CREATE_EVENT_MANUAL_RESET
+	CreateEventInitialSet  = 0x00000002 // CREATE_EVENT_INITIAL_SET
+)
+
+// CreateEventEx attempts to create a Windows event with the given name,
+// attributes, flags and desired access rights. If name is empty, it will
+// create an unnamed event.
+//
+// Without CreateEventManualReset, the event is reset automatically when a
+// single waiting thread is released. Without CreateEventInitialSet, it is
+// created in the non-signaled state.
+//
+// When creating a named event, if an event with the given name already
+// exists, openedExisting will be true and a handle for the existing event
+// will be returned. Its state is not changed.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-createeventexw
+func CreateEventEx(name string, attrs *syscall.SecurityAttributes, flags, desiredAccess uint32) (h syscall.Handle, openedExisting bool, err error) {
+	if err := checkName("create event", name); err != nil {
+		return 0, false, err
+	}
+
+	var utf16Name *uint16
+	if name != "" {
+		var err error
+		utf16Name, err = syscall.UTF16PtrFromString(name)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procCreateEventEx.Addr(),
+		uintptr(unsafe.Pointer(attrs)),
+		uintptr(unsafe.Pointer(utf16Name)),
+		uintptr(flags),
+		uintptr(desiredAccess))
+
+	switch {
+	case r0 == 0:
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, false, e
+	case e == syscall.ERROR_ALREADY_EXISTS:
+		return syscall.Handle(r0), true, nil
+	default:
+		return syscall.Handle(r0), false, nil
+	}
+}
+
+// OpenEvent attempts to open an existing Windows event with the given
+// name and desired access rights. If the named event does not already
+// exist, it returns a non-nil error.
+//
+// Waiting on an event requires syscall.SYNCHRONIZE access, and setting or
+// resetting it requires EventModifyState access.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-openeventw
+func OpenEvent(name string, desiredAccess uint32) (syscall.Handle, error) {
+	utf16Name, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procOpenEvent.Addr(),
+		uintptr(desiredAccess),
+		0, // bInheritHandle
+		uintptr(unsafe.Pointer(utf16Name)))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, e
+	}
+
+	return syscall.Handle(r0), nil
+}
+
+// SetEvent sets the Windows event with the given handle to the signaled
+// state.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-setevent
+func SetEvent(h syscall.Handle) error {
+	return eventCall(procSetEvent, h)
+}
+
+// ResetEvent sets the Windows event with the given handle to the
+// non-signaled state.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-resetevent
+func ResetEvent(h syscall.Handle) error {
+	return eventCall(procResetEvent, h)
+}
+
+// PulseEvent sets the Windows event with the given handle to the signaled
+// state and then resets it after releasing the appropriate number of
+// waiting threads.
+//
+// Microsoft considers PulseEvent unreliable, because a thread that is
+// temporarily removed from its wait, such as to run a kernel-mode APC,
+// can miss the pulse. It is provided for compatibility with existing
+// designs.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-pulseevent
+func PulseEvent(h syscall.Handle) error {
+	return eventCall(procPulseEvent, h)
+}
+
+// eventCall calls a function that accepts an event handle and returns a
+// BOOL.
+func eventCall(proc *windows.LazyProc, h syscall.Handle) error {
+	r0, _, e := syscall.SyscallN(proc.Addr(), uintptr(h))
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return e
+	}
+	return nil
+}
@@ -3,7 +3,6 @@
 package synchapi
 
 import (
-	"fmt"
 	"syscall"
 	"unsafe"
 
@@ -19,6 +18,19 @@ var (
 	procReleaseMutex  = modkernel.NewProc("ReleaseMutex")
 )
 
+// Access rights for mutexes.
+//
+// https://learn.microsoft.com/en-us/windows/win32/sync/synchronization-object-security-and-access-rights
+const (
+	MutexModifyState = 0x0001     // MUTEX_MODIFY_STATE
+	MutexAllAccess   = 0x001F0001 // MUTEX_ALL_ACCESS
+)
+
+// Flags for CreateMutexEx.
+const (
+	CreateMutexInitialOwner = 0x00000001 // CREATE_MUTEX_INITIAL_OWNER
+)
+
 // CreateMutex attempts to create a Windows mutex with the given name and
 // attributes. If name is empty, it will created an unnamed mutex.
 //
@@ -39,8 +51,8 @@ var (
 //
 // https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-createmutexw
 func CreateMutex(name string, initialOwner bool, attrs *syscall.SecurityAttributes) (h syscall.Handle, openedExisting bool, err error) {
-	if len(name)+1 >= syscall.MAX_PATH {
-		return 0, false, fmt.Errorf("create mutex: name length exceeds the %d character limit specified by MAX_PATH: %s", syscall.MAX_PATH, name)
+	if err := checkName("create mutex", name); err != nil {
+		return 0, false, err
 	}
 
 	var utf16Name *uint16
@@ -80,18 +92,20 @@ func CreateMutex(name string, initialOwner bool, attrs *syscall.SecurityAttribut
 // openedExisting will be true and a handle for the existing mutex will be
 // returned.
 //
+// If flags includes CreateMutexInitialOwner and the mutex does not already
+// exist, the calling thread takes ownership of it. The handle is granted
+// the desired access rights, such as syscall.SYNCHRONIZE or MutexAllAccess.
+//
 // When successful, a handle to the mutex is returned. The handle is bound
 // to the calling thread. This means that the caller should call
 // runtime.LockOSThread() before calling this function. If any calls to a wait
 // function or to ReleaseMutex are made with this handle, they must be made
 // from the same thread.
 //
-// TODO: Add support for flags and desired access settings.
-//
 // https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-createmutexexw
-func CreateMutexEx(name string, attrs *syscall.SecurityAttributes) (h syscall.Handle, openedExisting bool, err error) {
-	if len(name)+1 >= syscall.MAX_PATH {
-		return 0, false, fmt.Errorf("create mutex: name length exceeds the %d character limit specified by MAX_PATH: %s", syscall.MAX_PATH, name)
+func CreateMutexEx(name string, attrs *syscall.SecurityAttributes, flags, desiredAccess uint32) (h syscall.Handle, openedExisting bool, err error) {
+	if err := checkName("create mutex", name); err != nil {
+		return 0, false, err
 	}
 
 	var utf16Name *uint16
@@ -107,22 +121,29 @@ func CreateMutexEx(name string, attrs *syscall.SecurityAttributes) (h syscall.Ha
 		procCreateMutexEx.Addr(),
 		uintptr(unsafe.Pointer(attrs)),
 		uintptr(unsafe.Pointer(utf16Name)),
-		0,
-		0)
+		uintptr(flags),
+		uintptr(desiredAccess))
 
-	switch e {
-	case syscall.ERROR_ALREADY_EXISTS:
+	switch {
+	case r0 == 0:
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, false, e
+	case e == syscall.ERROR_ALREADY_EXISTS:
 		return syscall.Handle(r0), true, nil
-	case 0:
-		return syscall.Handle(r0), false, nil
 	default:
-		return syscall.Handle(r0), false, e
+		return syscall.Handle(r0), false, nil
 	}
 }
 
 // OpenMutex attempts to open an existing Windows mutex with the given name
-// and attributes. If the named mutex does not already exist, it returns
-// a non-nil error.
+// and desired access rights. If the named mutex does not already exist, it
+// returns a non-nil error.
+//
+// Waiting on a mutex requires syscall.SYNCHRONIZE access. Querying or
+// changing its security descriptor requires READ_CONTROL or WRITE_DAC
+// access, and MutexModifyState is required by some interop scenarios.
 //
 // When successful, a handle to the mutex is returned. The handle is bound
 // to the calling thread. This means that the caller should call
@@ -130,18 +151,10 @@ func CreateMutexEx(name string, attrs *syscall.SecurityAttributes) (h syscall.Ha
 // function or to ReleaseMutex are made with this handle, they must be made
 // from the same thread.
 //
-// TODO: Accept the desired access rights as a parameter.
-//
 // https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-openmutexw
-func OpenMutex(name string) (syscall.Handle, error) {
-	// Always use SYNCHRONIZE access rights when opening mutexes for now.
-	//
-	// See this document for possible access rights:
-	// https://learn.microsoft.com/en-us/windows/win32/sync/synchronization-object-security-and-access-rights
-	const synchronize = 0x00100000
-
-	if len(name)+1 >= syscall.MAX_PATH {
-		return 0, fmt.Errorf("open mutex: name length exceeds the %d character limit specified by MAX_PATH: %s", syscall.MAX_PATH, name)
+func OpenMutex(name string, desiredAccess uint32) (syscall.Handle, error) {
+	if err := checkName("open mutex", name); err != nil {
+		return 0, err
 	}
 
 	var utf16Name *uint16
@@ -155,8 +168,8 @@ func OpenMutex(name string) (syscall.Handle, error) {
 
 	r0, _, e := syscall.SyscallN(
 		procOpenMutex.Addr(),
-		synchronize, // dwDesiredAccess
-		0,           // bInheritHandle
+		uintptr(desiredAccess),
+		0, // bInheritHandle
 		uintptr(unsafe.Pointer(utf16Name)))
 
 	if r0 == 0 && e == 0 {
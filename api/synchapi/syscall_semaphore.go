@@ -0,0 +1,120 @@
+//go:build windows
+
+package synchapi
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procCreateSemaphore  = modkernel.NewProc("CreateSemaphoreW")
+	procOpenSemaphore    = modkernel.NewProc("OpenSemaphoreW")
+	procReleaseSemaphore = modkernel.NewProc("ReleaseSemaphore")
+)
+
+// Access rights for semaphores.
+//
+// https://learn.microsoft.com/en-us/windows/win32/sync/synchronization-object-security-and-access-rights
+const (
+	SemaphoreModifyState = 0x0002     // SEMAPHORE_MODIFY_STATE
+	SemaphoreAllAccess   = 0x001F0003 // SEMAPHORE_ALL_ACCESS
+)
+
+// CreateSemaphore attempts to create a Windows semaphore with the given
+// name, initial count, maximum count and attributes. If name is empty, it
+// will create an unnamed semaphore.
+//
+// When creating a named semaphore, if a semaphore with the given name
+// already exists, openedExisting will be true and a handle for the
+// existing semaphore will be returned. Its counts are not changed.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-createsemaphorew
+func CreateSemaphore(name string, initialCount, maximumCount int32, attrs *syscall.SecurityAttributes) (h syscall.Handle, openedExisting bool, err error) {
+	if err := checkName("create semaphore", name); err != nil {
+		return 0, false, err
+	}
+
+	var utf16Name *uint16
+	if name != "" {
+		var err error
+		utf16Name, err = syscall.UTF16PtrFromString(name)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procCreateSemaphore.Addr(),
+		uintptr(unsafe.Pointer(attrs)),
+		uintptr(initialCount),
+		uintptr(maximumCount),
+		uintptr(unsafe.Pointer(utf16Name)))
+
+	switch {
+	case r0 == 0:
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, false, e
+	case e == syscall.ERROR_ALREADY_EXISTS:
+		return syscall.Handle(r0), true, nil
+	default:
+		return syscall.Handle(r0), false, nil
+	}
+}
+
+// OpenSemaphore attempts to open an existing Windows semaphore with the
+// given name and desired access rights. If the named semaphore does not
+// already exist, it returns a non-nil error.
+//
+// Waiting on a semaphore requires syscall.SYNCHRONIZE access, and
+// releasing it requires SemaphoreModifyState access.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-opensemaphorew
+func OpenSemaphore(name string, desiredAccess uint32) (syscall.Handle, error) {
+	utf16Name, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procOpenSemaphore.Addr(),
+		uintptr(desiredAccess),
+		0, // bInheritHandle
+		uintptr(unsafe.Pointer(utf16Name)))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, e
+	}
+
+	return syscall.Handle(r0), nil
+}
+
+// ReleaseSemaphore increases the count of the Windows semaphore with the
+// given handle by releaseCount. It returns the count that the semaphore
+// had before it was released.
+//
+// If the release would cause the count to exceed the semaphore's maximum,
+// the count is not changed and ERROR_TOO_MANY_POSTS is returned.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-releasesemaphore
+func ReleaseSemaphore(h syscall.Handle, releaseCount int32) (previousCount int32, err error) {
+	r0, _, e := syscall.SyscallN(
+		procReleaseSemaphore.Addr(),
+		uintptr(h),
+		uintptr(releaseCount),
+		uintptr(unsafe.Pointer(&previousCount)))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, e
+	}
+
+	return previousCount, nil
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package synchapi
+
+import "syscall"
+
+var procSleepEx = modkernel.NewProc("SleepEx")
+
+// WaitIOCompletion is returned by SleepEx and the alertable wait functions
+// when they return early because one or more asynchronous procedure calls
+// were run. It corresponds to WAIT_IO_COMPLETION.
+const WaitIOCompletion = 0x000000C0
+
+// SleepEx suspends the calling thread for the given number of
+// milliseconds. If alertable is true, the sleep ends early when an
+// asynchronous procedure call (APC) or I/O completion routine is queued to
+// the thread, after it has been run.
+//
+// It returns zero if the full interval elapsed, or WaitIOCompletion if it
+// ended early because one or more APCs were run.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-sleepex
+func SleepEx(milliseconds uint32, alertable bool) uint32 {
+	var bAlertable uintptr
+	if alertable {
+		bAlertable = 1
+	}
+
+	r0, _, _ := syscall.SyscallN(procSleepEx.Addr(), uintptr(milliseconds), bAlertable)
+	return uint32(r0)
+}
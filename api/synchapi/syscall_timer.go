@@ -0,0 +1,149 @@
+//go:build windows
+
+package synchapi
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procCreateWaitableTimerEx = modkernel.NewProc("CreateWaitableTimerExW")
+	procOpenWaitableTimer     = modkernel.NewProc("OpenWaitableTimerW")
+	procSetWaitableTimer      = modkernel.NewProc("SetWaitableTimer")
+	procCancelWaitableTimer   = modkernel.NewProc("CancelWaitableTimer")
+)
+
+// Flags for CreateWaitableTimerEx.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-createwaitabletimerexw
+const (
+	CreateWaitableTimerManualReset    = 0x00000001 // CREATE_WAITABLE_TIMER_MANUAL_RESET
+	CreateWaitableTimerHighResolution = 0x00000002 // CREATE_WAITABLE_TIMER_HIGH_RESOLUTION
+)
+
+// TimerAllAccess grants all access rights to a waitable timer. It
+// corresponds to TIMER_ALL_ACCESS.
+//
+// https://learn.microsoft.com/en-us/windows/win32/sync/synchronization-object-security-and-access-rights
+const TimerAllAccess = 0x001F0003
+
+// CreateWaitableTimerEx attempts to create a Windows waitable timer with
+// the given name, attributes, flags and desired access rights. If name is
+// empty, it will create an unnamed timer.
+//
+// When creating a named timer, if a timer with the given name already
+// exists, openedExisting will be true and a handle for the existing timer
+// will be returned.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-createwaitabletimerexw
+func CreateWaitableTimerEx(name string, attrs *syscall.SecurityAttributes, flags, desiredAccess uint32) (h syscall.Handle, openedExisting bool, err error) {
+	if err := checkName("create waitable timer", name); err != nil {
+		return 0, false, err
+	}
+
+	var utf16Name *uint16
+	if name != "" {
+		var err error
+		utf16Name, err = syscall.UTF16PtrFromString(name)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procCreateWaitableTimerEx.Addr(),
+		uintptr(unsafe.Pointer(attrs)),
+		uintptr(unsafe.Pointer(utf16Name)),
+		uintptr(flags),
+		uintptr(desiredAccess))
+
+	switch {
+	case r0 == 0 && e == 0:
+		return 0, false, syscall.EINVAL
+	case r0 == 0:
+		return 0, false, e
+	case e == syscall.ERROR_ALREADY_EXISTS:
+		return syscall.Handle(r0), true, nil
+	default:
+		return syscall.Handle(r0), false, nil
+	}
+}
+
+// OpenWaitableTimer attempts to open an existing Windows waitable timer
+// with the given name and desired access rights. If the named timer does
+// not already exist, it returns a non-nil error.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-openwaitabletimerw
+func OpenWaitableTimer(name string, desiredAccess uint32) (syscall.Handle, error) {
+	utf16Name, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procOpenWaitableTimer.Addr(),
+		uintptr(desiredAccess),
+		0, // bInheritHandle
+		uintptr(unsafe.Pointer(utf16Name)))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, e
+	}
+
+	return syscall.Handle(r0), nil
+}
+
+// SetWaitableTimer activates the waitable timer with the given handle.
+//
+// A positive dueTime is an absolute time expressed in 100 nanosecond
+// intervals since January 1, 1601 (UTC), as in a FILETIME. A negative
+// dueTime is a time relative to the present, in 100 nanosecond intervals.
+//
+// If period is non-zero, the timer is signaled periodically every period
+// milliseconds after it first becomes due.
+//
+// If resume is true, the system will be woken from suspend when the timer
+// becomes due, provided that the system supports it.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-setwaitabletimer
+func SetWaitableTimer(h syscall.Handle, dueTime int64, period int32, resume bool) error {
+	var fResume uintptr
+	if resume {
+		fResume = 1
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procSetWaitableTimer.Addr(),
+		uintptr(h),
+		uintptr(unsafe.Pointer(&dueTime)),
+		uintptr(period),
+		0, // pfnCompletionRoutine
+		0, // lpArgToCompletionRoutine
+		fResume)
+
+	if r0 == 0 {
+		if e == 0 {
+			return syscall.EINVAL
+		}
+		return e
+	}
+	return nil
+}
+
+// CancelWaitableTimer deactivates the waitable timer with the given handle.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-cancelwaitabletimer
+func CancelWaitableTimer(h syscall.Handle) error {
+	r0, _, e := syscall.SyscallN(procCancelWaitableTimer.Addr(), uintptr(h))
+	if r0 == 0 {
+		if e == 0 {
+			return syscall.EINVAL
+		}
+		return e
+	}
+	return nil
+}
@@ -2,8 +2,152 @@
 
 package synchapi
 
+import (
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	procWaitForSingleObjectEx    = modkernel.NewProc("WaitForSingleObjectEx")
+	procWaitForMultipleObjects   = modkernel.NewProc("WaitForMultipleObjects")
+	procWaitForMultipleObjectsEx = modkernel.NewProc("WaitForMultipleObjectsEx")
+)
+
 // Special return values for syscall.WaitForSingleObject().
 const (
 	WaitAbandoned = 0x00000080 // WAIT_ABANDONED
 	WaitTimeout   = 0x00000102 // WAIT_TIMEOUT
 )
+
+// MaximumWaitObjects is the maximum number of handles that can be passed
+// to WaitForMultipleObjects. It corresponds to MAXIMUM_WAIT_OBJECTS.
+const MaximumWaitObjects = 64
+
+// WaitMilliseconds converts d to a timeout in milliseconds that can be
+// passed to a wait function. It rounds up, so that a short positive
+// duration does not become a zero-length wait, and it stays just below
+// INFINITE, so that a long duration does not become an unbounded wait.
+// Durations that are zero or negative are returned as zero.
+func WaitMilliseconds(d time.Duration) uint32 {
+	if d <= 0 {
+		return 0
+	}
+	ms := d / time.Millisecond
+	if d%time.Millisecond != 0 {
+		ms++
+	}
+	if ms >= windows.INFINITE {
+		return windows.INFINITE - 1
+	}
+	return uint32(ms)
+}
+
+// WaitForSingleObjectEx waits until the object with the given handle is
+// signaled, or until the given number of milliseconds have elapsed.
+//
+// A return value of syscall.WAIT_OBJECT_0 indicates that the object was
+// signaled, and WaitAbandoned indicates that it was a mutex that was
+// abandoned. If the wait times out, WaitTimeout is returned. If alertable
+// is true, the wait also ends when an asynchronous procedure call (APC)
+// or I/O completion routine is queued to the calling thread, after it has
+// been run, in which case WaitIOCompletion is returned.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-waitforsingleobjectex
+func WaitForSingleObjectEx(h syscall.Handle, milliseconds uint32, alertable bool) (uint32, error) {
+	var bAlertable uintptr
+	if alertable {
+		bAlertable = 1
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procWaitForSingleObjectEx.Addr(),
+		uintptr(h),
+		uintptr(milliseconds),
+		bAlertable)
+
+	if uint32(r0) == syscall.WAIT_FAILED {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return syscall.WAIT_FAILED, e
+	}
+
+	return uint32(r0), nil
+}
+
+// WaitForMultipleObjects waits until one or all of the objects with the
+// given handles are signaled, or until the given number of milliseconds
+// have elapsed.
+//
+// If waitAll is false, a return value of syscall.WAIT_OBJECT_0+i indicates
+// that the object at index i was signaled, and WaitAbandoned+i indicates
+// that it was a mutex that was abandoned. If waitAll is true, the index is
+// not meaningful. If the wait times out, WaitTimeout is returned.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-waitformultipleobjects
+func WaitForMultipleObjects(handles []syscall.Handle, waitAll bool, milliseconds uint32) (uint32, error) {
+	if len(handles) == 0 || len(handles) > MaximumWaitObjects {
+		return syscall.WAIT_FAILED, syscall.EINVAL
+	}
+
+	var bWaitAll uintptr
+	if waitAll {
+		bWaitAll = 1
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procWaitForMultipleObjects.Addr(),
+		uintptr(len(handles)),
+		uintptr(unsafe.Pointer(&handles[0])),
+		bWaitAll,
+		uintptr(milliseconds))
+
+	if uint32(r0) == syscall.WAIT_FAILED {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return syscall.WAIT_FAILED, e
+	}
+
+	return uint32(r0), nil
+}
+
+// WaitForMultipleObjectsEx waits in the same way as WaitForMultipleObjects.
+// If alertable is true, the wait also ends when an asynchronous procedure
+// call (APC) or I/O completion routine is queued to the calling thread,
+// after it has been run, in which case WaitIOCompletion is returned.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-waitformultipleobjectsex
+func WaitForMultipleObjectsEx(handles []syscall.Handle, waitAll bool, milliseconds uint32, alertable bool) (uint32, error) {
+	if len(handles) == 0 || len(handles) > MaximumWaitObjects {
+		return syscall.WAIT_FAILED, syscall.EINVAL
+	}
+
+	var bWaitAll, bAlertable uintptr
+	if waitAll {
+		bWaitAll = 1
+	}
+	if alertable {
+		bAlertable = 1
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procWaitForMultipleObjectsEx.Addr(),
+		uintptr(len(handles)),
+		uintptr(unsafe.Pointer(&handles[0])),
+		bWaitAll,
+		uintptr(milliseconds),
+		bAlertable)
+
+	if uint32(r0) == syscall.WAIT_FAILED {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return syscall.WAIT_FAILED, e
+	}
+
+	return uint32(r0), nil
+}
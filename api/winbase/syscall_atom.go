@@ -0,0 +1,97 @@
+//go:build windows
+
+package winbase
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procGlobalAddAtom     = modkernel.NewProc("GlobalAddAtomW")
+	procGlobalFindAtom    = modkernel.NewProc("GlobalFindAtomW")
+	procGlobalDeleteAtom  = modkernel.NewProc("GlobalDeleteAtom")
+	procGlobalGetAtomName = modkernel.NewProc("GlobalGetAtomNameW")
+)
+
+// GlobalAddAtom adds a string to the global atom table and returns its
+// atom. If the string is already in the table, its reference count is
+// incremented and its existing atom is returned.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-globaladdatomw
+func GlobalAddAtom(name string) (uint16, error) {
+	utf16Name, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	r0, _, e := syscall.SyscallN(procGlobalAddAtom.Addr(), uintptr(unsafe.Pointer(utf16Name)))
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return 0, e
+	}
+
+	return uint16(r0), nil
+}
+
+// GlobalFindAtom searches the global atom table for a string and returns
+// its atom. If the string is not in the table, it returns
+// syscall.ERROR_FILE_NOT_FOUND.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-globalfindatomw
+func GlobalFindAtom(name string) (uint16, error) {
+	utf16Name, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	r0, _, e := syscall.SyscallN(procGlobalFindAtom.Addr(), uintptr(unsafe.Pointer(utf16Name)))
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.ERROR_FILE_NOT_FOUND
+		}
+		return 0, e
+	}
+
+	return uint16(r0), nil
+}
+
+// GlobalDeleteAtom decrements the reference count of a global atom. When
+// the reference count reaches zero, the atom's string is removed from the
+// global atom table.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-globaldeleteatom
+func GlobalDeleteAtom(atom uint16) error {
+	r0, _, e := syscall.SyscallN(procGlobalDeleteAtom.Addr(), uintptr(atom))
+	if r0 != 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return e
+	}
+	return nil
+}
+
+// GlobalGetAtomName returns the string associated with a global atom.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-globalgetatomnamew
+func GlobalGetAtomName(atom uint16) (string, error) {
+	// Atom strings are limited to 255 characters.
+	var buffer [256]uint16
+
+	r0, _, e := syscall.SyscallN(
+		procGlobalGetAtomName.Addr(),
+		uintptr(atom),
+		uintptr(unsafe.Pointer(&buffer[0])),
+		uintptr(len(buffer)))
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return "", e
+	}
+
+	return syscall.UTF16ToString(buffer[:r0]), nil
+}
@@ -0,0 +1,84 @@
+//go:build windows
+
+package winbase
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel = windows.NewLazySystemDLL("kernel32.dll")
+
+	procCreateMailslot  = modkernel.NewProc("CreateMailslotW")
+	procGetMailslotInfo = modkernel.NewProc("GetMailslotInfo")
+)
+
+// Special values for mailslot timeouts and message sizes.
+const (
+	MailslotWaitForever = 0xFFFFFFFF // MAILSLOT_WAIT_FOREVER
+	MailslotNoMessage   = 0xFFFFFFFF // MAILSLOT_NO_MESSAGE
+)
+
+// CreateMailslot creates a mailslot with the given name, which must have
+// the form \\.\mailslot\[path]name.
+//
+// The maxMessageSize limits the size of messages that can be written to
+// the mailslot. A value of zero permits messages of any size. The
+// readTimeout is the number of milliseconds that a read operation waits
+// for a message to arrive, which may be zero, or MailslotWaitForever.
+//
+// When successful, a handle to the server end of the mailslot is returned.
+// It is the caller's responsibility to close the handle.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-createmailslotw
+func CreateMailslot(name string, maxMessageSize, readTimeout uint32, attrs *syscall.SecurityAttributes) (syscall.Handle, error) {
+	utf16Name, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return syscall.InvalidHandle, err
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procCreateMailslot.Addr(),
+		uintptr(unsafe.Pointer(utf16Name)),
+		uintptr(maxMessageSize),
+		uintptr(readTimeout),
+		uintptr(unsafe.Pointer(attrs)))
+
+	h := syscall.Handle(r0)
+	if h == syscall.InvalidHandle {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return h, e
+	}
+
+	return h, nil
+}
+
+// GetMailslotInfo retrieves information about the mailslot with the given
+// handle.
+//
+// The nextSize is the size of the next message in the mailslot, or
+// MailslotNoMessage if the mailslot is empty.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-getmailslotinfo
+func GetMailslotInfo(h syscall.Handle) (maxMessageSize, nextSize, messageCount, readTimeout uint32, err error) {
+	r0, _, e := syscall.SyscallN(
+		procGetMailslotInfo.Addr(),
+		uintptr(h),
+		uintptr(unsafe.Pointer(&maxMessageSize)),
+		uintptr(unsafe.Pointer(&nextSize)),
+		uintptr(unsafe.Pointer(&messageCount)),
+		uintptr(unsafe.Pointer(&readTimeout)))
+
+	if r0 == 0 {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		err = e
+	}
+	return
+}
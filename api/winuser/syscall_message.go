@@ -0,0 +1,135 @@
+//go:build windows
+
+package winuser
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	moduser = windows.NewLazySystemDLL("user32.dll")
+
+	procMsgWaitForMultipleObjectsEx = moduser.NewProc("MsgWaitForMultipleObjectsEx")
+	procPeekMessage                 = moduser.NewProc("PeekMessageW")
+	procTranslateMessage            = moduser.NewProc("TranslateMessage")
+	procDispatchMessage             = moduser.NewProc("DispatchMessageW")
+	procPostQuitMessage             = moduser.NewProc("PostQuitMessage")
+)
+
+// Queue status flags for MsgWaitForMultipleObjectsEx.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-msgwaitformultipleobjectsex
+const (
+	QSAllInput = 0x04FF // QS_ALLINPUT
+)
+
+// Wait flags for MsgWaitForMultipleObjectsEx.
+const (
+	MWMOAlertable      = 0x0002 // MWMO_ALERTABLE
+	MWMOInputAvailable = 0x0004 // MWMO_INPUTAVAILABLE
+)
+
+// Removal flags for PeekMessage.
+const (
+	PMNoRemove = 0x0000 // PM_NOREMOVE
+	PMRemove   = 0x0001 // PM_REMOVE
+)
+
+// WMQuit is the message that asks a thread to exit its message loop. It
+// corresponds to WM_QUIT.
+const WMQuit = 0x0012
+
+// Point is a point on the screen. It corresponds to the POINT structure.
+type Point struct {
+	X int32
+	Y int32
+}
+
+// Msg is a message from a thread's message queue. It corresponds to the
+// MSG structure.
+type Msg struct {
+	Hwnd     syscall.Handle
+	Message  uint32
+	WParam   uintptr
+	LParam   uintptr
+	Time     uint32
+	Pt       Point
+	LPrivate uint32
+}
+
+// MsgWaitForMultipleObjectsEx waits until one or all of the objects with
+// the given handles are signaled, until input of the types in wakeMask is
+// available in the calling thread's message queue, or until the given
+// number of milliseconds have elapsed.
+//
+// A return value of syscall.WAIT_OBJECT_0+len(handles) indicates that
+// input is available.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-msgwaitformultipleobjectsex
+func MsgWaitForMultipleObjectsEx(handles []syscall.Handle, milliseconds, wakeMask, flags uint32) (uint32, error) {
+	var ptr *syscall.Handle
+	if len(handles) > 0 {
+		ptr = &handles[0]
+	}
+
+	r0, _, e := syscall.SyscallN(
+		procMsgWaitForMultipleObjectsEx.Addr(),
+		uintptr(len(handles)),
+		uintptr(unsafe.Pointer(ptr)),
+		uintptr(milliseconds),
+		uintptr(wakeMask),
+		uintptr(flags))
+
+	if uint32(r0) == syscall.WAIT_FAILED {
+		if e == 0 {
+			e = syscall.EINVAL
+		}
+		return syscall.WAIT_FAILED, e
+	}
+
+	return uint32(r0), nil
+}
+
+// PeekMessage retrieves a message from the calling thread's message queue
+// without waiting, and reports whether one was available.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-peekmessagew
+func PeekMessage(msg *Msg, hwnd syscall.Handle, filterMin, filterMax, remove uint32) bool {
+	r0, _, _ := syscall.SyscallN(
+		procPeekMessage.Addr(),
+		uintptr(unsafe.Pointer(msg)),
+		uintptr(hwnd),
+		uintptr(filterMin),
+		uintptr(filterMax),
+		uintptr(remove))
+	return r0 != 0
+}
+
+// TranslateMessage translates virtual-key messages into character
+// messages.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-translatemessage
+func TranslateMessage(msg *Msg) bool {
+	r0, _, _ := syscall.SyscallN(procTranslateMessage.Addr(), uintptr(unsafe.Pointer(msg)))
+	return r0 != 0
+}
+
+// DispatchMessage dispatches a message to a window procedure and returns
+// the value that it returned.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-dispatchmessagew
+func DispatchMessage(msg *Msg) uintptr {
+	r0, _, _ := syscall.SyscallN(procDispatchMessage.Addr(), uintptr(unsafe.Pointer(msg)))
+	return r0
+}
+
+// PostQuitMessage posts a WM_QUIT message with the given exit code to the
+// calling thread's message queue.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-postquitmessage
+func PostQuitMessage(exitCode int32) {
+	syscall.SyscallN(procPostQuitMessage.Addr(), uintptr(exitCode))
+}
@@ -0,0 +1,54 @@
+//go:build windows
+
+package winobj
+
+import (
+	"fmt"
+
+	"github.com/gentlemanautomaton/winobj/api/securityappcontainer"
+	"golang.org/x/sys/windows"
+)
+
+// AppContainerPath returns the path of the directory in which the
+// AppContainer with the given SID creates named objects, such as
+// `Session\1\AppContainerNamedObjects\S-1-15-2-...`. The directory belongs
+// to the session of the calling process.
+//
+// A broker process can create objects within the directory so that a
+// packaged or sandboxed application running in the AppContainer can open
+// them by their base names, and it can open objects that the application
+// created in the same way.
+//
+// If sid is nil, the AppContainer of the calling process is used, and an
+// error is returned if the calling process is not running in one.
+func AppContainerPath(sid *windows.SID) (string, error) {
+	path, err := securityappcontainer.GetAppContainerNamedObjectPath(0, sid)
+	if err != nil {
+		if sid == nil {
+			return "", fmt.Errorf("winobj: failed to determine the named object path of the current AppContainer: %w", err)
+		}
+		return "", fmt.Errorf("winobj: failed to determine the named object path of the %s AppContainer: %w", sid, err)
+	}
+	return path, nil
+}
+
+// WithAppContainer returns an option that creates or opens objects within
+// the named object directory of the AppContainer with the given SID, as
+// returned by AppContainerPath. The name given to the constructor is used
+// within the directory, and the default prefix set by SetDefaultPrefix is
+// not applied to it.
+//
+// If the path cannot be determined, constructors that are given the option
+// return an error.
+func WithAppContainer(sid *windows.SID) Option {
+	path, err := AppContainerPath(sid)
+	return func(o *Options) {
+		if err != nil {
+			if o.err == nil {
+				o.err = err
+			}
+			return
+		}
+		o.Directory = path
+	}
+}
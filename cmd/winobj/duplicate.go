@@ -0,0 +1,141 @@
+//go:build windows
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj/api/memoryapi"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// maximumAllowed requests every access right that the caller is entitled
+// to when opening an object (MAXIMUM_ALLOWED).
+const maximumAllowed = 0x02000000
+
+// duplicateResult is the machine-readable output of the duplicate command.
+type duplicateResult struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	PID    uint32 `json:"pid"`
+	Handle string `json:"handle"`           // Hexadecimal, as it appears in the target process
+	Access string `json:"access,omitempty"` // Hexadecimal, if requested
+}
+
+func runDuplicate(args []string) int {
+	flags := flag.NewFlagSet("duplicate", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj duplicate [flags] <name> <pid>\n\nOpens a named object and duplicates a handle to it into the process with\nthe given ID, then prints the value of the handle in that process.\n\nThe handle remains open in the target process until that process closes\nit or exits. This is intended for debugging and for testing programs that\nreceive handles from other processes.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		ns      = flags.String("ns", "", "namespace to prefix the name with: global or local")
+		kind    = flags.String("type", "mutex", "type of the object: mutex, event, semaphore, timer or section")
+		access  = flags.String("access", "", "access mask for the duplicated handle, such as 0x00100000 (default: every right available to the caller)")
+		inherit = flags.Bool("inherit", false, "make the duplicated handle inheritable by the target process's children")
+		jsonOut = flags.Bool("json", false, "report the result as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	name, err := qualifyName(flags.Arg(0), *ns)
+	if err != nil {
+		return fail("duplicate", err)
+	}
+
+	pid, err := strconv.ParseUint(flags.Arg(1), 10, 32)
+	if err != nil {
+		return fail("duplicate", fmt.Errorf("invalid process ID %q", flags.Arg(1)))
+	}
+
+	desired := uint32(maximumAllowed)
+	if *access != "" {
+		mask, err := strconv.ParseUint(*access, 0, 32)
+		if err != nil {
+			return fail("duplicate", fmt.Errorf("invalid access mask %q", *access))
+		}
+		desired = uint32(mask)
+	}
+
+	source, err := openForDuplicate(name, *kind, desired)
+	if err != nil {
+		return fail("duplicate", err)
+	}
+	defer windows.CloseHandle(source)
+
+	target, err := windows.OpenProcess(windows.PROCESS_DUP_HANDLE, false, uint32(pid))
+	if err != nil {
+		return fail("duplicate", fmt.Errorf("failed to open process %d: %w", pid, err))
+	}
+	defer windows.CloseHandle(target)
+
+	var remote windows.Handle
+	if err := windows.DuplicateHandle(windows.CurrentProcess(), source, target, &remote, 0, *inherit, windows.DUPLICATE_SAME_ACCESS); err != nil {
+		return fail("duplicate", fmt.Errorf("failed to duplicate the %s %s into process %d: %w", name, *kind, pid, err))
+	}
+
+	if *jsonOut {
+		result := duplicateResult{
+			Name:   name,
+			Type:   *kind,
+			PID:    uint32(pid),
+			Handle: fmt.Sprintf("%#x", remote),
+		}
+		if *access != "" {
+			result.Access = fmt.Sprintf("%#08x", desired)
+		}
+		if err := writeJSON(result); err != nil {
+			return fail("duplicate", err)
+		}
+	} else {
+		fmt.Printf("%#x\n", remote)
+	}
+
+	return exitOK
+}
+
+// openForDuplicate opens an existing named object of the given kind with
+// the desired access rights.
+func openForDuplicate(name, kind string, access uint32) (windows.Handle, error) {
+	var (
+		h   syscall.Handle
+		err error
+	)
+	switch kind {
+	case "mutex":
+		var utf16Name *uint16
+		if utf16Name, err = windows.UTF16PtrFromString(name); err == nil {
+			var wh windows.Handle
+			wh, err = windows.OpenMutex(access, false, utf16Name)
+			h = syscall.Handle(wh)
+		}
+	case "event":
+		var utf16Name *uint16
+		if utf16Name, err = windows.UTF16PtrFromString(name); err == nil {
+			var wh windows.Handle
+			wh, err = windows.OpenEvent(access, false, utf16Name)
+			h = syscall.Handle(wh)
+		}
+	case "semaphore":
+		h, err = synchapi.OpenSemaphore(name, access)
+	case "timer":
+		h, err = synchapi.OpenWaitableTimer(name, access)
+	case "section":
+		h, err = memoryapi.OpenFileMapping(name, access, false)
+	default:
+		return 0, fmt.Errorf("unsupported object type %q", kind)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open the %s %s: %w", name, kind, err)
+	}
+	return windows.Handle(h), nil
+}
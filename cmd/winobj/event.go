@@ -0,0 +1,104 @@
+//go:build windows
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+func runEvent(args []string) int {
+	flags := flag.NewFlagSet("event", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj event [flags] <create|set|reset|pulse> <name>\n\nManipulates a named event.\n\n  create  creates the event and keeps it alive until interrupted\n  set     sets an existing event to the signaled state\n  reset   sets an existing event to the non-signaled state\n  pulse   sets and then resets an existing event, releasing current waiters\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		ns      = flags.String("ns", "", "namespace to prefix the name with: global or local")
+		sddl    = flags.String("sddl", "", "security descriptor for a created event, such as \"D:(A;;GA;;;WD)\"")
+		manual  = flags.Bool("manual", true, "create a manual-reset event instead of an auto-reset event")
+		initial = flags.Bool("set", false, "create the event in the signaled state")
+		timeout = flags.Duration("timeout", 0, "with create, close the event after this duration (0 waits for Ctrl+C)")
+		jsonOut = flags.Bool("json", false, "report the result as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitUsage
+	}
+	action := flags.Arg(0)
+
+	name, err := qualifyName(flags.Arg(1), *ns)
+	if err != nil {
+		return fail("event", err)
+	}
+
+	report := func(status string) {
+		if *jsonOut {
+			info := describe(name, "event")
+			info.Status = status
+			writeJSONLine(info)
+		} else {
+			fmt.Printf("The %s event was %s.\n", name, status)
+		}
+	}
+
+	if action == "create" {
+		event, existed, err := createEvent(name, *manual, *initial, *sddl)
+		if err != nil {
+			return fail("event", err)
+		}
+		defer windows.CloseHandle(event)
+
+		if existed {
+			report("opened")
+		} else {
+			report("created")
+		}
+
+		// The event only lives as long as a handle to it remains open, so
+		// keep it open until interrupted.
+		waitForInterrupt(*timeout)
+
+		report("closed")
+		return exitOK
+	}
+
+	var (
+		modify func(windows.Handle) error
+		status string
+	)
+	switch action {
+	case "set":
+		modify, status = windows.SetEvent, "set"
+	case "reset":
+		modify, status = windows.ResetEvent, "reset"
+	case "pulse":
+		modify, status = windows.PulseEvent, "pulsed"
+	default:
+		flags.Usage()
+		return exitUsage
+	}
+
+	utf16Name, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return fail("event", err)
+	}
+	event, err := windows.OpenEvent(windows.EVENT_MODIFY_STATE|windows.SYNCHRONIZE, false, utf16Name)
+	if err != nil {
+		return fail("event", fmt.Errorf("failed to open the %s event: %w", name, err))
+	}
+	defer windows.CloseHandle(event)
+
+	if err := modify(event); err != nil {
+		return fail("event", fmt.Errorf("failed to %s the %s event: %w", action, name, err))
+	}
+
+	report(status)
+
+	return exitOK
+}
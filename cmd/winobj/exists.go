@@ -0,0 +1,142 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// Results reported by the exists command.
+const (
+	statusExists       = "exists"
+	statusNotFound     = "not-found"
+	statusAccessDenied = "access-denied"
+	statusWrongType    = "wrong-type"
+)
+
+func runExists(args []string) int {
+	flags := flag.NewFlagSet("exists", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj exists [flags] <name>...\n\nChecks whether named objects exist.\n\nExit codes:\n  0  all of the objects exist\n  1  an error occurred\n  5  at least one object does not exist\n  6  at least one object exists but could not be opened\n  7  at least one object exists but is not of the requested type\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		kind    = flags.String("type", "any", "type the objects must have: mutex, event or any")
+		jsonOut = flags.Bool("json", false, "write the results as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	switch *kind {
+	case "any", "mutex", "event":
+	default:
+		return fail("exists", errors.New("the object type must be mutex, event or any"))
+	}
+
+	results := make([]objectInfo, 0, flags.NArg())
+	for _, name := range flags.Args() {
+		status, err := probe(name, *kind)
+		if err != nil {
+			return fail("exists", err)
+		}
+		result := objectInfo{Name: name, Status: status}
+		if *jsonOut {
+			if status == statusExists {
+				t := *kind
+				if t == "any" {
+					t = ""
+				}
+				result = describe(name, t)
+				result.Status = status
+			} else if session, ok := nameSession(name); ok {
+				result.Session = &session
+			}
+		}
+		results = append(results, result)
+	}
+
+	if *jsonOut {
+		if err := writeJSON(results); err != nil {
+			return fail("exists", err)
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(w, "STATUS\tNAME\n")
+		for _, result := range results {
+			fmt.Fprintf(w, "%s\t%s\n", result.Status, result.Name)
+		}
+		if err := w.Flush(); err != nil {
+			return fail("exists", err)
+		}
+	}
+
+	// Report the most significant failure in the exit code.
+	code := exitOK
+	for _, result := range results {
+		switch result.Status {
+		case statusNotFound:
+			return exitNotFound
+		case statusAccessDenied:
+			code = exitAccessDenied
+		case statusWrongType:
+			if code == exitOK {
+				code = exitWrongType
+			}
+		}
+	}
+	return code
+}
+
+// probe determines whether an object with the given name and kind exists.
+// If kind is "any", an object of any type satisfies the check.
+func probe(name, kind string) (status string, err error) {
+	var h windows.Handle
+	switch kind {
+	case "event":
+		var utf16Name *uint16
+		if utf16Name, err = windows.UTF16PtrFromString(name); err != nil {
+			return "", err
+		}
+		h, err = windows.OpenEvent(windows.SYNCHRONIZE, false, utf16Name)
+	default:
+		// Every named object shares the same namespace, so attempting to
+		// open a mutex is enough to tell whether an object of any type
+		// exists with the given name.
+		h, err = openMutexHandle(name)
+	}
+
+	switch {
+	case err == nil:
+		windows.CloseHandle(h)
+		return statusExists, nil
+	case errors.Is(err, windows.ERROR_FILE_NOT_FOUND):
+		return statusNotFound, nil
+	case errors.Is(err, windows.ERROR_ACCESS_DENIED):
+		return statusAccessDenied, nil
+	case errors.Is(err, windows.ERROR_INVALID_HANDLE):
+		// The name is in use by an object of a different type.
+		if kind == "any" {
+			return statusExists, nil
+		}
+		return statusWrongType, nil
+	default:
+		return "", fmt.Errorf("failed to check for %s: %w", name, err)
+	}
+}
+
+// openMutexHandle opens the named mutex with SYNCHRONIZE access.
+func openMutexHandle(name string) (windows.Handle, error) {
+	h, err := synchapi.OpenMutex(name, windows.SYNCHRONIZE)
+	return windows.Handle(h), err
+}
@@ -0,0 +1,87 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gentlemanautomaton/winobj/winobjdir"
+)
+
+// namespace is an object directory that holds named objects, along with
+// the prefix that produces Win32 names for the objects within it.
+type namespace struct {
+	dir    string
+	prefix string
+}
+
+// namespaces returns the object directories that correspond to the given
+// namespace selection, which may be "global", "session" or "all". If
+// session is negative, the current session is used.
+func namespaces(ns string, session int) ([]namespace, error) {
+	sessionID := uint32(session)
+	if session < 0 {
+		var err error
+		if sessionID, err = currentSession(); err != nil {
+			return nil, err
+		}
+	}
+
+	global := namespace{dir: winobjdir.GlobalDir, prefix: `Global\`}
+	local := namespace{dir: winobjdir.SessionDir(sessionID), prefix: sessionPrefix(sessionID)}
+
+	switch ns {
+	case "global":
+		return []namespace{global}, nil
+	case "session":
+		return []namespace{local}, nil
+	case "all":
+		if local.dir == global.dir {
+			return []namespace{global}, nil
+		}
+		return []namespace{global, local}, nil
+	default:
+		return nil, errors.New("the namespace must be global, session or all")
+	}
+}
+
+// objectFilter selects directory entries by type and name.
+type objectFilter struct {
+	types   map[string]bool // Lower case type names; nil matches all types
+	pattern string          // Lower case glob pattern; empty matches all names
+}
+
+// newObjectFilter returns a filter for the given comma-separated list of
+// types and case-insensitive glob pattern. Empty values match everything.
+func newObjectFilter(types, pattern string) (objectFilter, error) {
+	var filter objectFilter
+	if pattern != "" {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return filter, fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+		}
+		filter.pattern = strings.ToLower(pattern)
+	}
+	if types != "" {
+		filter.types = make(map[string]bool)
+		for _, t := range strings.Split(types, ",") {
+			filter.types[strings.ToLower(strings.TrimSpace(t))] = true
+		}
+	}
+	return filter, nil
+}
+
+// Match reports whether the filter matches the given directory entry.
+func (f objectFilter) Match(entry winobjdir.Entry) bool {
+	if f.types != nil && !f.types[typeName(entry.Type)] && !f.types[strings.ToLower(entry.Type)] {
+		return false
+	}
+	if f.pattern != "" {
+		if matched, _ := path.Match(f.pattern, strings.ToLower(entry.Name)); !matched {
+			return false
+		}
+	}
+	return true
+}
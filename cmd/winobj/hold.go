@@ -0,0 +1,134 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"golang.org/x/sys/windows"
+)
+
+func runHold(args []string) int {
+	flags := flag.NewFlagSet("hold", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj hold [flags] <name>\n\nAcquires a named mutex, or sets a named event, and holds it until\ninterrupted or until the timeout elapses.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		kind    = flags.String("type", "mutex", "type of object to hold: mutex or event")
+		timeout = flags.Duration("timeout", 0, "stop waiting for the object, or release it, after this duration (0 waits for Ctrl+C)")
+		jsonOut = flags.Bool("json", false, "report progress as JSON lines")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	name := flags.Arg(0)
+
+	// Stop waiting for the object, or stop holding it, when interrupted or
+	// when the timeout elapses.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	var (
+		release func() error
+		err     error
+	)
+	switch *kind {
+	case "mutex":
+		release, err = holdMutex(ctx, name)
+	case "event":
+		release, err = holdEvent(name)
+	default:
+		err = errors.New("the object type must be mutex or event")
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		if *jsonOut {
+			info := describe(name, *kind)
+			info.Status = "timeout"
+			writeJSONLine(info)
+		} else {
+			fmt.Fprintln(os.Stderr, "winobj hold: timed out")
+		}
+		return exitTimeout
+	}
+	if err != nil {
+		return fail("hold", err)
+	}
+
+	if *jsonOut {
+		info := describe(name, *kind)
+		info.Status = "held"
+		writeJSONLine(info)
+	} else {
+		fmt.Printf("Holding the %s %s.\n", name, *kind)
+	}
+
+	// Wait for an interrupt or for the timeout to elapse.
+	<-ctx.Done()
+
+	if err := release(); err != nil {
+		return fail("hold", err)
+	}
+
+	if *jsonOut {
+		info := describe(name, *kind)
+		info.Status = "released"
+		writeJSONLine(info)
+	} else {
+		fmt.Printf("Released the %s %s.\n", name, *kind)
+	}
+
+	return exitOK
+}
+
+// holdMutex creates or opens the named mutex and locks it, waiting until
+// it is available or ctx is done. It returns a function that unlocks and
+// closes the mutex.
+func holdMutex(ctx context.Context, name string) (release func() error, err error) {
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		return nil, err
+	}
+	// A mutex abandoned by its previous owner is still held.
+	if err := mutex.LockContext(ctx); err != nil && !errors.Is(err, winmutex.ErrAbandoned) {
+		mutex.Close()
+		return nil, err
+	}
+	return func() error {
+		mutex.Unlock()
+		return mutex.Close()
+	}, nil
+}
+
+// holdEvent creates or opens the named event and sets it. It returns a
+// function that resets and closes the event.
+func holdEvent(name string) (release func() error, err error) {
+	event, _, err := createEvent(name, true, false, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := windows.SetEvent(event); err != nil {
+		windows.CloseHandle(event)
+		return nil, fmt.Errorf("failed to set the %s event: %w", name, err)
+	}
+	return func() error {
+		err1 := windows.ResetEvent(event)
+		err2 := windows.CloseHandle(event)
+		return errors.Join(err1, err2)
+	}, nil
+}
@@ -0,0 +1,88 @@
+//go:build windows
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gentlemanautomaton/winobj/winobjdir"
+)
+
+func runList(args []string) int {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj list [flags]\n\nLists named objects in the global and session namespaces.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		ns      = flags.String("ns", "all", "namespace to list: global, session or all")
+		session = flags.Int("session", -1, "session whose namespace is listed (defaults to the current session)")
+		types   = flags.String("type", "", "comma-separated object types to include, such as mutex,event,semaphore,section")
+		pattern = flags.String("name", "", "case-insensitive glob pattern that object names must match")
+		jsonOut = flags.Bool("json", false, "write the objects as JSON, including their security")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	filter, err := newObjectFilter(*types, *pattern)
+	if err != nil {
+		return fail("list", err)
+	}
+
+	selected, err := namespaces(*ns, *session)
+	if err != nil {
+		return fail("list", err)
+	}
+
+	var objects []objectInfo
+	for _, namespace := range selected {
+		entries, err := winobjdir.List(namespace.dir)
+		if err != nil {
+			return fail("list", err)
+		}
+		for _, entry := range entries {
+			if !filter.Match(entry) {
+				continue
+			}
+			objects = append(objects, objectInfo{
+				Name: namespace.prefix + entry.Name,
+				Type: typeName(entry.Type),
+			})
+		}
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		return strings.ToLower(objects[i].Name) < strings.ToLower(objects[j].Name)
+	})
+
+	if *jsonOut {
+		for i := range objects {
+			objects[i] = describe(objects[i].Name, objects[i].Type)
+		}
+		if err := writeJSON(objects); err != nil {
+			return fail("list", err)
+		}
+		return exitOK
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "TYPE\tNAME\n")
+	for _, object := range objects {
+		fmt.Fprintf(w, "%s\t%s\n", object.Type, object.Name)
+	}
+	if err := w.Flush(); err != nil {
+		return fail("list", err)
+	}
+
+	return exitOK
+}
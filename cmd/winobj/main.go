@@ -0,0 +1,116 @@
+//go:build windows
+
+// Command winobj inspects and manipulates named kernel objects in the
+// Windows Object Manager namespace.
+//
+// Usage:
+//
+//	winobj <command> [flags] [arguments]
+//
+// Run "winobj help" for a list of commands, or "winobj <command> -h" for
+// the flags accepted by a command.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// Exit codes shared by all commands.
+const (
+	exitOK    = 0 // The command succeeded
+	exitError = 1 // The command failed
+	exitUsage = 2 // The command was invoked incorrectly
+
+	exitTimeout   = 3 // A wait timed out
+	exitAbandoned = 4 // A wait acquired a mutex that was abandoned by its owner
+
+	exitNotFound     = 5 // An object does not exist
+	exitAccessDenied = 6 // An object exists but could not be opened
+	exitWrongType    = 7 // An object exists but has a different type
+)
+
+// command is a winobj subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+// commands is the set of subcommands supported by winobj.
+var commands = []command{
+	{name: "list", summary: "list named objects in the global and session namespaces", run: runList},
+	{name: "hold", summary: "acquire a named mutex or set a named event until interrupted", run: runHold},
+	{name: "wait", summary: "wait until named objects are signaled or released", run: runWait},
+	{name: "exists", summary: "check whether named objects exist", run: runExists},
+	{name: "acl", summary: "print or replace the security descriptor of a named object", run: runACL},
+	{name: "owners", summary: "report the processes that hold handles to a named object", run: runOwners},
+	{name: "event", summary: "create, set, reset or pulse a named event", run: runEvent},
+	{name: "watch", summary: "report named objects as they are created and removed", run: runWatch},
+	{name: "shm", summary: "create, dump or write a named shared memory section", run: runSHM},
+	{name: "semaphore", summary: "create, acquire or release a named semaphore", run: runSemaphore},
+	{name: "timer", summary: "create, arm, cancel or wait on a named waitable timer", run: runTimer},
+	{name: "duplicate", summary: "duplicate a handle to a named object into another process", run: runDuplicate},
+	{name: "msi", summary: "wait until Windows Installer is idle", run: runMSI},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return exitUsage
+	}
+
+	name := args[0]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		usage(os.Stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(args[1:])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "winobj: unknown command %q\n\n", name)
+	usage(os.Stderr)
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: winobj <command> [flags] [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nRun \"winobj <command> -h\" for the flags accepted by a command.\n")
+}
+
+// fail prints an error message for the given command to stderr and returns
+// exitError.
+func fail(cmd string, err error) int {
+	fmt.Fprintf(os.Stderr, "winobj %s: %v\n", cmd, err)
+	return exitError
+}
+
+// waitForInterrupt blocks until the process is interrupted, such as by
+// Ctrl+C, or until the timeout elapses. If timeout is zero, it waits
+// indefinitely for an interrupt.
+func waitForInterrupt(timeout time.Duration) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	<-ctx.Done()
+}
@@ -0,0 +1,114 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmsi"
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+// msiResult is the machine-readable output of the msi command.
+type msiResult struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`              // idle or busy
+	Abandoned bool   `json:"abandoned,omitempty"` // The last installation ended without releasing the mutex
+	Waited    string `json:"waited"`
+}
+
+func runMSI(args []string) int {
+	flags := flag.NewFlagSet("msi", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj msi [flags]\n\nWaits until Windows Installer is idle, so that another installation can\nbegin. An installation is in progress while the %s mutex is\nheld. The mutex may exist while the installer is idle, so it is briefly\nacquired and immediately released to confirm that it is not held. It is\nnever created by this command.\n\nExit codes:\n  0  the installer is idle\n  1  an error occurred\n  3  the timeout elapsed while the installer was busy\n\nFlags:\n", winmsi.MutexName)
+		flags.PrintDefaults()
+	}
+	var (
+		timeout = flags.Duration("timeout", 0, "give up after this duration (0 waits forever)")
+		settle  = flags.Duration("settle", 0, "after the installer becomes idle, wait this long and confirm that it is still idle, to skip the gaps between chained installations")
+		jsonOut = flags.Bool("json", false, "write the result as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *timeout < 0 {
+		return fail("msi", errors.New("the timeout must not be negative"))
+	}
+
+	// Stop waiting when interrupted or when the timeout elapses.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	report := func(status string, abandoned bool) {
+		waited := time.Since(start).Round(time.Millisecond)
+		if *jsonOut {
+			writeJSON(msiResult{Name: winmsi.MutexName, Status: status, Abandoned: abandoned, Waited: waited.String()})
+			return
+		}
+		switch status {
+		case "idle":
+			fmt.Printf("Windows Installer is idle (waited %s).\n", waited)
+		default:
+			fmt.Fprintf(os.Stderr, "winobj msi: timed out after %s while Windows Installer was busy\n", waited)
+		}
+	}
+
+	for {
+		abandoned, err := winmutex.WaitForRelease(ctx, winmsi.MutexName, winmutex.WithoutPrefix())
+		if errors.Is(err, context.DeadlineExceeded) {
+			report("busy", false)
+			return exitTimeout
+		}
+		if err != nil {
+			return fail("msi", err)
+		}
+
+		if *settle <= 0 {
+			report("idle", abandoned)
+			return exitOK
+		}
+
+		// Chained installations release the mutex briefly between
+		// packages, so confirm that the installer remains idle. If the
+		// timeout elapses first, the installer is checked once more.
+		timer := time.NewTimer(*settle)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fail("msi", ctx.Err())
+			}
+		case <-timer.C:
+		}
+
+		busy, err := winmsi.InstallerBusy()
+		if err != nil {
+			return fail("msi", err)
+		}
+		if !busy {
+			report("idle", abandoned)
+			return exitOK
+		}
+		if ctx.Err() != nil {
+			report("busy", false)
+			return exitTimeout
+		}
+	}
+}
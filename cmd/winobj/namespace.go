@@ -0,0 +1,104 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// objectTypes maps the object manager's type names to the shorter names
+// used on the command line.
+var objectTypes = map[string]string{
+	"Mutant":       "mutex",
+	"Event":        "event",
+	"Semaphore":    "semaphore",
+	"Section":      "section",
+	"Timer":        "timer",
+	"Directory":    "directory",
+	"SymbolicLink": "symlink",
+	"Job":          "job",
+}
+
+// typeName returns the command line name for the given object manager type
+// name.
+func typeName(ntType string) string {
+	if name, ok := objectTypes[ntType]; ok {
+		return name
+	}
+	return strings.ToLower(ntType)
+}
+
+// currentSession returns the terminal services session ID of the current
+// process.
+func currentSession() (uint32, error) {
+	var session uint32
+	if err := windows.ProcessIdToSessionId(windows.GetCurrentProcessId(), &session); err != nil {
+		return 0, fmt.Errorf("failed to determine the current session: %w", err)
+	}
+	return session, nil
+}
+
+// sessionPrefix returns the Win32 name prefix for objects in the local
+// namespace of the given session.
+func sessionPrefix(session uint32) string {
+	return `Session\` + strconv.FormatUint(uint64(session), 10) + `\`
+}
+
+// nameSession returns the session whose local namespace holds the object
+// with the given Win32 name. It returns false if the object is in the
+// global namespace.
+func nameSession(name string) (session uint32, ok bool) {
+	switch {
+	case hasPrefixFold(name, `Global\`):
+		return 0, false
+	case hasPrefixFold(name, `Session\`):
+		rest := name[len(`Session\`):]
+		end := strings.IndexByte(rest, '\\')
+		if end < 0 {
+			return 0, false
+		}
+		id, err := strconv.ParseUint(rest[:end], 10, 32)
+		if err != nil {
+			return 0, false
+		}
+		return uint32(id), true
+	default:
+		// Names without a prefix, or with the Local\ prefix, are in the
+		// current session's namespace.
+		session, err := currentSession()
+		if err != nil {
+			return 0, false
+		}
+		return session, true
+	}
+}
+
+// hasPrefixFold reports whether s begins with prefix, ignoring case.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// qualifyName applies the prefix for the given namespace to name. The
+// namespace may be "global", "local" or empty, in which case the name is
+// returned unchanged.
+func qualifyName(name, ns string) (string, error) {
+	var prefix string
+	switch ns {
+	case "":
+		return name, nil
+	case "global":
+		prefix = `Global\`
+	case "local":
+		prefix = `Local\`
+	default:
+		return "", fmt.Errorf("unknown namespace %q: it must be global or local", ns)
+	}
+	if strings.Contains(name, `\`) {
+		return "", fmt.Errorf("the name %q already includes a namespace", name)
+	}
+	return prefix + name, nil
+}
@@ -0,0 +1,119 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// createEvent creates an event with the given name, or opens it if it
+// already exists, and reports whether it already existed.
+//
+// If sddl is not empty, it specifies the security descriptor of a newly
+// created event. The manual and initial arguments determine whether a
+// newly created event is manual-reset and whether it starts out signaled.
+//
+// It is the caller's responsibility to close the returned handle.
+func createEvent(name string, manual, initial bool, sddl string) (h windows.Handle, existed bool, err error) {
+	utf16Name, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, false, err
+	}
+
+	attrs, err := securityAttributes(sddl)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var bManual, bInitial uint32
+	if manual {
+		bManual = 1
+	}
+	if initial {
+		bInitial = 1
+	}
+
+	h, err = windows.CreateEvent(attrs, bManual, bInitial, utf16Name)
+	if err == windows.ERROR_ALREADY_EXISTS {
+		return h, true, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create the %s event: %w", name, err)
+	}
+	return h, false, nil
+}
+
+// securityAttributes returns security attributes holding the security
+// descriptor described by the given SDDL string. If sddl is empty, it
+// returns nil so that the default security descriptor is used.
+func securityAttributes(sddl string) (*windows.SecurityAttributes, error) {
+	if sddl == "" {
+		return nil, nil
+	}
+	sd, err := windows.SecurityDescriptorFromString(sddl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SDDL string %q: %w", sddl, err)
+	}
+	attrs := &windows.SecurityAttributes{SecurityDescriptor: sd}
+	attrs.Length = uint32(unsafe.Sizeof(*attrs))
+	return attrs, nil
+}
+
+// object is a named mutex or event that has been opened with SYNCHRONIZE
+// access, which is sufficient for waiting on it.
+type object struct {
+	name   string
+	kind   string // mutex or event
+	handle windows.Handle
+}
+
+// openObject opens an existing named object of the given kind. If kind is
+// "auto", it opens the object as a mutex or as an event, whichever matches
+// the type of the existing object.
+//
+// It is the caller's responsibility to close the returned object's handle.
+func openObject(name, kind string) (object, error) {
+	switch kind {
+	case "mutex":
+		h, err := synchapi.OpenMutex(name, windows.SYNCHRONIZE)
+		if err != nil {
+			return object{}, fmt.Errorf("failed to open the %s mutex: %w", name, err)
+		}
+		return object{name: name, kind: kind, handle: windows.Handle(h)}, nil
+	case "event":
+		utf16Name, err := windows.UTF16PtrFromString(name)
+		if err != nil {
+			return object{}, err
+		}
+		h, err := windows.OpenEvent(windows.SYNCHRONIZE, false, utf16Name)
+		if err != nil {
+			return object{}, fmt.Errorf("failed to open the %s event: %w", name, err)
+		}
+		return object{name: name, kind: kind, handle: h}, nil
+	case "auto":
+		// Opening an object as the wrong type fails with
+		// ERROR_INVALID_HANDLE, so try each supported type in turn.
+		obj, err := openObject(name, "mutex")
+		if errors.Is(err, windows.ERROR_INVALID_HANDLE) {
+			obj, err = openObject(name, "event")
+		}
+		if errors.Is(err, windows.ERROR_INVALID_HANDLE) {
+			return object{}, fmt.Errorf("the %s object is not a mutex or event", name)
+		}
+		return obj, err
+	default:
+		return object{}, fmt.Errorf("unsupported object type %q", kind)
+	}
+}
+
+// closeObjects closes the handles of the given objects.
+func closeObjects(objects []object) {
+	for _, obj := range objects {
+		windows.CloseHandle(obj.handle)
+	}
+}
@@ -0,0 +1,82 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// objectInfo is the machine-readable description of a named object that
+// is written by commands running with the -json flag.
+type objectInfo struct {
+	Name     string        `json:"name"`
+	Type     string        `json:"type,omitempty"`
+	Session  *uint32       `json:"session,omitempty"`
+	Status   string        `json:"status,omitempty"`
+	Security *securityInfo `json:"security,omitempty"`
+}
+
+// securityInfo summarizes the security descriptor of a named object.
+type securityInfo struct {
+	Owner string `json:"owner,omitempty"`
+	SDDL  string `json:"sddl"`
+}
+
+// describe returns a description of the named object with the given type.
+// The object's security is included if its security descriptor can be
+// read by the calling process.
+func describe(name, kind string) objectInfo {
+	info := objectInfo{
+		Name:     name,
+		Type:     kind,
+		Security: security(name),
+	}
+	if session, ok := nameSession(name); ok {
+		info.Session = &session
+	}
+	return info
+}
+
+// security returns a summary of the owner and discretionary access control
+// list of the named object, or nil if they cannot be read.
+func security(name string) *securityInfo {
+	sd, err := windows.GetNamedSecurityInfo(name, windows.SE_KERNEL_OBJECT, windows.OWNER_SECURITY_INFORMATION|windows.DACL_SECURITY_INFORMATION)
+	if err != nil {
+		return nil
+	}
+
+	info := &securityInfo{SDDL: sd.String()}
+	if owner, _, err := sd.Owner(); err == nil && owner != nil {
+		info.Owner = accountName(owner)
+	}
+	return info
+}
+
+// accountName returns the name of the account with the given security
+// identifier, or its string form if the account cannot be looked up.
+func accountName(sid *windows.SID) string {
+	account, domain, _, err := sid.LookupAccount("")
+	if err != nil {
+		return sid.String()
+	}
+	if domain != "" {
+		return domain + `\` + account
+	}
+	return account
+}
+
+// writeJSON writes v to stdout as indented JSON.
+func writeJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeJSONLine writes v to stdout as a single line of JSON, which is
+// suitable for streaming output.
+func writeJSONLine(v any) error {
+	return json.NewEncoder(os.Stdout).Encode(v)
+}
@@ -0,0 +1,117 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/gentlemanautomaton/winobj/winhandle"
+	"golang.org/x/sys/windows"
+)
+
+// ownerInfo describes a process that holds a handle to a named object.
+type ownerInfo struct {
+	PID    uint32 `json:"pid"`
+	Image  string `json:"image,omitempty"`
+	Handle uint64 `json:"handle"`
+	Access uint32 `json:"access"`
+}
+
+func runOwners(args []string) int {
+	flags := flag.NewFlagSet("owners", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj owners [flags] <name>\n\nReports the processes that hold handles to a named object. This usually\nrequires administrative privileges.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		kind    = flags.String("type", "auto", "type of the object: mutex, event or auto")
+		jsonOut = flags.Bool("json", false, "write the owners as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	name := flags.Arg(0)
+
+	obj, err := openObject(name, *kind)
+	if err != nil {
+		return fail("owners", err)
+	}
+	defer windows.CloseHandle(obj.handle)
+
+	handles, err := winhandle.SameObject(obj.handle)
+	if err != nil {
+		if errors.Is(err, winhandle.ErrObjectAddressUnavailable) {
+			err = fmt.Errorf("%w (try running as an administrator)", err)
+		}
+		return fail("owners", err)
+	}
+
+	owners := make([]ownerInfo, 0, len(handles))
+	for _, h := range handles {
+		owners = append(owners, ownerInfo{
+			PID:    h.ProcessID,
+			Image:  processImage(h.ProcessID),
+			Handle: uint64(h.Value),
+			Access: h.Access,
+		})
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].PID != owners[j].PID {
+			return owners[i].PID < owners[j].PID
+		}
+		return owners[i].Handle < owners[j].Handle
+	})
+
+	if *jsonOut {
+		type result struct {
+			objectInfo
+			Owners []ownerInfo `json:"owners"`
+		}
+		if err := writeJSON(result{objectInfo: describe(obj.name, obj.kind), Owners: owners}); err != nil {
+			return fail("owners", err)
+		}
+		return exitOK
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "PID\tIMAGE\tHANDLE\tACCESS\n")
+	for _, owner := range owners {
+		image := "-"
+		if owner.Image != "" {
+			image = filepath.Base(owner.Image)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%#x\t%#08x\n", owner.PID, image, owner.Handle, owner.Access)
+	}
+	if err := w.Flush(); err != nil {
+		return fail("owners", err)
+	}
+
+	return exitOK
+}
+
+// processImage returns the path of the executable image of the process
+// with the given ID, or an empty string if it cannot be determined.
+func processImage(pid uint32) string {
+	process, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(process)
+
+	var buffer [windows.MAX_LONG_PATH]uint16
+	size := uint32(len(buffer))
+	if err := windows.QueryFullProcessImageName(process, 0, &buffer[0], &size); err != nil {
+		return ""
+	}
+	return windows.UTF16ToString(buffer[:size])
+}
@@ -0,0 +1,144 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/gentlemanautomaton/winobj/winsemaphore"
+)
+
+// semaphoreResult is the machine-readable output of the semaphore command.
+type semaphoreResult struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Count    int    `json:"count,omitempty"`
+	Previous *int   `json:"previous,omitempty"`
+}
+
+func runSemaphore(args []string) int {
+	flags := flag.NewFlagSet("semaphore", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj semaphore [flags] <create|acquire|release> <name>\n\nManipulates a named semaphore.\n\n  create   creates the semaphore and keeps it alive until interrupted\n  acquire  decrements the count of an existing semaphore, waiting while it is zero\n  release  increments the count of an existing semaphore\n\nSemaphores are not owned by the process that acquires them, so counts\nacquired by this command remain acquired after it exits, for as long as\nthe semaphore exists.\n\nExit codes:\n  0  the command succeeded\n  1  an error occurred, such as the release exceeding the maximum count\n  3  the timeout elapsed before the count could be acquired\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		ns      = flags.String("ns", "", "namespace to prefix the name with: global or local")
+		initial = flags.Int("initial", 1, "with create, the initial count of the semaphore")
+		maximum = flags.Int("max", 1, "with create, the maximum count of the semaphore")
+		count   = flags.Int("count", 1, "with acquire or release, the amount to change the count by")
+		timeout = flags.Duration("timeout", 0, "with create, close the semaphore after this duration; with acquire, give up after this duration (0 waits forever)")
+		jsonOut = flags.Bool("json", false, "report the result as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitUsage
+	}
+	action := flags.Arg(0)
+
+	name, err := qualifyName(flags.Arg(1), *ns)
+	if err != nil {
+		return fail("semaphore", err)
+	}
+
+	report := func(result semaphoreResult) {
+		result.Name = name
+		if *jsonOut {
+			writeJSONLine(result)
+			return
+		}
+		switch {
+		case result.Previous != nil:
+			fmt.Printf("The %s semaphore was %s by %d from a count of %d.\n", name, result.Status, result.Count, *result.Previous)
+		case result.Count > 0:
+			fmt.Printf("The %s semaphore was %s by %d.\n", name, result.Status, result.Count)
+		default:
+			fmt.Printf("The %s semaphore was %s.\n", name, result.Status)
+		}
+	}
+
+	if action == "create" {
+		semaphore, existed, err := winsemaphore.Create(name, *initial, *maximum)
+		if err != nil {
+			return fail("semaphore", err)
+		}
+		defer semaphore.Close()
+
+		if existed {
+			report(semaphoreResult{Status: "opened"})
+		} else {
+			report(semaphoreResult{Status: "created"})
+		}
+
+		// The semaphore only lives as long as a handle to it remains open,
+		// so keep it open until interrupted.
+		waitForInterrupt(*timeout)
+
+		report(semaphoreResult{Status: "closed"})
+		return exitOK
+	}
+
+	if *count < 1 {
+		return fail("semaphore", errors.New("the count must be positive"))
+	}
+
+	switch action {
+	case "acquire", "release":
+	default:
+		flags.Usage()
+		return exitUsage
+	}
+
+	semaphore, err := winsemaphore.Open(name)
+	if err != nil {
+		return fail("semaphore", err)
+	}
+	defer semaphore.Close()
+
+	if action == "release" {
+		previous, err := semaphore.Release(*count)
+		if err != nil {
+			return fail("semaphore", err)
+		}
+		report(semaphoreResult{Status: "released", Count: *count, Previous: &previous})
+		return exitOK
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	// Acquire the count one unit at a time. If the whole count cannot be
+	// acquired, return the units that were.
+	for acquired := 0; acquired < *count; acquired++ {
+		if err := semaphore.Acquire(ctx); err != nil {
+			if acquired > 0 {
+				semaphore.Release(acquired)
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				if *jsonOut {
+					writeJSONLine(semaphoreResult{Name: name, Status: "timeout"})
+				} else {
+					fmt.Fprintln(os.Stderr, "winobj semaphore: timed out")
+				}
+				return exitTimeout
+			}
+			return fail("semaphore", err)
+		}
+	}
+
+	report(semaphoreResult{Status: "acquired", Count: *count})
+	return exitOK
+}
@@ -0,0 +1,246 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winobj/api/memoryapi"
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"golang.org/x/sys/windows"
+)
+
+// shmResult is the machine-readable output of the shm command.
+type shmResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status,omitempty"`
+	Size   int    `json:"size,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+	Data   string `json:"data,omitempty"` // Hexadecimal
+}
+
+func runSHM(args []string) int {
+	flags := flag.NewFlagSet("shm", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj shm [flags] <create|dump|write> <name> [data]\n\nManipulates a named shared memory section.\n\n  create  creates the section and keeps it alive until interrupted\n  dump    prints the contents of an existing section as hex\n  write   writes data into an existing section\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		ns      = flags.String("ns", "", "namespace to prefix the name with: global or local")
+		size    = flags.Int("size", 4096, "with create, the size of the section in bytes")
+		sddl    = flags.String("sddl", "", "with create, the security descriptor of the section")
+		timeout = flags.Duration("timeout", 0, "with create, close the section after this duration (0 waits for Ctrl+C)")
+		offset  = flags.Int("offset", 0, "with dump or write, the offset within the section")
+		length  = flags.Int("length", 0, "with dump, the number of bytes to print (0 prints to the end)")
+		hexData = flags.Bool("hex", false, "with write, interpret data as hexadecimal")
+		lock    = flags.String("lock", "", "name of a mutex to hold while reading or writing the section")
+		jsonOut = flags.Bool("json", false, "report the result as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() < 2 {
+		flags.Usage()
+		return exitUsage
+	}
+	action := flags.Arg(0)
+
+	name, err := qualifyName(flags.Arg(1), *ns)
+	if err != nil {
+		return fail("shm", err)
+	}
+
+	if action == "create" {
+		if flags.NArg() != 2 {
+			flags.Usage()
+			return exitUsage
+		}
+		section, existed, err := createSection(name, *size, *sddl)
+		if err != nil {
+			return fail("shm", err)
+		}
+		defer windows.CloseHandle(section)
+
+		status := "created"
+		if existed {
+			status = "opened"
+		}
+		if *jsonOut {
+			writeJSONLine(shmResult{Name: name, Status: status, Size: *size})
+		} else {
+			fmt.Printf("The %s section was %s.\n", name, status)
+		}
+
+		// The section only lives as long as a handle to it remains open,
+		// so keep it open until interrupted.
+		waitForInterrupt(*timeout)
+
+		if *jsonOut {
+			writeJSONLine(shmResult{Name: name, Status: "closed"})
+		} else {
+			fmt.Printf("The %s section was closed.\n", name)
+		}
+		return exitOK
+	}
+
+	var (
+		access uint32
+		data   []byte
+	)
+	switch action {
+	case "dump":
+		if flags.NArg() != 2 {
+			flags.Usage()
+			return exitUsage
+		}
+		access = windows.FILE_MAP_READ
+	case "write":
+		if flags.NArg() != 3 {
+			flags.Usage()
+			return exitUsage
+		}
+		access = windows.FILE_MAP_READ | windows.FILE_MAP_WRITE
+		data = []byte(flags.Arg(2))
+		if *hexData {
+			if data, err = hex.DecodeString(flags.Arg(2)); err != nil {
+				return fail("shm", fmt.Errorf("invalid hexadecimal data: %w", err))
+			}
+		}
+	default:
+		flags.Usage()
+		return exitUsage
+	}
+
+	// Hold the guard mutex, if any, while the section is accessed.
+	if *lock != "" {
+		mutex, err := winmutex.New(*lock)
+		if err != nil {
+			return fail("shm", err)
+		}
+		defer mutex.Close()
+		mutex.Lock()
+		defer mutex.Unlock()
+	}
+
+	view, err := mapSection(name, access)
+	if err != nil {
+		return fail("shm", err)
+	}
+	defer view.Close()
+
+	if *offset < 0 || *offset > len(view.data) {
+		return fail("shm", fmt.Errorf("the offset %d is outside the %d byte section", *offset, len(view.data)))
+	}
+
+	if action == "write" {
+		if *offset+len(data) > len(view.data) {
+			return fail("shm", fmt.Errorf("writing %d bytes at offset %d would exceed the %d byte section", len(data), *offset, len(view.data)))
+		}
+		copy(view.data[*offset:], data)
+		if *jsonOut {
+			writeJSON(shmResult{Name: name, Status: "written", Size: len(data), Offset: *offset})
+		} else {
+			fmt.Printf("Wrote %d bytes to the %s section at offset %d.\n", len(data), name, *offset)
+		}
+		return exitOK
+	}
+
+	contents := view.data[*offset:]
+	if *length > 0 && *length < len(contents) {
+		contents = contents[:*length]
+	}
+	if *jsonOut {
+		if err := writeJSON(shmResult{Name: name, Size: len(view.data), Offset: *offset, Data: hex.EncodeToString(contents)}); err != nil {
+			return fail("shm", err)
+		}
+		return exitOK
+	}
+
+	dumper := hex.Dumper(os.Stdout)
+	dumper.Write(contents)
+	if err := dumper.Close(); err != nil {
+		return fail("shm", err)
+	}
+
+	return exitOK
+}
+
+// createSection creates a section backed by the paging file with the
+// given name and size, or opens it if it already exists, and reports
+// whether it already existed.
+//
+// It is the caller's responsibility to close the returned handle.
+func createSection(name string, size int, sddl string) (h windows.Handle, existed bool, err error) {
+	if size <= 0 {
+		return 0, false, errors.New("the size of the section must be positive")
+	}
+
+	utf16Name, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, false, err
+	}
+
+	attrs, err := securityAttributes(sddl)
+	if err != nil {
+		return 0, false, err
+	}
+
+	h, err = windows.CreateFileMapping(windows.InvalidHandle, attrs, windows.PAGE_READWRITE, uint32(uint64(size)>>32), uint32(size), utf16Name)
+	if err == windows.ERROR_ALREADY_EXISTS {
+		return h, true, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create the %s section: %w", name, err)
+	}
+	return h, false, nil
+}
+
+// sectionView is a mapped view of an entire section.
+type sectionView struct {
+	handle  windows.Handle
+	address uintptr
+	data    []byte
+}
+
+// mapSection opens the existing section with the given name and maps a
+// view of all of it into memory with the given access rights.
+func mapSection(name string, access uint32) (*sectionView, error) {
+	h, err := memoryapi.OpenFileMapping(name, access, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the %s section: %w", name, err)
+	}
+	section := windows.Handle(h)
+
+	address, err := windows.MapViewOfFile(section, access, 0, 0, 0)
+	if err != nil {
+		windows.CloseHandle(section)
+		return nil, fmt.Errorf("failed to map the %s section: %w", name, err)
+	}
+
+	// Determine the size of the view, which is the size of the section
+	// rounded up to a whole number of pages.
+	var info windows.MemoryBasicInformation
+	if err := windows.VirtualQuery(address, &info, unsafe.Sizeof(info)); err != nil {
+		windows.UnmapViewOfFile(address)
+		windows.CloseHandle(section)
+		return nil, fmt.Errorf("failed to determine the size of the %s section: %w", name, err)
+	}
+
+	return &sectionView{
+		handle:  section,
+		address: address,
+		data:    unsafe.Slice(*(**byte)(unsafe.Pointer(&address)), info.RegionSize),
+	}, nil
+}
+
+// Close unmaps the view and closes the section handle.
+func (v *sectionView) Close() error {
+	err1 := windows.UnmapViewOfFile(v.address)
+	err2 := windows.CloseHandle(v.handle)
+	return errors.Join(err1, err2)
+}
@@ -0,0 +1,167 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/wintimer"
+)
+
+// timerResult is the machine-readable output of the timer command.
+type timerResult struct {
+	Name   string     `json:"name"`
+	Status string     `json:"status"`
+	Due    *time.Time `json:"due,omitempty"`
+	Period string     `json:"period,omitempty"`
+}
+
+func runTimer(args []string) int {
+	flags := flag.NewFlagSet("timer", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj timer [flags] <create|set|cancel|wait> <name>\n\nManipulates a named waitable timer.\n\n  create  creates the timer, arms it if -in or -at is given, and keeps it\n          alive until interrupted\n  set     arms an existing timer\n  cancel  disarms an existing timer\n  wait    waits until an existing timer becomes due\n\nExit codes:\n  0  the command succeeded\n  1  an error occurred\n  3  the timeout elapsed before the timer became due\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		ns      = flags.String("ns", "", "namespace to prefix the name with: global or local")
+		in      = flags.Duration("in", 0, "with create or set, the time until the timer becomes due")
+		at      = flags.String("at", "", "with create or set, the time at which the timer becomes due, in RFC 3339 format")
+		period  = flags.Duration("period", 0, "with create or set, the interval at which the timer repeats after it first becomes due")
+		wake    = flags.Bool("wake", false, "with create or set, wake the system from suspend when the timer becomes due")
+		timeout = flags.Duration("timeout", 0, "with create, close the timer after this duration; with wait, give up after this duration (0 waits forever)")
+		jsonOut = flags.Bool("json", false, "report the result as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitUsage
+	}
+	action := flags.Arg(0)
+
+	name, err := qualifyName(flags.Arg(1), *ns)
+	if err != nil {
+		return fail("timer", err)
+	}
+
+	switch action {
+	case "create", "set", "cancel", "wait":
+	default:
+		flags.Usage()
+		return exitUsage
+	}
+
+	// Determine when the timer should become due, if it is being armed.
+	var due time.Time
+	switch {
+	case *in != 0 && *at != "":
+		return fail("timer", errors.New("the -in and -at flags are mutually exclusive"))
+	case *in != 0:
+		due = time.Now().Add(*in)
+	case *at != "":
+		if due, err = time.Parse(time.RFC3339, *at); err != nil {
+			return fail("timer", fmt.Errorf("invalid due time: %w", err))
+		}
+	case action == "set":
+		return fail("timer", errors.New("the -in or -at flag is required to set a timer"))
+	case *period != 0:
+		due = time.Now().Add(*period)
+	}
+
+	report := func(status string) {
+		result := timerResult{Name: name, Status: status}
+		if status == "armed" {
+			result.Due = &due
+			if *period > 0 {
+				result.Period = period.String()
+			}
+		}
+		if *jsonOut {
+			writeJSONLine(result)
+			return
+		}
+		switch {
+		case status == "armed" && result.Period != "":
+			fmt.Printf("The %s timer was armed to become due at %s and every %s thereafter.\n", name, due.Format(time.RFC3339), result.Period)
+		case status == "armed":
+			fmt.Printf("The %s timer was armed to become due at %s.\n", name, due.Format(time.RFC3339))
+		case status == "due":
+			fmt.Printf("The %s timer became due.\n", name)
+		default:
+			fmt.Printf("The %s timer was %s.\n", name, status)
+		}
+	}
+
+	var timer *wintimer.Timer
+	if action == "create" {
+		timer, err = wintimer.New(name)
+	} else {
+		timer, err = wintimer.Open(name)
+	}
+	if err != nil {
+		return fail("timer", err)
+	}
+	defer timer.Close()
+
+	if !due.IsZero() && (action == "create" || action == "set") {
+		if *period > 0 {
+			err = timer.SetPeriodic(due, *period, *wake)
+		} else {
+			err = timer.Set(due, *wake)
+		}
+		if err != nil {
+			return fail("timer", err)
+		}
+	}
+
+	switch action {
+	case "create":
+		report("created")
+		if !due.IsZero() {
+			report("armed")
+		}
+
+		// The timer only lives as long as a handle to it remains open, so
+		// keep it open until interrupted.
+		waitForInterrupt(*timeout)
+
+		report("closed")
+	case "set":
+		report("armed")
+	case "cancel":
+		if err := timer.Cancel(); err != nil {
+			return fail("timer", err)
+		}
+		report("cancelled")
+	case "wait":
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+
+		if err := timer.Wait(ctx); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				if *jsonOut {
+					writeJSONLine(timerResult{Name: name, Status: "timeout"})
+				} else {
+					fmt.Fprintln(os.Stderr, "winobj timer: timed out")
+				}
+				return exitTimeout
+			}
+			return fail("timer", err)
+		}
+		report("due")
+	}
+
+	return exitOK
+}
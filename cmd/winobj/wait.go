@@ -0,0 +1,149 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// waitResult is the machine-readable outcome of the wait command.
+type waitResult struct {
+	Result  string       `json:"result"`
+	Objects []objectInfo `json:"objects"`
+}
+
+func runWait(args []string) int {
+	flags := flag.NewFlagSet("wait", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj wait [flags] <name>...\n\nWaits until named mutexes are released or named events are signaled.\nMutexes are released again as soon as they have been acquired.\n\nExit codes:\n  0  the wait succeeded\n  1  an error occurred\n  3  the timeout elapsed\n  4  a mutex was abandoned by its previous owner\n  5  an object does not exist\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		kind    = flags.String("type", "auto", "type of the objects: mutex, event or auto")
+		waitAny = flags.Bool("any", false, "wait until any one of the objects is signaled")
+		waitAll = flags.Bool("all", false, "wait until all of the objects are signaled at once (the default)")
+		timeout = flags.Duration("timeout", 0, "give up after this duration (0 waits forever)")
+		jsonOut = flags.Bool("json", false, "write the result as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *timeout < 0 {
+		return fail("wait", errors.New("the timeout must not be negative"))
+	}
+	if *waitAny && *waitAll {
+		return fail("wait", errors.New("the -any and -all flags are mutually exclusive"))
+	}
+	if flags.NArg() > synchapi.MaximumWaitObjects {
+		return fail("wait", fmt.Errorf("no more than %d objects can be waited on", synchapi.MaximumWaitObjects))
+	}
+
+	// Mutex ownership is bound to the thread that acquired it, so the wait
+	// and any subsequent release must happen on the same thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var objects []object
+	defer func() { closeObjects(objects) }()
+	for _, name := range flags.Args() {
+		obj, err := openObject(name, *kind)
+		if err != nil {
+			code := fail("wait", err)
+			if errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+				code = exitNotFound
+			}
+			return code
+		}
+		objects = append(objects, obj)
+	}
+
+	handles := make([]windows.Handle, len(objects))
+	for i := range objects {
+		handles[i] = objects[i].handle
+	}
+
+	milliseconds := uint32(windows.INFINITE)
+	if *timeout > 0 {
+		milliseconds = synchapi.WaitMilliseconds(*timeout)
+	}
+
+	event, err := windows.WaitForMultipleObjects(handles, !*waitAny, milliseconds)
+	if err != nil {
+		return fail("wait", err)
+	}
+
+	// Determine which objects were signaled.
+	var (
+		signaled  []object
+		abandoned bool
+	)
+	n := uint32(len(objects))
+	switch {
+	case event == synchapi.WaitTimeout:
+		if *jsonOut {
+			writeJSON(waitResult{Result: "timeout", Objects: []objectInfo{}})
+		} else {
+			fmt.Fprintln(os.Stderr, "winobj wait: timed out")
+		}
+		return exitTimeout
+	case event < windows.WAIT_OBJECT_0+n:
+		if *waitAny {
+			signaled = objects[event-windows.WAIT_OBJECT_0:][:1]
+		} else {
+			signaled = objects
+		}
+	case event >= windows.WAIT_ABANDONED && event < windows.WAIT_ABANDONED+n:
+		abandoned = true
+		if *waitAny {
+			signaled = objects[event-windows.WAIT_ABANDONED:][:1]
+		} else {
+			signaled = objects
+		}
+	default:
+		return fail("wait", fmt.Errorf("unexpected wait result: %#x", event))
+	}
+
+	// Release any mutexes that were acquired by the wait.
+	for _, obj := range signaled {
+		if obj.kind == "mutex" {
+			if err := windows.ReleaseMutex(obj.handle); err != nil {
+				return fail("wait", fmt.Errorf("failed to release the %s mutex: %w", obj.name, err))
+			}
+		}
+	}
+
+	result := waitResult{Result: "signaled"}
+	if abandoned {
+		result.Result = "abandoned"
+	}
+
+	if *jsonOut {
+		for _, obj := range signaled {
+			result.Objects = append(result.Objects, describe(obj.name, obj.kind))
+		}
+		if err := writeJSON(result); err != nil {
+			return fail("wait", err)
+		}
+	} else {
+		for _, obj := range signaled {
+			fmt.Println(obj.name)
+		}
+	}
+
+	if abandoned {
+		return exitAbandoned
+	}
+
+	return exitOK
+}
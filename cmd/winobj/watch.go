@@ -0,0 +1,120 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winobjdir"
+)
+
+// watchEvent is the machine-readable form of a namespace change.
+type watchEvent struct {
+	Time time.Time `json:"time"`
+	Op   string    `json:"op"`
+	Name string    `json:"name"`
+	Type string    `json:"type"`
+}
+
+func runWatch(args []string) int {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: winobj watch [flags]\n\nReports named objects as they are created and removed, until interrupted.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var (
+		ns       = flags.String("ns", "all", "namespace to watch: global, session or all")
+		session  = flags.Int("session", -1, "session whose namespace is watched (defaults to the current session)")
+		types    = flags.String("type", "", "comma-separated object types to include, such as mutex,event,semaphore,section")
+		pattern  = flags.String("name", "", "case-insensitive glob pattern that object names must match")
+		interval = flags.Duration("interval", 250*time.Millisecond, "how often the namespace is polled for changes")
+		jsonOut  = flags.Bool("json", false, "write each change as a line of JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *interval <= 0 {
+		return fail("watch", errors.New("the interval must be positive"))
+	}
+
+	filter, err := newObjectFilter(*types, *pattern)
+	if err != nil {
+		return fail("watch", err)
+	}
+
+	selected, err := namespaces(*ns, *session)
+	if err != nil {
+		return fail("watch", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Watch each namespace on its own goroutine, tagging the changes with
+	// the namespace they occurred in.
+	type change struct {
+		winobjdir.Change
+		prefix string
+	}
+	changes := make(chan change)
+	errs := make(chan error, len(selected))
+	for _, namespace := range selected {
+		go func() {
+			ch := make(chan winobjdir.Change)
+			go func() {
+				errs <- winobjdir.Watch(ctx, namespace.dir, *interval, ch)
+			}()
+			for {
+				select {
+				case c := <-ch:
+					select {
+					case changes <- change{Change: c, prefix: namespace.prefix}:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		select {
+		case c := <-changes:
+			if !filter.Match(c.Entry) {
+				continue
+			}
+			event := watchEvent{
+				Time: time.Now(),
+				Op:   c.Op.String(),
+				Name: c.prefix + c.Entry.Name,
+				Type: typeName(c.Entry.Type),
+			}
+			if *jsonOut {
+				if err := writeJSONLine(event); err != nil {
+					return fail("watch", err)
+				}
+			} else {
+				fmt.Printf("%s  %-8s %-10s %s\n", event.Time.Format(time.TimeOnly), event.Op, event.Type, event.Name)
+			}
+		case err := <-errs:
+			if ctx.Err() != nil {
+				return exitOK
+			}
+			return fail("watch", err)
+		}
+	}
+}
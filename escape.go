@@ -0,0 +1,92 @@
+package winobj
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf16"
+)
+
+// MaxEscapedLength is the maximum length of a name returned by EscapeName,
+// in UTF-16 code units. It leaves room for any namespace prefix, including
+// the longest possible Session\<n>\ prefix.
+const MaxEscapedLength = MaxNameLength - len(`Session\4294967295\`)
+
+// ValidateName returns an error if name is not a valid kernel object name,
+// such as when it is empty, contains a backslash or null character outside
+// of its namespace prefix, or exceeds MaxNameLength. It allows invalid
+// names to be reported before they are passed to the system, which
+// reports them with little explanation.
+//
+// It is equivalent to Name(name).Validate().
+func ValidateName(name string) error {
+	return Name(name).Validate()
+}
+
+// EscapeName maps s to a string that can be used as the base of a kernel
+// object name, such as a file path or a user-supplied identifier. The
+// mapping is deterministic, so processes that escape the same string
+// arrive at the same name, and distinct strings are mapped to distinct
+// names.
+//
+// Backslashes, null characters and percent signs are replaced with
+// percent-encoded equivalents, such as %5C for a backslash. If the result
+// would be longer than MaxEscapedLength, it is truncated and a hash of s
+// is appended to it.
+//
+// EscapeName does not add a namespace prefix. Use GlobalName, LocalName or
+// SessionName to add one. An empty string is returned unchanged.
+func EscapeName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '%':
+			b.WriteString("%25")
+		case '\\':
+			b.WriteString("%5C")
+		case 0:
+			b.WriteString("%00")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	escaped := b.String()
+
+	if utf16Length(escaped) <= MaxEscapedLength {
+		return escaped
+	}
+
+	// Truncate the name, leaving room for a separator and the hash, and
+	// taking care not to split a surrogate pair or an escape sequence.
+	sum := sha256.Sum256([]byte(s))
+	suffix := "~" + hex.EncodeToString(sum[:])[:hashLength]
+	limit := MaxEscapedLength - len(suffix)
+
+	var (
+		length int
+		end    int
+	)
+	for i, r := range escaped {
+		n := utf16.RuneLen(r)
+		if length+n > limit {
+			break
+		}
+		length += n
+		end = i + len(string(r))
+	}
+	truncated := escaped[:end]
+	if i := strings.LastIndexByte(truncated, '%'); i >= 0 && i > len(truncated)-3 {
+		truncated = truncated[:i]
+	}
+
+	return truncated + suffix
+}
+
+// utf16Length returns the length of s in UTF-16 code units.
+func utf16Length(s string) int {
+	var n int
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
@@ -0,0 +1,53 @@
+package winobj_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj"
+)
+
+func TestEscapeName(t *testing.T) {
+	tests := []struct {
+		Input string
+		Want  string
+	}{
+		{`MyApp`, `MyApp`},
+		{`C:\Program Files\MyApp`, `C:%5CProgram Files%5CMyApp`},
+		{`100%`, `100%25`},
+		{"a\x00b", `a%00b`},
+	}
+
+	for _, test := range tests {
+		if got := winobj.EscapeName(test.Input); got != test.Want {
+			t.Errorf("EscapeName(%q): got %q, want %q", test.Input, got, test.Want)
+		}
+	}
+}
+
+func TestEscapeNameLong(t *testing.T) {
+	long1 := strings.Repeat(`\`, 300) + "1"
+	long2 := strings.Repeat(`\`, 300) + "2"
+
+	escaped1 := winobj.EscapeName(long1)
+	escaped2 := winobj.EscapeName(long2)
+
+	if escaped1 == escaped2 {
+		t.Fatalf("EscapeName returned the same name for different strings: %s", escaped1)
+	}
+
+	for _, escaped := range []string{escaped1, escaped2} {
+		if len(escaped) > winobj.MaxEscapedLength {
+			t.Errorf("EscapeName returned a name of %d characters, which exceeds %d", len(escaped), winobj.MaxEscapedLength)
+		}
+		if strings.HasSuffix(strings.Split(escaped, "~")[0], "%") {
+			t.Errorf("EscapeName split an escape sequence: %s", escaped)
+		}
+		name, err := winobj.SessionName(4294967295, escaped)
+		if err != nil {
+			t.Errorf("EscapeName returned an invalid name: %v", err)
+		} else if err := winobj.ValidateName(string(name)); err != nil {
+			t.Errorf("ValidateName: %v", err)
+		}
+	}
+}
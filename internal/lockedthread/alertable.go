@@ -0,0 +1,42 @@
+//go:build windows
+
+package lockedthread
+
+import (
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// ID returns the system identifier of the locked operating system thread.
+func (t *Thread) ID() (id uint32) {
+	t.Run(func() {
+		id = windows.GetCurrentThreadId()
+	})
+	return id
+}
+
+// SleepAlertable parks the locked operating system thread in an alertable
+// state for up to d, so that any asynchronous procedure calls (APCs)
+// queued to it are run. The sleep ends early once one or more APCs have
+// run. It reports whether any APCs were run.
+func (t *Thread) SleepAlertable(d time.Duration) (ran bool) {
+	ms := uint32(min(max(d, 0)/time.Millisecond, windows.INFINITE-1))
+	t.Run(func() {
+		ran = synchapi.SleepEx(ms, true) == synchapi.WaitIOCompletion
+	})
+	return ran
+}
+
+// DrainAPCs runs every asynchronous procedure call (APC) that is queued to
+// the locked operating system thread, without waiting for more to arrive.
+// It reports whether any APCs were run.
+func (t *Thread) DrainAPCs() (ran bool) {
+	t.Run(func() {
+		for synchapi.SleepEx(0, true) == synchapi.WaitIOCompletion {
+			ran = true
+		}
+	})
+	return ran
+}
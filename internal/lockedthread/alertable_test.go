@@ -0,0 +1,63 @@
+//go:build windows
+
+package lockedthread_test
+
+import (
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/api/processthreadsapi"
+	"github.com/gentlemanautomaton/winobj/internal/lockedthread"
+	"golang.org/x/sys/windows"
+)
+
+var apcCount atomic.Int32
+
+var apcCallback = windows.NewCallback(func(data uintptr) uintptr {
+	apcCount.Add(int32(data))
+	return 0
+})
+
+func TestThreadDrainAPCs(t *testing.T) {
+	thread := lockedthread.New()
+	defer thread.Close()
+
+	handle, err := windows.OpenThread(windows.THREAD_SET_CONTEXT, false, thread.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer windows.CloseHandle(handle)
+
+	if thread.DrainAPCs() {
+		t.Fatal("DrainAPCs reported that APCs ran when none were queued")
+	}
+
+	apcCount.Store(0)
+	for range 3 {
+		if err := processthreadsapi.QueueUserAPC(apcCallback, syscall.Handle(handle), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !thread.DrainAPCs() {
+		t.Fatal("DrainAPCs reported that no APCs ran when three were queued")
+	}
+	if n := apcCount.Load(); n != 3 {
+		t.Fatalf("%d APCs ran when 3 should have run", n)
+	}
+}
+
+func TestThreadSleepAlertable(t *testing.T) {
+	thread := lockedthread.New()
+	defer thread.Close()
+
+	start := time.Now()
+	if thread.SleepAlertable(20 * time.Millisecond) {
+		t.Fatal("SleepAlertable reported that APCs ran when none were queued")
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Fatalf("SleepAlertable returned after %s when it should have slept for 20ms", elapsed)
+	}
+}
@@ -0,0 +1,106 @@
+package lockedthread
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrLimitReached is returned by Get when the limit on locked operating
+// system threads has been reached and the limit is configured to fail
+// rather than wait.
+var ErrLimitReached = errors.New("lockedthread: the limit on locked operating system threads has been reached")
+
+// budget tracks the number of locked operating system threads that are
+// currently running.
+var budget = newLimiter()
+
+type limiter struct {
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	max     int  // Zero means unlimited
+	wait    bool // Wait for a thread instead of failing
+	active  int
+	threads map[*Thread]struct{}
+	idle    []*Thread // Pooled threads, which count toward active
+}
+
+func newLimiter() *limiter {
+	l := &limiter{threads: make(map[*Thread]struct{})}
+	l.cond = sync.NewCond(&l.mutex)
+	return l
+}
+
+// SetLimit sets the maximum number of locked operating system threads that
+// may be running at once. A max of zero removes the limit.
+//
+// If wait is true, calls to Get block until a thread is available.
+// Otherwise they return ErrLimitReached.
+//
+// Lowering the limit closes idle threads in the pool as needed to honor
+// it, but does not affect threads that are in use.
+func SetLimit(max int, wait bool) {
+	budget.mutex.Lock()
+	budget.max = max
+	budget.wait = wait
+	var excess []*Thread
+	for max > 0 && budget.active-len(excess) > max && len(budget.idle) > 0 {
+		last := len(budget.idle) - 1
+		excess = append(excess, budget.idle[last])
+		budget.idle = budget.idle[:last]
+	}
+	budget.cond.Broadcast()
+	budget.mutex.Unlock()
+
+	for _, t := range excess {
+		t.Close()
+	}
+}
+
+// Limit returns the current limit on locked operating system threads and
+// the number of threads that are currently running.
+func Limit() (max, active int) {
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	return budget.max, budget.active
+}
+
+// PingAll pings every thread that is currently running and returns an
+// error describing each thread that did not respond before ctx was
+// cancelled.
+func PingAll(ctx context.Context) error {
+	budget.mutex.Lock()
+	threads := make([]*Thread, 0, len(budget.threads))
+	for t := range budget.threads {
+		threads = append(threads, t)
+	}
+	budget.mutex.Unlock()
+
+	var errs []error
+	for i, t := range threads {
+		if err := t.Ping(ctx); err != nil && err != ErrClosed {
+			errs = append(errs, fmt.Errorf("lockedthread: thread %d of %d is unresponsive: %w", i+1, len(threads), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// register records a thread that has been started.
+func (l *limiter) register(t *Thread) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.threads[t] = struct{}{}
+}
+
+// release returns a thread to the budget.
+func (l *limiter) release(t *Thread) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.threads, t)
+	l.active--
+	l.cond.Signal()
+}
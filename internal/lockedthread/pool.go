@@ -0,0 +1,110 @@
+package lockedthread
+
+import "context"
+
+// maxIdle is the maximum number of idle threads retained by the pool.
+const maxIdle = 4
+
+// Get returns an idle thread from the pool, or starts a new thread if none
+// are idle. If no threads are idle and the limit set by SetLimit has been
+// reached, it either waits for a thread to become available or returns
+// ErrLimitReached, depending on how the limit was configured. If it waits,
+// it stops waiting and returns ctx.Err() when ctx is done.
+//
+// The caller should return the thread with Put when finished with it.
+func Get(ctx context.Context) (*Thread, error) {
+	var stop func() bool
+
+	budget.mutex.Lock()
+	for {
+		if n := len(budget.idle); n > 0 {
+			t := budget.idle[n-1]
+			budget.idle = budget.idle[:n-1]
+			budget.mutex.Unlock()
+			stopWaking(stop)
+			return t, nil
+		}
+		if budget.max == 0 || budget.active < budget.max {
+			break
+		}
+		if !budget.wait {
+			budget.mutex.Unlock()
+			stopWaking(stop)
+			return nil, ErrLimitReached
+		}
+		if err := ctx.Err(); err != nil {
+			// Pass on any signal this waiter may have consumed.
+			budget.cond.Signal()
+			budget.mutex.Unlock()
+			stopWaking(stop)
+			return nil, err
+		}
+		if stop == nil {
+			// Wake the waiters when ctx is done, so that this one can
+			// observe it.
+			stop = context.AfterFunc(ctx, func() {
+				budget.mutex.Lock()
+				budget.cond.Broadcast()
+				budget.mutex.Unlock()
+			})
+		}
+		budget.cond.Wait()
+	}
+	budget.active++
+	budget.mutex.Unlock()
+	stopWaking(stop)
+
+	return start(), nil
+}
+
+// TryGet returns a thread in the same way as Get, except that it never
+// waits. If the limit set by SetLimit has been reached and is configured
+// to wait, it returns a nil thread and a nil error. If the limit is
+// configured to fail, it returns ErrLimitReached.
+func TryGet() (*Thread, error) {
+	budget.mutex.Lock()
+	if n := len(budget.idle); n > 0 {
+		t := budget.idle[n-1]
+		budget.idle = budget.idle[:n-1]
+		budget.mutex.Unlock()
+		return t, nil
+	}
+	if budget.max != 0 && budget.active >= budget.max {
+		wait := budget.wait
+		budget.mutex.Unlock()
+		if wait {
+			return nil, nil
+		}
+		return nil, ErrLimitReached
+	}
+	budget.active++
+	budget.mutex.Unlock()
+
+	return start(), nil
+}
+
+// stopWaking stops a function registered by Get to wake waiters, if any.
+func stopWaking(stop func() bool) {
+	if stop != nil {
+		stop()
+	}
+}
+
+// Put returns a thread obtained from Get to the pool. If the pool is full,
+// or if the thread would exceed a limit that was lowered while it was in
+// use, the thread is closed instead.
+//
+// The caller must not use the thread after returning it, and must not
+// leave any thread-affine state behind on it, such as mutex ownership.
+func Put(t *Thread) {
+	budget.mutex.Lock()
+	if len(budget.idle) < maxIdle && (budget.max == 0 || budget.active <= budget.max) {
+		budget.idle = append(budget.idle, t)
+		budget.cond.Signal()
+		budget.mutex.Unlock()
+		return
+	}
+	budget.mutex.Unlock()
+
+	t.Close()
+}
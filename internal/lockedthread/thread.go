@@ -1,31 +1,55 @@
 package lockedthread
 
 import (
+	"context"
+	"errors"
 	"runtime"
-	"sync"
 )
 
+// ErrClosed is returned by Ping when the thread has been closed.
+var ErrClosed = errors.New("lockedthread: the thread has been closed")
+
+// command is a function to be run on a locked thread, either fn or
+// fnArg(arg).
 type command struct {
-	fn   func()
-	done chan<- struct{}
+	fn    func()
+	fnArg func(any)
+	arg   any
 }
 
 // Thread facilitates execution of functions on an operating system thread
 // that is locked, ensuring that all functions execute on the same thread.
 type Thread struct {
-	mutex sync.Mutex
+	lock  chan struct{} // Holds a token while a command is being run
 	cmds  chan<- command
+	reply <-chan struct{} // Receives a value when a command completes
 	done  <-chan struct{}
 }
 
 // New returns a new Thread that allows commands to be executed on a locked
 // operating system thread.
 //
+// New counts toward the limit set by SetLimit, but it does not enforce it.
+// Use Get to respect the limit.
+//
 // It is the caller's responsibility to close the thread when finished with
 // it.
 func New() *Thread {
-	// Prepare command and completion channels.
+	budget.mutex.Lock()
+	budget.active++
+	budget.mutex.Unlock()
+
+	return start()
+}
+
+// start launches a locked operating system thread. The caller must have
+// already counted it against the budget.
+func start() *Thread {
+	// Prepare command and completion channels. Commands are run one at a
+	// time, so a single reply channel is reused for all of them, which
+	// keeps each round trip to the thread free of allocations.
 	cmds := make(chan command)
+	reply := make(chan struct{})
 	done := make(chan struct{})
 
 	// Launch a goroutine that will be locked to a system thread and will be
@@ -41,45 +65,87 @@ func New() *Thread {
 		// Execute each command received via the command channel, until the
 		// channel is closed.
 		for cmd := range cmds {
-			func(cmd command) {
-				defer close(cmd.done) // Signal completion of the command
-				cmd.fn()
-			}(cmd)
+			func() {
+				defer func() { reply <- struct{}{} }() // Signal completion of the command
+				if cmd.fnArg != nil {
+					cmd.fnArg(cmd.arg)
+				} else {
+					cmd.fn()
+				}
+			}()
 		}
 	}(cmds)
 
 	// Return a thread object that is capable of sendind commands to the
 	// locked OS thread.
-	return &Thread{
-		cmds: cmds,
-		done: done,
+	t := &Thread{
+		lock:  make(chan struct{}, 1),
+		cmds:  cmds,
+		reply: reply,
+		done:  done,
 	}
+	budget.register(t)
+	return t
 }
 
 // Run executes the given function on the locked operating system thread.
 func (t *Thread) Run(f func()) {
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
+	t.run(command{fn: f}, "Run")
+}
+
+// RunArg executes f(arg) on the locked operating system thread. When f is
+// a top-level function and arg is a pointer, no allocations are needed, so
+// it suits hot paths where Run would allocate a closure for each call.
+func (t *Thread) RunArg(f func(arg any), arg any) {
+	t.run(command{fnArg: f, arg: arg}, "RunArg")
+}
+
+// run sends cmd to the locked operating system thread and waits for it to
+// complete.
+func (t *Thread) run(cmd command, method string) {
+	t.lock <- struct{}{}
+	defer func() { <-t.lock }()
 
 	// Panic if the thread has already been closed.
 	if t.cmds == nil {
-		panic("lockedthread: Thread.Run() was called on a thread that has been closed")
+		panic("lockedthread: Thread." + method + "() was called on a thread that has been closed")
 	}
 
-	// Prepare a channel that will be closed upon completion of the command.
-	done := make(chan struct{})
-
 	// Send the command to the OS thread.
-	t.cmds <- command{fn: f, done: done}
+	t.cmds <- cmd
 
 	// Wait for the command to be completed.
-	<-done
+	<-t.reply
+}
+
+// Ping verifies that the thread is responsive by running a no-op on it.
+// If the thread is busy running another function and does not become
+// available before ctx is cancelled, it returns ctx.Err(). If the thread
+// has been closed, it returns ErrClosed.
+func (t *Thread) Ping(ctx context.Context) error {
+	select {
+	case t.lock <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-t.lock }()
+
+	if t.cmds == nil {
+		return ErrClosed
+	}
+
+	// The thread is idle, so the no-op will be accepted immediately and
+	// will complete without delay.
+	t.cmds <- command{fn: func() {}}
+	<-t.reply
+
+	return nil
 }
 
 // Close stops the associated OS thread and releases any system resources.
 func (t *Thread) Close() error {
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
+	t.lock <- struct{}{}
+	defer func() { <-t.lock }()
 
 	// Check whether the thread has already been closed.
 	if t.cmds == nil {
@@ -94,7 +160,11 @@ func (t *Thread) Close() error {
 
 	// Mark the thread as closed.
 	t.cmds = nil
+	t.reply = nil
 	t.done = nil
 
+	// Return the thread to the budget.
+	budget.release(t)
+
 	return nil
 }
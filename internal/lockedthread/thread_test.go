@@ -73,3 +73,30 @@ func TestThreadConcurrent(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkThreadRun(b *testing.B) {
+	thread := lockedthread.New()
+	defer thread.Close()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		thread.Run(func() {})
+	}
+}
+
+type benchmarkCounter struct{ n int }
+
+func incrementCounter(arg any) {
+	arg.(*benchmarkCounter).n++
+}
+
+func BenchmarkThreadRunArg(b *testing.B) {
+	thread := lockedthread.New()
+	defer thread.Close()
+
+	var counter benchmarkCounter
+	b.ReportAllocs()
+	for b.Loop() {
+		thread.RunArg(incrementCounter, &counter)
+	}
+}
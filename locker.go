@@ -0,0 +1,46 @@
+package winobj
+
+import (
+	"context"
+	"time"
+)
+
+// Locker is implemented by locks that can be acquired on behalf of a
+// context.
+//
+// Applications that depend on Locker rather than on a concrete lock type
+// can switch between the system locks provided by this module and locks
+// provided by other packages, such as distributed locks backed by a
+// remote service.
+type Locker interface {
+	// Acquire blocks until the lock has been acquired or ctx is cancelled.
+	// When successful, it returns a function that releases the lock. The
+	// release function must be called exactly once.
+	Acquire(ctx context.Context) (release func(), err error)
+}
+
+// LockObserver receives notifications about attempts to acquire a lock,
+// so that wait times and contention can be measured without wrapping
+// every call site. It is supplied to locks with WithObserver.
+//
+// Its methods are called synchronously by the goroutine that is acquiring
+// the lock, so they should return quickly. The name identifies the lock,
+// and is empty for unnamed locks.
+type LockObserver interface {
+	// LockWaiting is called when a goroutine begins to wait for the lock.
+	LockWaiting(name string)
+
+	// LockAcquired is called when the lock is acquired, with the time
+	// that was spent waiting for it.
+	LockAcquired(name string, waited time.Duration)
+
+	// LockContended is called when an attempt to acquire the lock fails
+	// because it is held by another owner, such as when a call to TryLock
+	// fails or a timed wait expires.
+	LockContended(name string)
+
+	// LockAbandoned is called when the lock is acquired after its
+	// previous owner exited without releasing it. It is called before
+	// LockAcquired.
+	LockAbandoned(name string)
+}
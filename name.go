@@ -0,0 +1,208 @@
+package winobj
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// MaxNameLength is the maximum length of an object name, in UTF-16 code
+// units, including its namespace prefix. It is one less than MAX_PATH to
+// leave room for a null terminator.
+const MaxNameLength = 259
+
+// MaxObjectPathLength is the maximum length of an object manager path, in
+// UTF-16 code units.
+const MaxObjectPathLength = 32767
+
+// Namespace identifies the kernel object namespace that a name refers to.
+type Namespace int
+
+// Kernel object namespaces.
+const (
+	DefaultNamespace    Namespace = iota // No prefix, which resolves to the caller's session
+	GlobalNamespace                      // The Global\ prefix
+	LocalNamespace                       // The Local\ prefix, for the caller's session
+	SessionNamespace                     // The Session\<n>\ prefix, for a specific session
+	ObjectPathNamespace                  // A full object manager path, such as \BaseNamedObjects\<name>
+)
+
+// String returns a string representation of the namespace.
+func (ns Namespace) String() string {
+	switch ns {
+	case DefaultNamespace:
+		return "default"
+	case GlobalNamespace:
+		return "global"
+	case LocalNamespace:
+		return "local"
+	case SessionNamespace:
+		return "session"
+	case ObjectPathNamespace:
+		return "object path"
+	default:
+		return "unknown"
+	}
+}
+
+// ObjectName is a constraint satisfied by plain strings and by Name. It
+// allows constructors throughout the module to accept either form.
+type ObjectName interface {
+	~string
+}
+
+// Name is the name of a kernel object, including its optional namespace
+// prefix, such as `Global\MyApp-Lock` or `Session\2\MyApp-Lock`.
+//
+// A name that begins with a backslash is a full object manager path, such
+// as `\BaseNamedObjects\MyApp-Lock` or `\Sessions\3\BaseNamedObjects\MyApp-Lock`.
+// It identifies the directory of the object precisely, which is useful to
+// services and tools that work across sessions.
+//
+// A Name can be converted to and from a string directly. Use ParseName to
+// obtain a Name that is known to be valid.
+type Name string
+
+// ParseName parses and validates s as a kernel object name.
+func ParseName(s string) (Name, error) {
+	name := Name(s)
+	if err := name.Validate(); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// GlobalName returns base as a name in the global namespace, such as
+// `Global\MyApp-Lock`. It returns an error if base is not a valid name or
+// already includes a namespace prefix.
+func GlobalName(base string) (Name, error) {
+	return prefixedName(`Global\`, base)
+}
+
+// LocalName returns base as a name in the local namespace of the caller's
+// session, such as `Local\MyApp-Lock`. It returns an error if base is not
+// a valid name or already includes a namespace prefix.
+func LocalName(base string) (Name, error) {
+	return prefixedName(`Local\`, base)
+}
+
+// SessionName returns base as a name in the namespace of the session with
+// the given ID, such as `Session\2\MyApp-Lock`. It returns an error if
+// base is not a valid name or already includes a namespace prefix.
+func SessionName(session uint32, base string) (Name, error) {
+	return prefixedName(fmt.Sprintf(`Session\%d\`, session), base)
+}
+
+// prefixedName returns base with the given namespace prefix, after
+// verifying that base does not include a prefix of its own.
+func prefixedName(prefix, base string) (Name, error) {
+	if ns := Name(base).Namespace(); ns != DefaultNamespace {
+		return "", fmt.Errorf("winobj: the name \"%s\" already has a %s namespace prefix", base, ns)
+	}
+	return ParseName(prefix + base)
+}
+
+// Namespace returns the namespace identified by the name's prefix.
+func (n Name) Namespace() Namespace {
+	ns, _, _, _ := n.parse()
+	return ns
+}
+
+// Session returns the session ID of a name in the session namespace. If
+// the name is not in the session namespace, or if its session ID is
+// invalid, ok is false.
+func (n Name) Session() (id uint32, ok bool) {
+	ns, id, _, err := n.parse()
+	if ns != SessionNamespace || err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// Base returns the name without its namespace prefix. For an object
+// manager path, it returns the final element of the path.
+func (n Name) Base() string {
+	_, _, base, _ := n.parse()
+	return base
+}
+
+// String returns the name, including its namespace prefix.
+func (n Name) String() string {
+	return string(n)
+}
+
+// Validate returns an error if n is not a valid kernel object name.
+func (n Name) Validate() error {
+	ns, _, base, err := n.parse()
+	if err != nil {
+		return err
+	}
+	if ns == ObjectPathNamespace {
+		return n.validatePath()
+	}
+
+	switch {
+	case base == "":
+		return fmt.Errorf("winobj: the name \"%s\" is empty", n)
+	case strings.ContainsRune(base, '\\'):
+		return fmt.Errorf("winobj: the name \"%s\" contains a backslash", n)
+	case strings.ContainsRune(base, 0):
+		return fmt.Errorf("winobj: the name \"%s\" contains a null character", n)
+	}
+
+	if length := len(utf16.Encode([]rune(string(n)))); length > MaxNameLength {
+		return fmt.Errorf("winobj: the name \"%s\" is %d characters long, which exceeds the %d character limit", n, length, MaxNameLength)
+	}
+
+	return nil
+}
+
+// validatePath returns an error if n is not a valid object manager path.
+func (n Name) validatePath() error {
+	s := string(n)
+	switch {
+	case strings.Contains(s, `\\`) || strings.HasSuffix(s, `\`):
+		return fmt.Errorf("winobj: the object path \"%s\" has an empty element", n)
+	case strings.ContainsRune(s, 0):
+		return fmt.Errorf("winobj: the object path \"%s\" contains a null character", n)
+	}
+
+	if length := len(utf16.Encode([]rune(s))); length > MaxObjectPathLength {
+		return fmt.Errorf("winobj: the object path \"%s\" is %d characters long, which exceeds the %d character limit", n, length, MaxObjectPathLength)
+	}
+
+	return nil
+}
+
+// parse splits n into its namespace, session ID and base name. Namespace
+// prefixes are matched without regard to case, as they are by Windows.
+func (n Name) parse() (ns Namespace, session uint32, base string, err error) {
+	s := string(n)
+	switch {
+	case strings.HasPrefix(s, `\`):
+		return ObjectPathNamespace, 0, s[strings.LastIndexByte(s, '\\')+1:], nil
+	case hasPrefixFold(s, `Global\`):
+		return GlobalNamespace, 0, s[len(`Global\`):], nil
+	case hasPrefixFold(s, `Local\`):
+		return LocalNamespace, 0, s[len(`Local\`):], nil
+	case hasPrefixFold(s, `Session\`):
+		rest := s[len(`Session\`):]
+		id, base, found := strings.Cut(rest, `\`)
+		if !found {
+			return SessionNamespace, 0, rest, fmt.Errorf("winobj: the name \"%s\" is missing a backslash after its session ID", s)
+		}
+		parsed, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			return SessionNamespace, 0, base, fmt.Errorf("winobj: the name \"%s\" has an invalid session ID: %w", s, err)
+		}
+		return SessionNamespace, uint32(parsed), base, nil
+	default:
+		return DefaultNamespace, 0, s, nil
+	}
+}
+
+// hasPrefixFold reports whether s begins with prefix, ignoring case.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,104 @@
+package winobj_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj"
+)
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Namespace winobj.Namespace
+		Session   uint32
+		Base      string
+	}{
+		{`MyApp-Lock`, winobj.DefaultNamespace, 0, `MyApp-Lock`},
+		{`Global\MyApp-Lock`, winobj.GlobalNamespace, 0, `MyApp-Lock`},
+		{`global\MyApp-Lock`, winobj.GlobalNamespace, 0, `MyApp-Lock`},
+		{`Local\MyApp-Lock`, winobj.LocalNamespace, 0, `MyApp-Lock`},
+		{`Session\2\MyApp-Lock`, winobj.SessionNamespace, 2, `MyApp-Lock`},
+		{`\BaseNamedObjects\MyApp-Lock`, winobj.ObjectPathNamespace, 0, `MyApp-Lock`},
+		{`\Sessions\3\BaseNamedObjects\MyApp-Lock`, winobj.ObjectPathNamespace, 0, `MyApp-Lock`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			name, err := winobj.ParseName(test.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ns := name.Namespace(); ns != test.Namespace {
+				t.Errorf("Namespace: got %s, want %s", ns, test.Namespace)
+			}
+			if base := name.Base(); base != test.Base {
+				t.Errorf("Base: got %s, want %s", base, test.Base)
+			}
+			session, ok := name.Session()
+			if ok != (test.Namespace == winobj.SessionNamespace) || session != test.Session {
+				t.Errorf("Session: got %d (%t), want %d", session, ok, test.Session)
+			}
+			if s := name.String(); s != test.Name {
+				t.Errorf("String: got %s, want %s", s, test.Name)
+			}
+		})
+	}
+}
+
+func TestParseNameInvalid(t *testing.T) {
+	tests := []string{
+		``,
+		`Global\`,
+		`Global\MyApp\Lock`,
+		`Session\MyApp-Lock`,
+		`Session\x\MyApp-Lock`,
+		"MyApp\x00Lock",
+		`\BaseNamedObjects\`,
+		`\BaseNamedObjects\\MyApp-Lock`,
+		strings.Repeat("a", winobj.MaxNameLength+1),
+	}
+
+	for _, test := range tests {
+		if _, err := winobj.ParseName(test); err == nil {
+			t.Errorf("ParseName(%q) succeeded when it should have failed", test)
+		}
+	}
+}
+
+func TestNamespaceNames(t *testing.T) {
+	tests := []struct {
+		Build func() (winobj.Name, error)
+		Want  winobj.Name
+	}{
+		{func() (winobj.Name, error) { return winobj.GlobalName("MyApp") }, `Global\MyApp`},
+		{func() (winobj.Name, error) { return winobj.LocalName("MyApp") }, `Local\MyApp`},
+		{func() (winobj.Name, error) { return winobj.SessionName(3, "MyApp") }, `Session\3\MyApp`},
+	}
+
+	for _, test := range tests {
+		name, err := test.Build()
+		if err != nil {
+			t.Errorf("%s: %v", test.Want, err)
+			continue
+		}
+		if name != test.Want {
+			t.Errorf("got %s, want %s", name, test.Want)
+		}
+	}
+}
+
+func TestNamespaceNamesInvalid(t *testing.T) {
+	tests := []string{
+		``,
+		`Global\MyApp`,
+		`Session\1\MyApp`,
+		`MyApp\Lock`,
+	}
+
+	for _, test := range tests {
+		if _, err := winobj.GlobalName(test); err == nil {
+			t.Errorf("GlobalName(%q) succeeded when it should have failed", test)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+//go:build windows
+
+package winobj
+
+import (
+	"fmt"
+	"log/slog"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Option configures the creation of a kernel object. Options are accepted
+// by constructors throughout the module.
+type Option func(*Options)
+
+// Options holds the settings that are applied when a kernel object is
+// created. Constructors obtain them by calling ApplyOptions.
+type Options struct {
+	// SecurityDescriptor is the security descriptor assigned to newly
+	// created objects. If it is nil, objects receive a default security
+	// descriptor derived from the caller's access token.
+	//
+	// It has no effect on objects that already exist.
+	SecurityDescriptor *windows.SECURITY_DESCRIPTOR
+
+	// InitialOwner requests that the caller take ownership of a newly
+	// created object, for objects that can be owned, such as mutexes.
+	// Constructors for other kinds of objects ignore it.
+	InitialOwner bool
+
+	// Access is the access mask requested for the handle to a created or
+	// opened object, for constructors that support it. If it is zero,
+	// each constructor requests the access that it needs.
+	Access uint32
+
+	// Inheritable marks the handle to a created or opened object as
+	// inheritable, so that child processes can receive it.
+	Inheritable bool
+
+	// Observer is notified of attempts to acquire locks, for locks that
+	// support it.
+	Observer LockObserver
+
+	// Logger receives debug events describing the life of the created or
+	// opened object, for constructors that support it.
+	Logger *slog.Logger
+
+	// PrivateNamespace places the created or opened object within a
+	// private namespace, for constructors that support it.
+	PrivateNamespace *PrivateNamespace
+
+	// Directory places the created or opened object within an object
+	// directory with the given path, such as the named object directory of
+	// an AppContainer, for constructors that support it.
+	Directory string
+
+	// Unprefixed uses the name given to the constructor as it is, without
+	// applying the default prefix set by SetDefaultPrefix. It is intended
+	// for objects that belong to other software.
+	Unprefixed bool
+
+	// LowIntegrity adds a low mandatory integrity label to the security
+	// descriptor of newly created objects, so that processes running at
+	// low integrity can open them. ApplyOptions applies the label to
+	// SecurityDescriptor, creating one if necessary.
+	LowIntegrity bool
+
+	// ReportLockedClose causes an object that is closed while a lock on
+	// it is held to report an error after releasing the lock, for locks
+	// that support it.
+	ReportLockedClose bool
+
+	err error // The first error encountered while applying options
+}
+
+// ApplyOptions returns the settings produced by applying opts in order. It
+// returns an error if any of the options are invalid, such as an option
+// holding a malformed SDDL string.
+func ApplyOptions(opts ...Option) (Options, error) {
+	var o Options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	if o.LowIntegrity && o.err == nil {
+		sd, err := withLowIntegrityLabel(o.SecurityDescriptor)
+		if err != nil {
+			return o, fmt.Errorf("winobj: failed to apply a low integrity label: %w", err)
+		}
+		o.SecurityDescriptor = sd
+	}
+	return o, o.err
+}
+
+// lowIntegritySDDL describes a system access control list that holds a
+// low mandatory integrity label. Processes at low integrity may read,
+// write and synchronize on objects with the label, but not modify their
+// security.
+const lowIntegritySDDL = "S:(ML;;NW;;;LW)"
+
+// withLowIntegrityLabel returns a copy of sd with a low mandatory integrity
+// label. If sd is nil, the returned security descriptor holds only the
+// label, and objects that are created with it receive a default DACL.
+func withLowIntegrityLabel(sd *windows.SECURITY_DESCRIPTOR) (*windows.SECURITY_DESCRIPTOR, error) {
+	label, err := windows.SecurityDescriptorFromString(lowIntegritySDDL)
+	if err != nil {
+		return nil, err
+	}
+	if sd == nil {
+		return label, nil
+	}
+
+	sacl, _, err := label.SACL()
+	if err != nil {
+		return nil, err
+	}
+	absolute, err := sd.ToAbsolute()
+	if err != nil {
+		return nil, err
+	}
+	if err := absolute.SetSACL(sacl, true, false); err != nil {
+		return nil, err
+	}
+	return absolute.ToSelfRelative()
+}
+
+// QualifyName returns name with the namespace of o applied to it. If o has
+// a private namespace or a directory, name is placed within it and the
+// default prefix is not applied. If o is unprefixed, name is returned
+// unchanged. Otherwise, it is equivalent to Qualify.
+func (o Options) QualifyName(name string) string {
+	switch {
+	case name == "":
+		return name
+	case o.PrivateNamespace != nil:
+		return o.PrivateNamespace.alias + `\` + name
+	case o.Directory != "":
+		return o.Directory + `\` + name
+	case o.Unprefixed:
+		return name
+	default:
+		return Qualify(name)
+	}
+}
+
+// SecurityAttributes returns security attributes that carry the security
+// descriptor and handle inheritance of o. If o has neither a security
+// descriptor nor inheritance, it returns nil.
+func (o Options) SecurityAttributes() *windows.SecurityAttributes {
+	if o.SecurityDescriptor == nil && !o.Inheritable {
+		return nil
+	}
+	attrs := &windows.SecurityAttributes{SecurityDescriptor: o.SecurityDescriptor}
+	attrs.Length = uint32(unsafe.Sizeof(*attrs))
+	if o.Inheritable {
+		attrs.InheritHandle = 1
+	}
+	return attrs
+}
+
+// SyscallSecurityAttributes returns the same security attributes as
+// SecurityAttributes, in the form accepted by the syscall package and the
+// bindings in this module's api packages.
+//
+// The returned attributes keep the security descriptor reachable for as
+// long as they are themselves reachable.
+func (o Options) SyscallSecurityAttributes() *syscall.SecurityAttributes {
+	// The two structures share a layout. Only the type of the descriptor
+	// field differs, and the memory remains typed as a pointer so that the
+	// garbage collector retains the descriptor.
+	return (*syscall.SecurityAttributes)(unsafe.Pointer(o.SecurityAttributes()))
+}
+
+// WithSecurityDescriptor returns an option that assigns sd to newly
+// created objects. It allows programs that build access control lists
+// dynamically to supply them without converting them to SDDL.
+//
+// A nil sd restores the default security descriptor.
+func WithSecurityDescriptor(sd *windows.SECURITY_DESCRIPTOR) Option {
+	return func(o *Options) {
+		o.SecurityDescriptor = sd
+	}
+}
+
+// WithAccess returns an option that requests the given access mask for
+// the handle to a created or opened object.
+func WithAccess(access uint32) Option {
+	return func(o *Options) {
+		o.Access = access
+	}
+}
+
+// WithInheritable returns an option that marks the handle to a created or
+// opened object as inheritable.
+//
+// Inheritable handles are received by every child process that is started
+// with handle inheritance enabled, which os/exec does when any handles are
+// listed in SysProcAttr.AdditionalInheritedHandles. List the handle there
+// to pass it to a particular child.
+func WithInheritable() Option {
+	return func(o *Options) {
+		o.Inheritable = true
+	}
+}
+
+// WithObserver returns an option that notifies o of attempts to acquire
+// the created or opened lock, such as to record wait times and contention.
+func WithObserver(o LockObserver) Option {
+	return func(opts *Options) {
+		opts.Observer = o
+	}
+}
+
+// WithLogger returns an option that emits debug events to logger as the
+// created or opened object is used, such as when a lock is acquired or
+// released. Events are logged at slog.LevelDebug.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *Options) {
+		o.Logger = logger
+	}
+}
+
+// WithReportLockedClose returns an option that causes Close to return an
+// error when a lock is still held, after releasing it as usual. It helps
+// to audit the shutdown order of programs that are expected to release
+// their locks before closing them.
+func WithReportLockedClose() Option {
+	return func(o *Options) {
+		o.ReportLockedClose = true
+	}
+}
+
+// WithPrivateNamespace returns an option that places the created or
+// opened object within ns. The name given to the constructor is used
+// within the namespace, and the default prefix set by SetDefaultPrefix is
+// not applied to it.
+func WithPrivateNamespace(ns *PrivateNamespace) Option {
+	return func(o *Options) {
+		o.PrivateNamespace = ns
+	}
+}
+
+// WithoutPrefix returns an option that uses the name given to the
+// constructor as it is, without applying the default prefix set by
+// SetDefaultPrefix. It is intended for objects that belong to other
+// software, such as the mutex that Windows Installer holds while it is
+// busy, whose names must not be changed.
+func WithoutPrefix() Option {
+	return func(o *Options) {
+		o.Unprefixed = true
+	}
+}
+
+// WithLowIntegrity returns an option that applies a low mandatory
+// integrity label (S-1-16-4096) to newly created objects, so that
+// sandboxed processes running at low integrity, such as browser renderers
+// and protected-mode applications, can open and synchronize on them.
+//
+// It may be combined with WithSDDL or WithSecurityDescriptor, in which
+// case the label replaces the system access control list of the given
+// security descriptor, and its DACL still applies.
+func WithLowIntegrity() Option {
+	return func(o *Options) {
+		o.LowIntegrity = true
+	}
+}
+
+// WithSDDL returns an option that assigns the security descriptor
+// described by the given SDDL string to newly created objects, such as
+// "D:(A;;GA;;;WD)", which grants full access to everyone.
+//
+// If the string is malformed, constructors that are given the option
+// return an error.
+func WithSDDL(sddl string) Option {
+	sd, err := windows.SecurityDescriptorFromString(sddl)
+	return func(o *Options) {
+		if err != nil {
+			if o.err == nil {
+				o.err = fmt.Errorf("winobj: invalid SDDL string \"%s\": %w", sddl, err)
+			}
+			return
+		}
+		o.SecurityDescriptor = sd
+	}
+}
@@ -0,0 +1,130 @@
+//go:build windows
+
+package winobj_test
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winobj"
+	"golang.org/x/sys/windows"
+)
+
+func TestOptionsSecurityAttributes(t *testing.T) {
+	empty, err := winobj.ApplyOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs := empty.SecurityAttributes(); attrs != nil {
+		t.Fatalf("SecurityAttributes returned %v without a security descriptor", attrs)
+	}
+
+	sd, err := windows.SecurityDescriptorFromString("D:(A;;GA;;;WD)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options, err := winobj.ApplyOptions(winobj.WithSecurityDescriptor(sd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := options.SecurityAttributes()
+	if attrs == nil {
+		t.Fatal("SecurityAttributes returned nil with a security descriptor")
+	}
+	if attrs.SecurityDescriptor != sd {
+		t.Fatal("SecurityAttributes did not carry the security descriptor")
+	}
+	if attrs.Length != uint32(unsafe.Sizeof(*attrs)) {
+		t.Fatalf("SecurityAttributes has length %d", attrs.Length)
+	}
+
+	sysAttrs := options.SyscallSecurityAttributes()
+	if sysAttrs.SecurityDescriptor != uintptr(unsafe.Pointer(sd)) {
+		t.Fatal("SyscallSecurityAttributes did not carry the security descriptor")
+	}
+
+	// A nil descriptor restores the default.
+	options, err = winobj.ApplyOptions(winobj.WithSecurityDescriptor(sd), winobj.WithSecurityDescriptor(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs := options.SecurityAttributes(); attrs != nil {
+		t.Fatalf("SecurityAttributes returned %v after the security descriptor was cleared", attrs)
+	}
+}
+
+func TestOptionsSDDL(t *testing.T) {
+	options, err := winobj.ApplyOptions(winobj.WithSDDL("D:(A;;GA;;;WD)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options.SecurityDescriptor == nil {
+		t.Fatal("WithSDDL did not set a security descriptor")
+	}
+	if got := options.SecurityDescriptor.String(); got != "D:(A;;GA;;;WD)" {
+		t.Fatalf("WithSDDL set the security descriptor %s", got)
+	}
+
+	if _, err := winobj.ApplyOptions(winobj.WithSDDL("not sddl")); err == nil {
+		t.Fatal("ApplyOptions succeeded with a malformed SDDL string")
+	}
+}
+
+func TestOptionsLowIntegrity(t *testing.T) {
+	for _, opts := range [][]winobj.Option{
+		{winobj.WithLowIntegrity()},
+		{winobj.WithLowIntegrity(), winobj.WithSDDL("D:(A;;GA;;;WD)")},
+	} {
+		options, err := winobj.ApplyOptions(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if options.SecurityDescriptor == nil {
+			t.Fatal("WithLowIntegrity did not produce a security descriptor")
+		}
+		sddl := options.SecurityDescriptor.String()
+		if !strings.Contains(sddl, "S:(ML;;NW;;;LW)") {
+			t.Errorf("The security descriptor %s does not have a low integrity label", sddl)
+		}
+		if len(opts) > 1 && !strings.Contains(sddl, "D:(A;;GA;;;WD)") {
+			t.Errorf("The security descriptor %s lost its DACL", sddl)
+		}
+	}
+}
+
+func TestOptionsAppContainer(t *testing.T) {
+	// S-1-15-2-1 is the ALL APPLICATION PACKAGES SID, which is not an
+	// AppContainer, so only the derivation of a well-formed SID is tested.
+	sid, err := windows.StringToSid("S-1-15-2-1-2-3-4-5-6-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options, err := winobj.ApplyOptions(winobj.WithAppContainer(sid))
+	if err != nil {
+		t.Skip(err)
+	}
+	if !strings.Contains(options.Directory, `AppContainerNamedObjects\S-1-15-2-1-2-3-4-5-6-7`) {
+		t.Fatalf("WithAppContainer produced the directory %q", options.Directory)
+	}
+	if got, want := options.QualifyName("Lock"), options.Directory+`\Lock`; got != want {
+		t.Fatalf("QualifyName returned %q instead of %q", got, want)
+	}
+}
+
+func TestOptionsWithoutPrefix(t *testing.T) {
+	if err := winobj.SetDefaultPrefix(`Global\Contoso-`); err != nil {
+		t.Fatal(err)
+	}
+	defer winobj.SetDefaultPrefix("")
+
+	options, err := winobj.ApplyOptions(winobj.WithoutPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := options.QualifyName(`Global\_MSIExecute`), `Global\_MSIExecute`; got != want {
+		t.Fatalf("QualifyName returned %q instead of %q", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package winobj
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultPrefix holds the prefix applied by Qualify.
+var defaultPrefix atomic.Pointer[string]
+
+// SetDefaultPrefix sets a prefix that is applied to the name of every
+// named object created or opened by the packages in this module, such as
+// `Global\Contoso-Agent-`. It allows a program to choose the namespace and
+// naming convention of all of its objects in one place.
+//
+// The prefix may consist of a namespace, a base prefix, or both. If a name
+// includes its own namespace, that namespace takes precedence over the
+// namespace of the prefix, but the base prefix is still applied.
+//
+// SetDefaultPrefix should be called during program initialization, before
+// any objects are created. An empty prefix disables prefixing, which is
+// the default.
+func SetDefaultPrefix(prefix string) error {
+	if prefix != "" {
+		_, _, base, err := Name(prefix).parse()
+		switch {
+		case err != nil:
+			return err
+		case strings.ContainsRune(base, '\\'):
+			return fmt.Errorf("winobj: the default prefix \"%s\" contains a backslash", prefix)
+		case strings.ContainsRune(base, 0):
+			return fmt.Errorf("winobj: the default prefix \"%s\" contains a null character", prefix)
+		}
+	}
+	defaultPrefix.Store(&prefix)
+	return nil
+}
+
+// DefaultPrefix returns the prefix set by SetDefaultPrefix.
+func DefaultPrefix() string {
+	if prefix := defaultPrefix.Load(); prefix != nil {
+		return *prefix
+	}
+	return ""
+}
+
+// Qualify returns name with the default prefix applied to it. Empty names,
+// which identify unnamed objects, are returned unchanged.
+//
+// For example, with the default prefix `Global\Contoso-Agent-`:
+//
+//	Lock        → Global\Contoso-Agent-Lock
+//	Local\Lock  → Local\Contoso-Agent-Lock
+//
+// Object manager paths, such as `\BaseNamedObjects\Lock`, are returned
+// unchanged.
+//
+// Constructors throughout the module call Qualify on the names they are
+// given, so most programs do not need to call it directly.
+func Qualify[N ObjectName](name N) string {
+	s := string(name)
+	prefix := DefaultPrefix()
+	if s == "" || prefix == "" {
+		return s
+	}
+
+	ns, _, base, err := Name(s).parse()
+	if err != nil {
+		// Leave invalid names alone so that they are reported as they
+		// were given.
+		return s
+	}
+	switch ns {
+	case DefaultNamespace:
+		return prefix + s
+	case ObjectPathNamespace:
+		// Object manager paths identify an object precisely, so they are
+		// not prefixed.
+		return s
+	}
+
+	_, _, prefixBase, _ := Name(prefix).parse()
+	return s[:len(s)-len(base)] + prefixBase + base
+}
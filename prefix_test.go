@@ -0,0 +1,50 @@
+package winobj_test
+
+import (
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj"
+)
+
+func TestQualify(t *testing.T) {
+	defer winobj.SetDefaultPrefix("")
+
+	tests := []struct {
+		Prefix string
+		Name   string
+		Want   string
+	}{
+		{``, `MyApp-Lock`, `MyApp-Lock`},
+		{`Global\Contoso-Agent-`, ``, ``},
+		{`Global\Contoso-Agent-`, `Lock`, `Global\Contoso-Agent-Lock`},
+		{`Global\Contoso-Agent-`, `Local\Lock`, `Local\Contoso-Agent-Lock`},
+		{`Global\Contoso-Agent-`, `Session\2\Lock`, `Session\2\Contoso-Agent-Lock`},
+		{`Contoso-Agent-`, `Lock`, `Contoso-Agent-Lock`},
+		{`Contoso-Agent-`, `Global\Lock`, `Global\Contoso-Agent-Lock`},
+		{`Local\`, `Lock`, `Local\Lock`},
+		{`Local\`, `Global\Lock`, `Global\Lock`},
+		{`Global\Contoso-Agent-`, `\BaseNamedObjects\Lock`, `\BaseNamedObjects\Lock`},
+	}
+
+	for _, test := range tests {
+		if err := winobj.SetDefaultPrefix(test.Prefix); err != nil {
+			t.Fatal(err)
+		}
+		if got := winobj.Qualify(test.Name); got != test.Want {
+			t.Errorf("Qualify(%q) with prefix %q: got %q, want %q", test.Name, test.Prefix, got, test.Want)
+		}
+	}
+}
+
+func TestSetDefaultPrefixInvalid(t *testing.T) {
+	defer winobj.SetDefaultPrefix("")
+
+	for _, prefix := range []string{`Global\Contoso\Agent-`, `Session\x\Agent-`, "Agent\x00"} {
+		if err := winobj.SetDefaultPrefix(prefix); err == nil {
+			t.Errorf("SetDefaultPrefix(%q) succeeded when it should have failed", prefix)
+		}
+	}
+	if prefix := winobj.DefaultPrefix(); prefix != "" {
+		t.Errorf("DefaultPrefix returned %q after invalid prefixes were rejected", prefix)
+	}
+}
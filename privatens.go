@@ -0,0 +1,131 @@
+//go:build windows
+
+package winobj
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj/api/namespaceapi"
+	"golang.org/x/sys/windows"
+)
+
+// PrivateNamespace is a private object namespace. Objects created within
+// it cannot be created or opened by processes that do not satisfy its
+// boundary, which protects cooperating processes from other processes
+// that squat on well-known names in the global and local namespaces.
+//
+// Objects are placed within the namespace by passing the WithPrivateNamespace
+// option to their constructors, or by using names returned by Name.
+type PrivateNamespace struct {
+	alias string
+
+	mutex    sync.Mutex
+	handle   syscall.Handle // Zero once the namespace has been closed
+	boundary syscall.Handle
+}
+
+// NewPrivateNamespace creates the private namespace identified by the
+// given boundary name and SIDs, or opens it if it already exists. Within
+// the calling process, the namespace is referred to by alias.
+//
+// Processes that open the namespace must use the same boundary name and
+// SIDs, and each of the SIDs must be present in their access tokens. If no
+// SIDs are given, the SID of the current user is used, which limits the
+// namespace to processes running as that user. Processes running under
+// different accounts can share a namespace by using a group SID that all
+// of them belong to.
+//
+// Options such as WithSDDL are applied to the namespace when it is
+// created. Other options are ignored.
+//
+// It is the caller's responsibility to close the namespace when it is no
+// longer needed. Objects within it remain accessible through handles that
+// are already open.
+func NewPrivateNamespace(boundary, alias string, sids []*windows.SID, opts ...Option) (*PrivateNamespace, error) {
+	options, err := ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("winobj: failed to create the private namespace \"%s\": %w", alias, err)
+	}
+	if alias == "" || strings.ContainsAny(alias, "\\\x00") {
+		return nil, fmt.Errorf("winobj: the private namespace alias \"%s\" is invalid", alias)
+	}
+
+	if len(sids) == 0 {
+		user, err := windows.GetCurrentProcessToken().GetTokenUser()
+		if err != nil {
+			return nil, fmt.Errorf("winobj: failed to determine the current user: %w", err)
+		}
+		sids = []*windows.SID{user.User.Sid}
+	}
+
+	descriptor, err := namespaceapi.CreateBoundaryDescriptor(boundary)
+	if err != nil {
+		return nil, fmt.Errorf("winobj: failed to create the boundary descriptor \"%s\": %w", boundary, err)
+	}
+	for _, sid := range sids {
+		if err := namespaceapi.AddSIDToBoundaryDescriptor(&descriptor, sid); err != nil {
+			namespaceapi.DeleteBoundaryDescriptor(descriptor)
+			return nil, fmt.Errorf("winobj: failed to add %s to the boundary descriptor \"%s\": %w", sid, boundary, err)
+		}
+	}
+
+	handle, err := namespaceapi.CreatePrivateNamespace(options.SyscallSecurityAttributes(), descriptor, alias)
+	if errors.Is(err, windows.ERROR_ALREADY_EXISTS) {
+		handle, err = namespaceapi.OpenPrivateNamespace(descriptor, alias)
+	}
+	if err != nil {
+		namespaceapi.DeleteBoundaryDescriptor(descriptor)
+		return nil, fmt.Errorf("winobj: failed to create the private namespace \"%s\": %w", alias, err)
+	}
+
+	return &PrivateNamespace{
+		alias:    alias,
+		handle:   handle,
+		boundary: descriptor,
+	}, nil
+}
+
+// Alias returns the alias by which the namespace is referred to within the
+// calling process.
+func (ns *PrivateNamespace) Alias() string {
+	return ns.alias
+}
+
+// Name returns the name of an object within the namespace, which is the
+// namespace's alias followed by a backslash and base.
+//
+// Names within a private namespace are not subject to the default prefix
+// set by SetDefaultPrefix. Constructors that are given them directly will
+// apply the prefix, so such names should be used with the
+// WithPrivateNamespace option instead, unless no default prefix is set.
+func (ns *PrivateNamespace) Name(base string) (Name, error) {
+	switch {
+	case base == "":
+		return "", fmt.Errorf("winobj: the name within the private namespace \"%s\" is empty", ns.alias)
+	case strings.ContainsAny(base, "\\\x00"):
+		return "", fmt.Errorf("winobj: the name \"%s\" within the private namespace \"%s\" contains a backslash or null character", base, ns.alias)
+	}
+	return Name(ns.alias + `\` + base), nil
+}
+
+// Close closes the namespace. The namespace continues to exist while other
+// processes have it open.
+func (ns *PrivateNamespace) Close() error {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if ns.handle == 0 {
+		return nil
+	}
+
+	err := namespaceapi.ClosePrivateNamespace(ns.handle, 0)
+	namespaceapi.DeleteBoundaryDescriptor(ns.boundary)
+	ns.handle = 0
+	ns.boundary = 0
+
+	return err
+}
@@ -0,0 +1,97 @@
+package winobj
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// DefaultSeparator is the separator used by a NameScheme that does not
+// specify one.
+const DefaultSeparator = "."
+
+// hashLength is the number of hexadecimal digits in a hashed purpose.
+const hashLength = 16
+
+// NameScheme composes object names from vendor, application, version and
+// purpose components. Products that create many objects across many
+// processes can share a single scheme so that every name they use is
+// formatted the same way and is easy to search for.
+//
+// A scheme with the vendor "Acme", the application "Widget", the version
+// "v2" and the global namespace produces names such as:
+//
+//	Global\Acme.Widget.v2.cache.lock
+//
+// Empty components are omitted. Backslashes and null characters within
+// components are replaced with underscores.
+type NameScheme struct {
+	Namespace Namespace // DefaultNamespace, GlobalNamespace, LocalNamespace or SessionNamespace
+	Session   uint32    // Used with SessionNamespace
+	Vendor    string
+	App       string
+	Version   string
+	Separator string // DefaultSeparator if empty
+
+	// Hash replaces the purpose components of each name with a hash of
+	// them. This keeps names short and uniform when purposes are long or
+	// contain user-supplied data such as file paths.
+	Hash bool
+}
+
+// Name returns the object name for the given purpose components. It
+// returns an error if the resulting name is invalid, such as when it
+// exceeds MaxNameLength.
+func (s NameScheme) Name(purpose ...string) (Name, error) {
+	sep := s.Separator
+	if sep == "" {
+		sep = DefaultSeparator
+	}
+
+	var parts []string
+	for _, part := range []string{s.Vendor, s.App, s.Version} {
+		if part != "" {
+			parts = append(parts, sanitizeComponent(part))
+		}
+	}
+
+	if len(purpose) > 0 {
+		cleaned := make([]string, len(purpose))
+		for i, part := range purpose {
+			cleaned[i] = sanitizeComponent(part)
+		}
+		if s.Hash {
+			sum := sha256.Sum256([]byte(strings.Join(cleaned, sep)))
+			parts = append(parts, hex.EncodeToString(sum[:])[:hashLength])
+		} else {
+			parts = append(parts, cleaned...)
+		}
+	}
+
+	var prefix string
+	switch s.Namespace {
+	case DefaultNamespace:
+	case GlobalNamespace:
+		prefix = `Global\`
+	case LocalNamespace:
+		prefix = `Local\`
+	case SessionNamespace:
+		prefix = fmt.Sprintf(`Session\%d\`, s.Session)
+	default:
+		return "", fmt.Errorf("winobj: the name scheme has an unknown namespace: %d", s.Namespace)
+	}
+
+	return ParseName(prefix + strings.Join(parts, sep))
+}
+
+// sanitizeComponent replaces characters that are not permitted in object
+// names with underscores.
+func sanitizeComponent(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\\' || r == 0 {
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,72 @@
+package winobj_test
+
+import (
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj"
+)
+
+func TestNameScheme(t *testing.T) {
+	scheme := winobj.NameScheme{
+		Namespace: winobj.GlobalNamespace,
+		Vendor:    "Acme",
+		App:       "Widget",
+		Version:   "v2",
+	}
+
+	tests := []struct {
+		Purpose []string
+		Want    winobj.Name
+	}{
+		{nil, `Global\Acme.Widget.v2`},
+		{[]string{"cache", "lock"}, `Global\Acme.Widget.v2.cache.lock`},
+		{[]string{`C:\Data`}, `Global\Acme.Widget.v2.C:_Data`},
+	}
+
+	for _, test := range tests {
+		name, err := scheme.Name(test.Purpose...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != test.Want {
+			t.Errorf("Name(%q): got %s, want %s", test.Purpose, name, test.Want)
+		}
+	}
+}
+
+func TestNameSchemeHash(t *testing.T) {
+	scheme := winobj.NameScheme{
+		Namespace: winobj.SessionNamespace,
+		Session:   3,
+		Vendor:    "Acme",
+		App:       "Widget",
+		Separator: "-",
+		Hash:      true,
+	}
+
+	name1, err := scheme.Name(`C:\Data\customers.db`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name2, err := scheme.Name(`C:\Data\customers.db`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name3, err := scheme.Name(`C:\Data\orders.db`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name1 != name2 {
+		t.Errorf("The same purpose produced different names: %s and %s", name1, name2)
+	}
+	if name1 == name3 {
+		t.Errorf("Different purposes produced the same name: %s", name1)
+	}
+	if id, ok := name1.Session(); !ok || id != 3 {
+		t.Errorf("The name %s is not in session 3", name1)
+	}
+	if got, want := len(name1.Base()), len("Acme-Widget-")+16; got != want {
+		t.Errorf("The name %s has a base length of %d when it should be %d", name1, got, want)
+	}
+}
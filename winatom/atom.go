@@ -0,0 +1,77 @@
+//go:build windows
+
+package winatom
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj/api/winbase"
+)
+
+// MaxNameLength is the maximum number of characters in an atom's string.
+const MaxNameLength = 255
+
+// Atom identifies a string in the global atom table.
+type Atom uint16
+
+// Add adds name to the global atom table and returns its atom. If name is
+// already in the table, its reference count is incremented and the
+// existing atom is returned.
+//
+// Atom strings are compared without regard to case.
+func Add(name string) (Atom, error) {
+	if err := validateName(name); err != nil {
+		return 0, err
+	}
+	atom, err := winbase.GlobalAddAtom(name)
+	if err != nil {
+		return 0, fmt.Errorf("winatom: failed to add \"%s\": %w", name, err)
+	}
+	return Atom(atom), nil
+}
+
+// Find looks up name in the global atom table. It returns the atom and
+// true if the name is present, or zero and false if it is not.
+func Find(name string) (Atom, bool, error) {
+	if err := validateName(name); err != nil {
+		return 0, false, err
+	}
+	atom, err := winbase.GlobalFindAtom(name)
+	if err != nil {
+		if err == syscall.ERROR_FILE_NOT_FOUND {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("winatom: failed to find \"%s\": %w", name, err)
+	}
+	return Atom(atom), true, nil
+}
+
+// Name returns the string that the atom represents.
+func (a Atom) Name() (string, error) {
+	name, err := winbase.GlobalGetAtomName(uint16(a))
+	if err != nil {
+		return "", fmt.Errorf("winatom: failed to get the name of atom %d: %w", a, err)
+	}
+	return name, nil
+}
+
+// Delete decrements the reference count of the atom. When the reference
+// count reaches zero, the atom's string is removed from the table.
+func (a Atom) Delete() error {
+	if err := winbase.GlobalDeleteAtom(uint16(a)); err != nil {
+		return fmt.Errorf("winatom: failed to delete atom %d: %w", a, err)
+	}
+	return nil
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return errors.New("winatom: atom names must not be empty")
+	}
+	if n := len(syscall.StringToUTF16(name)) - 1; n > MaxNameLength {
+		return fmt.Errorf("winatom: atom name length of %d exceeds the %d character limit", n, MaxNameLength)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+//go:build windows
+
+package winatom_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winatom"
+)
+
+func TestAtomAddFindDelete(t *testing.T) {
+	const name = "WinObj-WinAtom-Test-AddFindDelete"
+
+	atom, err := winatom.Add(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, ok, err := winatom.Find(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("The atom was not found after it was added")
+	}
+	if found != atom {
+		t.Fatalf("Find returned atom %d when it should have returned %d", found, atom)
+	}
+
+	got, err := atom.Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != name {
+		t.Fatalf("Name returned %q when it should have returned %q", got, name)
+	}
+
+	if err := atom.Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := winatom.Find(name); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("The atom was found after it was deleted")
+	}
+}
+
+func TestAtomNameTooLong(t *testing.T) {
+	if _, err := winatom.Add(strings.Repeat("x", winatom.MaxNameLength+1)); err == nil {
+		t.Fatal("An atom was added with a name that is too long")
+	}
+}
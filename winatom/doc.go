@@ -0,0 +1,13 @@
+//go:build windows
+
+// Package winatom provides access to the global atom table on Windows.
+//
+// The global atom table maps short strings to 16-bit values that are
+// visible to every process in the same session. It is a convenient way for
+// cooperating processes to register and discover small pieces of
+// information, such as the name of an object that was chosen at run time.
+//
+// Each atom is reference counted. An atom remains in the table until it
+// has been deleted as many times as it was added, so processes should
+// delete the atoms they add when they no longer need them.
+package winatom
@@ -0,0 +1,8 @@
+//go:build windows
+
+// Package winevent provides access to named event objects on Windows.
+//
+// An event is a kernel object that one process can signal to wake up
+// others. Events are commonly used by vendors to announce that something
+// has happened, such as a configuration change or a request to shut down.
+package winevent
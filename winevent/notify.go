@@ -0,0 +1,144 @@
+//go:build windows
+
+package winevent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/ntexapi"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// MaxNotify is the maximum number of events that can be watched by a
+// single call to Notify. It is one less than the number of objects that
+// Windows can wait on at once, to leave room for cancellation.
+const MaxNotify = 63
+
+// pollInterval is the number of milliseconds between checks of manual-reset
+// events that have been reported but not yet reset.
+const pollInterval = 100
+
+// Notify watches the named events and sends the name of each event to ch
+// when it is signaled, in the manner of os/signal.Notify. It blocks until
+// ctx is cancelled or an event can no longer be waited on, and returns the
+// reason it stopped.
+//
+// Each signal of an auto-reset event is reported once. A manual-reset
+// event is reported when it becomes signaled and is not reported again
+// until it has been reset and signaled again.
+//
+// The events must already exist. Notify blocks while sending to ch, so
+// slow receivers delay the reporting of subsequent events.
+func Notify(ctx context.Context, ch chan<- string, names ...string) error {
+	if len(names) == 0 {
+		return errors.New("winevent: no event names were provided")
+	}
+	if len(names) > MaxNotify {
+		return fmt.Errorf("winevent: %d events were provided but no more than %d can be watched at once", len(names), MaxNotify)
+	}
+
+	// Open each event and determine whether it resets automatically.
+	events := make([]syscall.Handle, 0, len(names))
+	defer func() {
+		closeEvents(events)
+	}()
+	manual := make([]bool, len(names))
+	for i, name := range names {
+		event, err := synchapi.OpenEvent(winobj.Qualify(name), syscall.SYNCHRONIZE|ntexapi.EventQueryState)
+		if err != nil {
+			return fmt.Errorf("winevent: failed to open the %s event: %w", name, err)
+		}
+		events = append(events, event)
+
+		info, err := ntexapi.NtQueryEvent(event)
+		if err != nil {
+			return fmt.Errorf("winevent: failed to query the %s event: %w", name, err)
+		}
+		manual[i] = info.EventType == ntexapi.NotificationEvent
+	}
+
+	// Prepare an event that will be signaled if ctx is cancelled, so that
+	// the wait can be interrupted.
+	cancelled, _, err := synchapi.CreateEventEx("", nil, synchapi.CreateEventManualReset, synchapi.EventAllAccess)
+	if err != nil {
+		return fmt.Errorf("winevent: failed to create cancellation event: %w", err)
+	}
+	defer syscall.CloseHandle(cancelled)
+
+	stop := context.AfterFunc(ctx, func() {
+		synchapi.SetEvent(cancelled)
+	})
+	defer stop()
+
+	// Manual-reset events stay signaled after they are reported, so they
+	// are parked until they have been reset.
+	parked := make([]bool, len(names))
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Rearm any parked events that have been reset.
+		var waiting bool
+		for i := range parked {
+			if !parked[i] {
+				continue
+			}
+			info, err := ntexapi.NtQueryEvent(events[i])
+			if err != nil {
+				return fmt.Errorf("winevent: failed to query the %s event: %w", names[i], err)
+			}
+			if info.EventState == 0 {
+				parked[i] = false
+			} else {
+				waiting = true
+			}
+		}
+
+		// Wait for any armed event to be signaled.
+		armed := make([]int, 0, len(names))
+		handles := make([]syscall.Handle, 0, len(names)+1)
+		for i, event := range events {
+			if !parked[i] {
+				armed = append(armed, i)
+				handles = append(handles, event)
+			}
+		}
+		handles = append(handles, cancelled)
+
+		timeout := uint32(windows.INFINITE)
+		if waiting {
+			timeout = pollInterval
+		}
+
+		result, err := synchapi.WaitForMultipleObjects(handles, false, timeout)
+		if err != nil {
+			return fmt.Errorf("winevent: failed to wait for events: %w", err)
+		}
+
+		switch {
+		case result == synchapi.WaitTimeout:
+			continue
+		case result == windows.WAIT_OBJECT_0+uint32(len(armed)):
+			return ctx.Err()
+		case result < windows.WAIT_OBJECT_0+uint32(len(armed)):
+			i := armed[result-windows.WAIT_OBJECT_0]
+			if manual[i] {
+				parked[i] = true
+			}
+			select {
+			case ch <- names[i]:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		default:
+			return fmt.Errorf("winevent: unexpected wait result: %#x", result)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+//go:build windows
+
+package winevent_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winevent"
+	"golang.org/x/sys/windows"
+)
+
+func TestNotify(t *testing.T) {
+	autoName := testEventName("NotifyAuto")
+	manualName := testEventName("NotifyManual")
+
+	autoEvent := createEvent(t, autoName, false)
+	defer windows.CloseHandle(autoEvent)
+	manualEvent := createEvent(t, manualName, true)
+	defer windows.CloseHandle(manualEvent)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan string)
+	done := make(chan error, 1)
+	go func() {
+		done <- winevent.Notify(ctx, ch, autoName, manualName)
+	}()
+
+	expect := func(name string) {
+		t.Helper()
+		select {
+		case received := <-ch:
+			if received != name {
+				t.Fatalf("Received a notification for %s when one was expected for %s", received, name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("No notification was received for %s", name)
+		}
+	}
+	expectNothing := func() {
+		t.Helper()
+		select {
+		case received := <-ch:
+			t.Fatalf("Received an unexpected notification for %s", received)
+		case <-time.After(300 * time.Millisecond):
+		}
+	}
+
+	windows.SetEvent(autoEvent)
+	expect(autoName)
+
+	// A manual-reset event should only be reported once until it is reset.
+	windows.SetEvent(manualEvent)
+	expect(manualName)
+	expectNothing()
+
+	windows.ResetEvent(manualEvent)
+	time.Sleep(200 * time.Millisecond)
+	windows.SetEvent(manualEvent)
+	expect(manualName)
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Notify returned %v when it should have returned %v", err, context.Canceled)
+	}
+}
+
+func TestNotifyMissing(t *testing.T) {
+	ch := make(chan string)
+	if err := winevent.Notify(context.Background(), ch, testEventName("NotifyMissing")); err == nil {
+		t.Fatal("Notify succeeded for an event that does not exist")
+	}
+}
+
+func createEvent(t *testing.T, name string, manual bool) windows.Handle {
+	t.Helper()
+
+	utf16Name, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manualReset uint32
+	if manual {
+		manualReset = 1
+	}
+	event, err := windows.CreateEvent(nil, manualReset, 0, utf16Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func testEventName(name string) string {
+	return "WinObj-WinEvent-Test-" + name
+}
@@ -0,0 +1,129 @@
+//go:build windows
+
+package winevent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// MaxWait is the maximum number of events that can be waited on by a
+// single call to WaitAny or WaitAll.
+const MaxWait = MaxNotify
+
+// WaitAny waits until any of the named events is signaled, and returns the
+// name of the event that was. If more than one event is signaled, the
+// first of them in the order given is returned. It returns an error if ctx
+// is cancelled first.
+//
+// Waiting on an auto-reset event that is signaled resets it.
+//
+// The events must already exist.
+func WaitAny(ctx context.Context, names ...string) (string, error) {
+	events, err := openForWait(names)
+	if err != nil {
+		return "", err
+	}
+	defer closeEvents(events)
+
+	// Prepare an event that will be signaled if ctx is cancelled, so that
+	// the wait can be interrupted.
+	cancelled, _, err := synchapi.CreateEventEx("", nil, synchapi.CreateEventManualReset, synchapi.EventAllAccess)
+	if err != nil {
+		return "", fmt.Errorf("winevent: failed to create cancellation event: %w", err)
+	}
+	defer syscall.CloseHandle(cancelled)
+
+	stop := context.AfterFunc(ctx, func() {
+		synchapi.SetEvent(cancelled)
+	})
+	defer stop()
+
+	n := uint32(len(events))
+	result, err := synchapi.WaitForMultipleObjects(append(events, cancelled), false, windows.INFINITE)
+	if err != nil {
+		return "", fmt.Errorf("winevent: failed to wait for events: %w", err)
+	}
+
+	switch {
+	case result < windows.WAIT_OBJECT_0+n:
+		return names[result-windows.WAIT_OBJECT_0], nil
+	case result == windows.WAIT_OBJECT_0+n:
+		return "", ctx.Err()
+	default:
+		return "", fmt.Errorf("winevent: unexpected wait result: %#x", result)
+	}
+}
+
+// WaitAll waits until all of the named events are signaled at the same
+// time, and returns the names of the events, which is all of them. It
+// returns an error if ctx is cancelled first.
+//
+// Auto-reset events are only reset once all of the events are signaled,
+// so an event that is signaled early is not consumed while the others are
+// still being waited on.
+//
+// The events must already exist.
+func WaitAll(ctx context.Context, names ...string) ([]string, error) {
+	events, err := openForWait(names)
+	if err != nil {
+		return nil, err
+	}
+	defer closeEvents(events)
+
+	// A cancellation event cannot be included in a wait for all objects,
+	// so check ctx between bounded waits instead.
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := synchapi.WaitForMultipleObjects(events, true, pollInterval)
+		if err != nil {
+			return nil, fmt.Errorf("winevent: failed to wait for events: %w", err)
+		}
+
+		switch {
+		case result == synchapi.WaitTimeout:
+		case result < windows.WAIT_OBJECT_0+uint32(len(events)):
+			return names, nil
+		default:
+			return nil, fmt.Errorf("winevent: unexpected wait result: %#x", result)
+		}
+	}
+}
+
+// openForWait opens the named events with the access needed to wait on
+// them.
+func openForWait(names []string) ([]syscall.Handle, error) {
+	if len(names) == 0 {
+		return nil, errors.New("winevent: no event names were provided")
+	}
+	if len(names) > MaxWait {
+		return nil, fmt.Errorf("winevent: %d events were provided but no more than %d can be waited on at once", len(names), MaxWait)
+	}
+
+	events := make([]syscall.Handle, 0, len(names)+1)
+	for _, name := range names {
+		event, err := synchapi.OpenEvent(winobj.Qualify(name), syscall.SYNCHRONIZE)
+		if err != nil {
+			closeEvents(events)
+			return nil, fmt.Errorf("winevent: failed to open the %s event: %w", name, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// closeEvents closes the given event handles.
+func closeEvents(events []syscall.Handle) {
+	for _, event := range events {
+		syscall.CloseHandle(event)
+	}
+}
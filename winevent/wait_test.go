@@ -0,0 +1,79 @@
+//go:build windows
+
+package winevent_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winevent"
+	"golang.org/x/sys/windows"
+)
+
+func TestWaitAny(t *testing.T) {
+	name1 := testEventName("WaitAny1")
+	name2 := testEventName("WaitAny2")
+
+	event1 := createEvent(t, name1, true)
+	defer windows.CloseHandle(event1)
+	event2 := createEvent(t, name2, true)
+	defer windows.CloseHandle(event2)
+
+	windows.SetEvent(event2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	name, err := winevent.WaitAny(ctx, name1, name2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != name2 {
+		t.Fatalf("WaitAny returned %s when it should have returned %s", name, name2)
+	}
+}
+
+func TestWaitAnyCancelled(t *testing.T) {
+	name := testEventName("WaitAnyCancelled")
+	event := createEvent(t, name, true)
+	defer windows.CloseHandle(event)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := winevent.WaitAny(ctx, name); err != context.DeadlineExceeded {
+		t.Fatalf("WaitAny returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWaitAll(t *testing.T) {
+	name1 := testEventName("WaitAll1")
+	name2 := testEventName("WaitAll2")
+
+	event1 := createEvent(t, name1, false)
+	defer windows.CloseHandle(event1)
+	event2 := createEvent(t, name2, false)
+	defer windows.CloseHandle(event2)
+
+	windows.SetEvent(event1)
+
+	// With only one event signaled, the wait should not succeed.
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	if _, err := winevent.WaitAll(ctx, name1, name2); err != context.DeadlineExceeded {
+		t.Fatalf("WaitAll returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+
+	windows.SetEvent(event2)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	names, err := winevent.WaitAll(ctx, name1, name2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 {
+		t.Fatalf("WaitAll returned %d names when it should have returned 2", len(names))
+	}
+}
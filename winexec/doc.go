@@ -0,0 +1,11 @@
+//go:build windows
+
+// Package winexec starts child processes that share unnamed kernel objects
+// with their parent.
+//
+// Start launches a command with an unnamed mutex and an unnamed event that
+// are inherited by the child process. The child process recovers them by
+// calling Inherited. Because the objects are unnamed, parent and child can
+// coordinate without claiming any names in the Global or Local namespaces,
+// and without any risk of other processes interfering with them.
+package winexec
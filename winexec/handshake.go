@@ -0,0 +1,186 @@
+//go:build windows
+
+package winexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"golang.org/x/sys/windows"
+)
+
+// EnvVar is the name of the environment variable that passes the inherited
+// handle values to the child process.
+const EnvVar = "WINOBJ_HANDSHAKE"
+
+// ErrNotInherited is returned by Inherited when the current process was
+// not started with a handshake.
+var ErrNotInherited = errors.New("winexec: the process was not started with a handshake")
+
+// Handshake is a pair of unnamed objects shared by a parent process and a
+// child process: a mutex that either side can lock, and a manual-reset
+// event that either side can signal.
+type Handshake struct {
+	// Mutex is the shared mutex.
+	Mutex *winmutex.Mutex
+
+	event windows.Handle
+}
+
+// Start starts cmd with a new handshake that is inherited by the child
+// process, and returns the parent's side of the handshake. The child
+// process can retrieve its side by calling Inherited.
+//
+// Start modifies cmd's environment and system process attributes. It is
+// the caller's responsibility to close the returned handshake.
+func Start(cmd *exec.Cmd) (*Handshake, error) {
+	mutex, _, err := synchapi.CreateMutex("", false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("winexec: failed to create mutex: %w", err)
+	}
+
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		syscall.CloseHandle(mutex)
+		return nil, fmt.Errorf("winexec: failed to create event: %w", err)
+	}
+
+	m, err := winmutex.FromHandle(mutex)
+	if err != nil {
+		syscall.CloseHandle(mutex)
+		windows.CloseHandle(event)
+		return nil, err
+	}
+
+	h := &Handshake{
+		Mutex: m,
+		event: event,
+	}
+
+	// Mark the handles as inheritable while the child process is started.
+	// Only handles in AdditionalInheritedHandles are passed to the child,
+	// so other processes started concurrently will not receive them.
+	handles := []syscall.Handle{mutex, syscall.Handle(event)}
+	for _, handle := range handles {
+		if err := windows.SetHandleInformation(windows.Handle(handle), windows.HANDLE_FLAG_INHERIT, windows.HANDLE_FLAG_INHERIT); err != nil {
+			h.Close()
+			return nil, fmt.Errorf("winexec: failed to make handle inheritable: %w", err)
+		}
+	}
+	defer func() {
+		for _, handle := range handles {
+			windows.SetHandleInformation(windows.Handle(handle), windows.HANDLE_FLAG_INHERIT, 0)
+		}
+	}()
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.AdditionalInheritedHandles = append(cmd.SysProcAttr.AdditionalInheritedHandles, handles...)
+
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, EnvVar+"="+strconv.FormatUint(uint64(mutex), 10)+","+strconv.FormatUint(uint64(event), 10))
+
+	if err := cmd.Start(); err != nil {
+		h.Close()
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Inherited returns the child's side of the handshake that was passed to
+// the current process by a parent that called Start. If the current
+// process was not started with a handshake, it returns ErrNotInherited.
+//
+// It is the caller's responsibility to close the returned handshake.
+func Inherited() (*Handshake, error) {
+	value, ok := os.LookupEnv(EnvVar)
+	if !ok {
+		return nil, ErrNotInherited
+	}
+
+	mutexValue, eventValue, ok := strings.Cut(value, ",")
+	if !ok {
+		return nil, fmt.Errorf("winexec: the %s environment variable is malformed: %s", EnvVar, value)
+	}
+	mutex, err := strconv.ParseUint(mutexValue, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("winexec: the %s environment variable has an invalid mutex handle: %w", EnvVar, err)
+	}
+	event, err := strconv.ParseUint(eventValue, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("winexec: the %s environment variable has an invalid event handle: %w", EnvVar, err)
+	}
+
+	m, err := winmutex.FromHandle(syscall.Handle(mutex))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handshake{
+		Mutex: m,
+		event: windows.Handle(event),
+	}, nil
+}
+
+// Signal sets the shared event, releasing any waiters on either side.
+func (h *Handshake) Signal() error {
+	if err := windows.SetEvent(h.event); err != nil {
+		return fmt.Errorf("winexec: failed to signal the handshake event: %w", err)
+	}
+	return nil
+}
+
+// Reset resets the shared event to the non-signaled state.
+func (h *Handshake) Reset() error {
+	if err := windows.ResetEvent(h.event); err != nil {
+		return fmt.Errorf("winexec: failed to reset the handshake event: %w", err)
+	}
+	return nil
+}
+
+// Wait blocks until the shared event is signaled or ctx is cancelled.
+func (h *Handshake) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cancelled, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return fmt.Errorf("winexec: failed to create cancellation event: %w", err)
+	}
+	defer windows.CloseHandle(cancelled)
+
+	stop := context.AfterFunc(ctx, func() {
+		windows.SetEvent(cancelled)
+	})
+	defer stop()
+
+	event, err := windows.WaitForMultipleObjects([]windows.Handle{h.event, cancelled}, false, windows.INFINITE)
+	if err != nil {
+		return fmt.Errorf("winexec: failed to wait for the handshake event: %w", err)
+	}
+	if event != windows.WAIT_OBJECT_0 {
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// Close closes the handshake's mutex and event.
+func (h *Handshake) Close() error {
+	err1 := h.Mutex.Close()
+	err2 := windows.CloseHandle(h.event)
+	return errors.Join(err1, err2)
+}
@@ -0,0 +1,75 @@
+//go:build windows
+
+package winexec_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winexec"
+)
+
+const helperEnvVar = "WINOBJ_WINEXEC_TEST_HELPER"
+
+func TestMain(m *testing.M) {
+	// When started as a helper, act as the child side of a handshake.
+	if os.Getenv(helperEnvVar) != "" {
+		os.Exit(runHelper())
+	}
+	os.Exit(m.Run())
+}
+
+func runHelper() int {
+	h, err := winexec.Inherited()
+	if err != nil {
+		return 1
+	}
+	defer h.Close()
+
+	// Exercise the inherited mutex while signaling the parent.
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+
+	if err := h.Signal(); err != nil {
+		return 2
+	}
+
+	return 0
+}
+
+func TestHandshake(t *testing.T) {
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), helperEnvVar+"=1")
+
+	h, err := winexec.Start(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("The child process failed: %v", err)
+	}
+
+	// Once the child has exited, the shared mutex must be available.
+	if !h.Mutex.TryLock() {
+		t.Fatal("The shared mutex could not be locked after the child exited")
+	}
+	h.Mutex.Unlock()
+}
+
+func TestInheritedWithoutHandshake(t *testing.T) {
+	if _, err := winexec.Inherited(); err != winexec.ErrNotInherited {
+		t.Fatalf("Inherited returned %v when it should have returned %v", err, winexec.ErrNotInherited)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+// Package winflock provides a drop-in replacement for file-based locks
+// that is backed by a named system mutex on Windows.
+//
+// The Flock type mirrors the exclusive locking API of the
+// github.com/gofrs/flock package, so that programs which use a lock file
+// to guard a single writer can switch to a kernel mutex without rewriting
+// their call sites. Shared (read) locks are not supported, because system
+// mutexes only provide exclusive ownership.
+package winflock
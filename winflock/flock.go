@@ -0,0 +1,136 @@
+//go:build windows
+
+package winflock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+// Flock is an exclusive lock backed by a named system mutex. It mirrors
+// the exclusive locking methods of the flock.Flock type from the
+// github.com/gofrs/flock package.
+//
+// The system mutex and its operating system thread are only allocated
+// while the lock is held. Unlocking a Flock releases them.
+//
+// A Flock is safe for concurrent use by multiple goroutines.
+type Flock struct {
+	name string
+
+	mutex sync.Mutex
+	held  *winmutex.Mutex
+}
+
+// New returns a new Flock for the system mutex with the given name. The
+// mutex is not created or opened until the lock is acquired. The name may
+// be given as a plain string or as a winobj.Name.
+func New[N winobj.ObjectName](name N) *Flock {
+	return &Flock{name: string(name)}
+}
+
+// Path returns the name of the system mutex that backs the lock. It is
+// named Path for compatibility with the flock package.
+func (f *Flock) Path() string {
+	return f.name
+}
+
+// String returns the name of the system mutex that backs the lock.
+func (f *Flock) String() string {
+	return f.name
+}
+
+// Locked reports whether f currently holds the lock.
+func (f *Flock) Locked() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.held != nil
+}
+
+// Lock acquires the lock, blocking until it is available. If f already
+// holds the lock, it returns immediately.
+func (f *Flock) Lock() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.held != nil {
+		return nil
+	}
+
+	mutex, err := winmutex.New(f.name)
+	if err != nil {
+		return err
+	}
+	mutex.Lock()
+	f.held = mutex
+
+	return nil
+}
+
+// TryLock attempts to acquire the lock without blocking and reports
+// whether it succeeded. If f already holds the lock, it returns true.
+func (f *Flock) TryLock() (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.held != nil {
+		return true, nil
+	}
+
+	mutex, err := winmutex.New(f.name)
+	if err != nil {
+		return false, err
+	}
+	if !mutex.TryLock() {
+		return false, mutex.Close()
+	}
+	f.held = mutex
+
+	return true, nil
+}
+
+// TryLockContext repeatedly attempts to acquire the lock, waiting
+// retryDelay between attempts, until it succeeds or ctx is cancelled.
+// It reports whether the lock was acquired.
+func (f *Flock) TryLockContext(ctx context.Context, retryDelay time.Duration) (bool, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if locked, err := f.TryLock(); locked || err != nil {
+			return locked, err
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// Unlock releases the lock. If f does not hold the lock, it does nothing.
+func (f *Flock) Unlock() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.held == nil {
+		return nil
+	}
+
+	f.held.Unlock()
+	err := f.held.Close()
+	f.held = nil
+
+	return err
+}
+
+// Close releases the lock if it is held. It is equivalent to Unlock and
+// is provided for compatibility with the flock package.
+func (f *Flock) Close() error {
+	return f.Unlock()
+}
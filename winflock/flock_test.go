@@ -0,0 +1,82 @@
+//go:build windows
+
+package winflock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winflock"
+)
+
+func TestFlockLockUnlock(t *testing.T) {
+	lock := winflock.New(testLockName("LockUnlock"))
+
+	if err := lock.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if !lock.Locked() {
+		t.Fatal("Locked returned false after Lock succeeded")
+	}
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if lock.Locked() {
+		t.Fatal("Locked returned true after Unlock succeeded")
+	}
+}
+
+func TestFlockTryLockContended(t *testing.T) {
+	name := testLockName("TryLockContended")
+
+	lock1 := winflock.New(name)
+	if err := lock1.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	defer lock1.Unlock()
+
+	lock2 := winflock.New(name)
+	locked, err := lock2.TryLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if locked {
+		lock2.Unlock()
+		t.Fatal("A lock was acquired when it should have been blocked")
+	}
+}
+
+func TestFlockTryLockContextTimeout(t *testing.T) {
+	name := testLockName("TryLockContextTimeout")
+
+	lock1 := winflock.New(name)
+	if err := lock1.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	defer lock1.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	lock2 := winflock.New(name)
+	locked, err := lock2.TryLockContext(ctx, 10*time.Millisecond)
+	if locked {
+		lock2.Unlock()
+		t.Fatal("A lock was acquired when it should have been blocked")
+	}
+	if err != context.DeadlineExceeded {
+		t.Fatalf("TryLockContext returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestFlockUnlockWithoutLock(t *testing.T) {
+	lock := winflock.New(testLockName("UnlockWithoutLock"))
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testLockName(name string) string {
+	return "WinObj-WinFlock-Test-" + name
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+// Package winhandle inspects the handle tables of processes on Windows.
+//
+// It can be used to determine which processes hold handles to a particular
+// kernel object, such as a named mutex that is blocking an installer.
+// Snapshot lists the synchronization objects held by the current process,
+// which helps to pinpoint leaked handles.
+package winhandle
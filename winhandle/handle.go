@@ -0,0 +1,145 @@
+//go:build windows
+
+package winhandle
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winobj/api/ntexapi"
+	"golang.org/x/sys/windows"
+)
+
+// ErrObjectAddressUnavailable is returned when the system does not reveal
+// the kernel addresses of objects in its handle table. Recent versions of
+// Windows only reveal them to processes with administrative privileges.
+var ErrObjectAddressUnavailable = errors.New("winhandle: object addresses are not available to this process")
+
+// Handle describes an open handle in a process's handle table.
+type Handle struct {
+	// ProcessID identifies the process that holds the handle.
+	ProcessID uint32
+
+	// Value is the value of the handle within its process.
+	Value windows.Handle
+
+	// Object is the kernel address of the object that the handle refers
+	// to. All handles that refer to the same object have the same address.
+	// It is zero when the system does not reveal object addresses.
+	Object uintptr
+
+	// Access is the access mask that was granted to the handle.
+	Access uint32
+
+	// TypeIndex identifies the type of the object that the handle refers
+	// to. Type indices vary between versions of Windows.
+	TypeIndex uint16
+
+	// Attributes holds the handle's attribute flags, such as
+	// OBJ_INHERIT.
+	Attributes uint32
+}
+
+// System returns a snapshot of every open handle in the system.
+func System() ([]Handle, error) {
+	const class = windows.SystemExtendedHandleInformation
+
+	size := uint32(1024 * 1024)
+	for {
+		buffer := make([]byte, size)
+		var needed uint32
+		err := windows.NtQuerySystemInformation(class, unsafe.Pointer(&buffer[0]), size, &needed)
+		switch err {
+		case nil:
+			return parseHandles(buffer), nil
+		case windows.STATUS_INFO_LENGTH_MISMATCH:
+			// The handle table can grow between calls, so leave some room.
+			size = max(needed, size) + 64*1024
+		default:
+			return nil, fmt.Errorf("winhandle: failed to query the system handle table: %w", err)
+		}
+	}
+}
+
+// Process returns a snapshot of the open handles of the process with the
+// given ID.
+func Process(pid uint32) ([]Handle, error) {
+	all, err := System()
+	if err != nil {
+		return nil, err
+	}
+
+	var handles []Handle
+	for _, h := range all {
+		if h.ProcessID == pid {
+			handles = append(handles, h)
+		}
+	}
+	return handles, nil
+}
+
+// SameObject returns the handles in all processes that refer to the same
+// object as h, which must be a handle that is open in the current process.
+// The returned handles do not include h itself.
+//
+// If the system does not reveal object addresses to the calling process,
+// it returns ErrObjectAddressUnavailable.
+func SameObject(h windows.Handle) ([]Handle, error) {
+	all, err := System()
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the kernel address of the object that h refers to.
+	pid := windows.GetCurrentProcessId()
+	var object uintptr
+	for _, entry := range all {
+		if entry.ProcessID == pid && entry.Value == h {
+			object = entry.Object
+			break
+		}
+	}
+	if object == 0 {
+		return nil, ErrObjectAddressUnavailable
+	}
+
+	var handles []Handle
+	for _, entry := range all {
+		if entry.Object != object || (entry.ProcessID == pid && entry.Value == h) {
+			continue
+		}
+		handles = append(handles, entry)
+	}
+	return handles, nil
+}
+
+// parseHandles converts the handle information returned by
+// NtQuerySystemInformation to a slice of handles.
+func parseHandles(buffer []byte) []Handle {
+	header := (*ntexapi.SystemHandleInformationEx)(unsafe.Pointer(&buffer[0]))
+	n := int(header.NumberOfHandles)
+
+	// Guard against a count that doesn't fit within the buffer.
+	const (
+		headerSize = unsafe.Sizeof(ntexapi.SystemHandleInformationEx{})
+		entrySize  = unsafe.Sizeof(ntexapi.SystemHandleTableEntryInfoEx{})
+	)
+	if limit := (uintptr(len(buffer)) - headerSize) / entrySize; uintptr(n) > limit {
+		n = int(limit)
+	}
+
+	entries := unsafe.Slice((*ntexapi.SystemHandleTableEntryInfoEx)(unsafe.Pointer(&buffer[headerSize])), n)
+	handles := make([]Handle, n)
+	for i, entry := range entries {
+		handles[i] = Handle{
+			ProcessID:  uint32(entry.UniqueProcessID),
+			Value:      windows.Handle(entry.HandleValue),
+			Object:     entry.Object,
+			Access:     entry.GrantedAccess,
+			TypeIndex:  entry.ObjectTypeIndex,
+			Attributes: entry.HandleAttributes,
+		}
+	}
+	return handles
+}
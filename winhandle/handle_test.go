@@ -0,0 +1,66 @@
+//go:build windows
+
+package winhandle_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winhandle"
+	"golang.org/x/sys/windows"
+)
+
+func TestProcessIncludesHandle(t *testing.T) {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer windows.CloseHandle(event)
+
+	handles, err := winhandle.Process(windows.GetCurrentProcessId())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, h := range handles {
+		if h.Value == event {
+			return
+		}
+	}
+
+	t.Fatalf("The handle table of the current process does not include handle %#x", event)
+}
+
+func TestSameObject(t *testing.T) {
+	name, err := windows.UTF16PtrFromString("WinObj-WinHandle-Test-SameObject")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := windows.CreateMutex(nil, false, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer windows.CloseHandle(first)
+
+	second, err := windows.OpenMutex(windows.SYNCHRONIZE, false, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer windows.CloseHandle(second)
+
+	handles, err := winhandle.SameObject(first)
+	if errors.Is(err, winhandle.ErrObjectAddressUnavailable) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(handles) != 1 {
+		t.Fatalf("SameObject returned %d handles when it should have returned 1", len(handles))
+	}
+	if handles[0].Value != second {
+		t.Fatalf("SameObject returned handle %#x when it should have returned %#x", handles[0].Value, second)
+	}
+}
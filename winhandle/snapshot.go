@@ -0,0 +1,126 @@
+//go:build windows
+
+package winhandle
+
+import (
+	"fmt"
+	"slices"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj/api/ntobapi"
+	"golang.org/x/sys/windows"
+)
+
+// SyncTypes are the names of the synchronization object types included in
+// a snapshot.
+var SyncTypes = []string{"Event", "Mutant", "Semaphore", "Timer"}
+
+// Object describes a synchronization object held open by the current
+// process.
+type Object struct {
+	// Handle is the value of the handle within the current process.
+	Handle windows.Handle
+
+	// Type is the name of the object's type, such as "Mutant" for a
+	// mutex.
+	Type string
+
+	// Name is the full object manager path of the object, such as
+	// `\Sessions\1\BaseNamedObjects\MyApp-Lock`. It is empty for unnamed
+	// objects.
+	Name string
+
+	// Access is the access mask that was granted to the handle.
+	Access uint32
+}
+
+// String returns a string representation of the object.
+func (o Object) String() string {
+	if o.Name == "" {
+		return fmt.Sprintf("%#x %s (unnamed)", o.Handle, o.Type)
+	}
+	return fmt.Sprintf("%#x %s %s", o.Handle, o.Type, o.Name)
+}
+
+// Snapshot returns the synchronization objects that the current process
+// holds open, with their types and names. Only objects whose types are
+// listed in SyncTypes are included.
+//
+// It is intended as a diagnostic for finding leaked handles. Handles that
+// are closed while the snapshot is taken may be omitted.
+func Snapshot() ([]Object, error) {
+	handles, err := Process(windows.GetCurrentProcessId())
+	if err != nil {
+		return nil, err
+	}
+
+	// Type indices are shared by every handle of the same type, so each
+	// type only needs to be queried once.
+	types := make(map[uint16]string)
+
+	var objects []Object
+	for _, h := range handles {
+		typeName, ok := types[h.TypeIndex]
+		if !ok {
+			typeName, err = queryString(h.Value, ntobapi.ObjectTypeInformation)
+			if err != nil {
+				continue
+			}
+			types[h.TypeIndex] = typeName
+		}
+		if !slices.Contains(SyncTypes, typeName) {
+			continue
+		}
+
+		// Querying names is only safe for types that cannot block, which
+		// includes every synchronization type.
+		name, err := queryString(h.Value, ntobapi.ObjectNameInformation)
+		if err != nil {
+			continue
+		}
+
+		objects = append(objects, Object{
+			Handle: h.Value,
+			Type:   typeName,
+			Name:   name,
+			Access: h.Access,
+		})
+	}
+
+	return objects, nil
+}
+
+// ObjectName returns the full object manager path of the object with the
+// given handle, such as `\Sessions\1\BaseNamedObjects\MyApp-Lock`. It is
+// empty for unnamed objects.
+//
+// Querying the names of some kinds of objects, such as synchronous file
+// handles for named pipes, can block indefinitely. It is safe for every
+// synchronization object type.
+func ObjectName(h windows.Handle) (string, error) {
+	name, err := queryString(h, ntobapi.ObjectNameInformation)
+	if err != nil {
+		return "", fmt.Errorf("winhandle: failed to query the name of handle %#x: %w", h, err)
+	}
+	return name, nil
+}
+
+// queryString queries an object for information of the given class, which
+// must begin with a string.
+func queryString(h windows.Handle, class uint32) (string, error) {
+	buffer := make([]byte, 512)
+	for {
+		needed, status := ntobapi.NtQueryObject(syscall.Handle(h), class, buffer)
+		switch status {
+		case windows.STATUS_SUCCESS:
+			return ntobapi.ParseUnicodeString(buffer), nil
+		case windows.STATUS_INFO_LENGTH_MISMATCH, windows.STATUS_BUFFER_OVERFLOW, windows.STATUS_BUFFER_TOO_SMALL:
+			if int(needed) <= len(buffer) {
+				return "", status
+			}
+			buffer = make([]byte, needed)
+		default:
+			return "", status
+		}
+	}
+}
@@ -0,0 +1,45 @@
+//go:build windows
+
+package winhandle_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winhandle"
+	"golang.org/x/sys/windows"
+)
+
+func TestSnapshot(t *testing.T) {
+	const name = "WinObj-WinHandle-Test-Snapshot"
+
+	utf16Name, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutex, err := windows.CreateMutex(nil, false, utf16Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer windows.CloseHandle(mutex)
+
+	objects, err := winhandle.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, object := range objects {
+		if object.Handle != mutex {
+			continue
+		}
+		if object.Type != "Mutant" {
+			t.Fatalf("The mutex has type %q when it should have type %q", object.Type, "Mutant")
+		}
+		if !strings.HasSuffix(object.Name, `\`+name) {
+			t.Fatalf("The mutex has name %q when it should end with %q", object.Name, name)
+		}
+		return
+	}
+
+	t.Fatal("The snapshot did not include the mutex")
+}
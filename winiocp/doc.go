@@ -0,0 +1,11 @@
+//go:build windows
+
+// Package winiocp delivers the completions of an I/O completion port on a
+// Go channel.
+//
+// An I/O completion port is a kernel object that queues notifications of
+// completed asynchronous operations. Ordinarily a program dedicates one or
+// more threads to dequeuing them. Listen manages those threads instead,
+// so that completions can be received in a select statement alongside the
+// program's other channels.
+package winiocp
@@ -0,0 +1,168 @@
+//go:build windows
+
+package winiocp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winobj/api/ioapiset"
+	"golang.org/x/sys/windows"
+)
+
+// batchSize is the maximum number of completions dequeued by each poller
+// at once.
+const batchSize = 16
+
+// wakeKey is the address of a variable that serves as the completion key
+// of the packets posted to stop pollers. No caller can produce the same
+// key without referring to it.
+var wakeKey byte
+
+// Completion is a completion packet dequeued from a port.
+type Completion struct {
+	Key        uintptr             // The completion key associated with the file handle, or posted with the packet
+	Overlapped *windows.Overlapped // The overlapped structure of the operation, if any
+	Bytes      uint32              // The number of bytes transferred
+}
+
+// Port is an I/O completion port.
+type Port struct {
+	handle windows.Handle
+}
+
+// New creates an I/O completion port that allows up to concurrency
+// threads to process its completions at once. A concurrency of zero allows
+// as many threads as there are processors.
+//
+// It is the caller's responsibility to close the port.
+func New(concurrency uint32) (*Port, error) {
+	handle, err := windows.CreateIoCompletionPort(windows.InvalidHandle, 0, 0, concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("winiocp: failed to create completion port: %w", err)
+	}
+	return &Port{handle: handle}, nil
+}
+
+// Associate associates a file handle that was opened for overlapped I/O
+// with the port. Completions of its operations are queued to the port with
+// the given key.
+func (p *Port) Associate(file windows.Handle, key uintptr) error {
+	if _, err := windows.CreateIoCompletionPort(file, p.handle, key, 0); err != nil {
+		return fmt.Errorf("winiocp: failed to associate handle with completion port: %w", err)
+	}
+	return nil
+}
+
+// Post queues a completion packet to the port.
+func (p *Port) Post(c Completion) error {
+	if err := windows.PostQueuedCompletionStatus(p.handle, c.Bytes, c.Key, c.Overlapped); err != nil {
+		return fmt.Errorf("winiocp: failed to post completion: %w", err)
+	}
+	return nil
+}
+
+// Listen dequeues completions from the port and sends them to ch. It runs
+// the given number of pollers, each of which dequeues completions on its
+// own operating system thread. It blocks until ctx is cancelled or the
+// port can no longer be read, and returns the reason it stopped.
+//
+// Listen blocks while sending to ch, so ch should be buffered if the
+// receiver cannot keep up with bursts of completions. Completions that are
+// dequeued after ctx is cancelled are discarded.
+func (p *Port) Listen(ctx context.Context, ch chan<- Completion, pollers int) error {
+	if pollers < 1 {
+		return errors.New("winiocp: at least one poller is required")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// When ctx is cancelled, wake one poller. Each poller that is woken
+	// wakes the next until every poller has stopped.
+	var running atomic.Int32
+	running.Store(int32(pollers))
+	context.AfterFunc(ctx, p.wake)
+
+	errs := make(chan error, pollers)
+	for range pollers {
+		go func() {
+			errs <- p.poll(ctx, ch, &running)
+		}()
+	}
+
+	var first error
+	for range pollers {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+			cancel()
+		}
+	}
+	if first != nil {
+		return first
+	}
+	return ctx.Err()
+}
+
+// poll dequeues completions and sends them to ch until it receives a wake
+// packet or fails.
+func (p *Port) poll(ctx context.Context, ch chan<- Completion, running *atomic.Int32) error {
+	entries := make([]ioapiset.OverlappedEntry, batchSize)
+	for {
+		n, err := ioapiset.GetQueuedCompletionStatusEx(syscall.Handle(p.handle), entries, windows.INFINITE, false)
+		if err != nil {
+			running.Add(-1)
+			return fmt.Errorf("winiocp: failed to dequeue completions: %w", err)
+		}
+
+		woken := false
+		for _, entry := range entries[:n] {
+			if entry.CompletionKey == uintptr(unsafe.Pointer(&wakeKey)) && entry.Overlapped == nil {
+				woken = true
+				continue
+			}
+			completion := Completion{
+				Key:        entry.CompletionKey,
+				Overlapped: entry.Overlapped,
+				Bytes:      entry.NumberOfBytesTransferred,
+			}
+			select {
+			case ch <- completion:
+			case <-ctx.Done():
+			}
+		}
+
+		if woken {
+			if running.Add(-1) > 0 {
+				p.wake()
+			}
+			return nil
+		}
+	}
+}
+
+// wake posts a packet that stops one poller.
+func (p *Port) wake() {
+	windows.PostQueuedCompletionStatus(p.handle, 0, uintptr(unsafe.Pointer(&wakeKey)), nil)
+}
+
+// Handle returns the underlying handle of the port. It remains owned by
+// the port and must not be closed by the caller.
+func (p *Port) Handle() windows.Handle {
+	return p.handle
+}
+
+// Close closes the port. Any calls to Listen that are in progress return
+// an error.
+func (p *Port) Close() error {
+	if p.handle == 0 {
+		return nil
+	}
+	err := windows.CloseHandle(p.handle)
+	p.handle = 0
+	return err
+}
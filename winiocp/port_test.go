@@ -0,0 +1,73 @@
+//go:build windows
+
+package winiocp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winiocp"
+)
+
+func TestListen(t *testing.T) {
+	port, err := winiocp.New(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer port.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan winiocp.Completion, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- port.Listen(ctx, ch, 3)
+	}()
+
+	const count = 20
+	for i := range count {
+		if err := port.Post(winiocp.Completion{Key: uintptr(i), Bytes: uint32(i * 10)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := make(map[uintptr]bool)
+	timeout := time.After(10 * time.Second)
+	for len(seen) < count {
+		select {
+		case c := <-ch:
+			if c.Bytes != uint32(c.Key*10) {
+				t.Fatalf("Completion %d reported %d bytes when it should have reported %d", c.Key, c.Bytes, c.Key*10)
+			}
+			seen[c.Key] = true
+		case <-timeout:
+			t.Fatalf("Only %d of %d completions were received", len(seen), count)
+		}
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Listen returned %v when it should have returned %v", err, context.Canceled)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Listen did not return after its context was cancelled")
+	}
+}
+
+func TestListenNoPollers(t *testing.T) {
+	port, err := winiocp.New(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer port.Close()
+
+	if err := port.Listen(context.Background(), make(chan winiocp.Completion), 0); err == nil {
+		t.Fatal("Listen succeeded without any pollers")
+	}
+}
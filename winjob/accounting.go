@@ -0,0 +1,116 @@
+//go:build windows
+
+package winjob
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// basicAndIOAccountingInformation is JOBOBJECT_BASIC_AND_IO_ACCOUNTING_INFORMATION.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-jobobject_basic_and_io_accounting_information
+type basicAndIOAccountingInformation struct {
+	TotalUserTime             int64
+	TotalKernelTime           int64
+	ThisPeriodTotalUserTime   int64
+	ThisPeriodTotalKernelTime int64
+	TotalPageFaultCount       uint32
+	TotalProcesses            uint32
+	ActiveProcesses           uint32
+	TotalTerminatedProcesses  uint32
+	IoInfo                    windows.IO_COUNTERS
+}
+
+// Usage describes the resources consumed by the processes in a job since
+// it was created, including processes that have exited.
+type Usage struct {
+	UserTime            time.Duration
+	KernelTime          time.Duration
+	PageFaults          uint32
+	TotalProcesses      uint32 // Every process that has been in the job
+	ActiveProcesses     uint32 // Processes that are currently in the job
+	TerminatedProcesses uint32 // Processes terminated by a limit violation
+	ReadOperations      uint64
+	WriteOperations     uint64
+	OtherOperations     uint64
+	ReadBytes           uint64
+	WriteBytes          uint64
+	OtherBytes          uint64
+	PeakProcessMemory   uint64 // The most committed memory used by a single process
+	PeakJobMemory       uint64 // The most committed memory used by the job as a whole
+}
+
+// Limits describes the memory and process limits imposed on a job. A
+// limit of zero means that the limit is not set.
+type Limits struct {
+	KillOnClose        bool
+	ActiveProcessLimit uint32
+	ProcessMemoryLimit uint64
+	JobMemoryLimit     uint64
+}
+
+// Usage queries the job for its current resource usage.
+func (job *Job) Usage() (Usage, error) {
+	var accounting basicAndIOAccountingInformation
+	if err := windows.QueryInformationJobObject(job.handle, windows.JobObjectBasicAndIoAccountingInformation, uintptr(unsafe.Pointer(&accounting)), uint32(unsafe.Sizeof(accounting)), nil); err != nil {
+		return Usage{}, fmt.Errorf("winjob: failed to query job accounting information: %w", err)
+	}
+
+	extended, err := job.extendedLimits()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	// Job times are measured in 100-nanosecond intervals.
+	return Usage{
+		UserTime:            time.Duration(accounting.TotalUserTime) * 100,
+		KernelTime:          time.Duration(accounting.TotalKernelTime) * 100,
+		PageFaults:          accounting.TotalPageFaultCount,
+		TotalProcesses:      accounting.TotalProcesses,
+		ActiveProcesses:     accounting.ActiveProcesses,
+		TerminatedProcesses: accounting.TotalTerminatedProcesses,
+		ReadOperations:      accounting.IoInfo.ReadOperationCount,
+		WriteOperations:     accounting.IoInfo.WriteOperationCount,
+		OtherOperations:     accounting.IoInfo.OtherOperationCount,
+		ReadBytes:           accounting.IoInfo.ReadTransferCount,
+		WriteBytes:          accounting.IoInfo.WriteTransferCount,
+		OtherBytes:          accounting.IoInfo.OtherTransferCount,
+		PeakProcessMemory:   uint64(extended.PeakProcessMemoryUsed),
+		PeakJobMemory:       uint64(extended.PeakJobMemoryUsed),
+	}, nil
+}
+
+// Limits queries the job for the limits that are imposed on it.
+func (job *Job) Limits() (Limits, error) {
+	extended, err := job.extendedLimits()
+	if err != nil {
+		return Limits{}, err
+	}
+
+	var limits Limits
+	flags := extended.BasicLimitInformation.LimitFlags
+	limits.KillOnClose = flags&windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE != 0
+	if flags&windows.JOB_OBJECT_LIMIT_ACTIVE_PROCESS != 0 {
+		limits.ActiveProcessLimit = extended.BasicLimitInformation.ActiveProcessLimit
+	}
+	if flags&windows.JOB_OBJECT_LIMIT_PROCESS_MEMORY != 0 {
+		limits.ProcessMemoryLimit = uint64(extended.ProcessMemoryLimit)
+	}
+	if flags&windows.JOB_OBJECT_LIMIT_JOB_MEMORY != 0 {
+		limits.JobMemoryLimit = uint64(extended.JobMemoryLimit)
+	}
+	return limits, nil
+}
+
+// extendedLimits queries the job for its extended limit information.
+func (job *Job) extendedLimits() (windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION, error) {
+	var info windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+	if err := windows.QueryInformationJobObject(job.handle, windows.JobObjectExtendedLimitInformation, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)), nil); err != nil {
+		return info, fmt.Errorf("winjob: failed to query job limit information: %w", err)
+	}
+	return info, nil
+}
@@ -0,0 +1,65 @@
+//go:build windows
+
+package winjob_test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winjob"
+)
+
+func TestUsage(t *testing.T) {
+	job, err := winjob.NewKillOnClose()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer job.Close()
+
+	usage, err := job.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.ActiveProcesses != 0 || usage.TotalProcesses != 0 {
+		t.Fatalf("A new job reported %d active and %d total processes", usage.ActiveProcesses, usage.TotalProcesses)
+	}
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), helperEnvVar+"=1")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+
+	if err := job.Assign(cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err = job.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.ActiveProcesses != 1 || usage.TotalProcesses != 1 {
+		t.Fatalf("The job reported %d active and %d total processes when it should have reported 1 of each", usage.ActiveProcesses, usage.TotalProcesses)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	job, err := winjob.NewKillOnClose()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer job.Close()
+
+	limits, err := job.Limits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !limits.KillOnClose {
+		t.Fatal("The job did not report its kill on close limit")
+	}
+	if limits.ActiveProcessLimit != 0 || limits.ProcessMemoryLimit != 0 || limits.JobMemoryLimit != 0 {
+		t.Fatalf("The job reported limits that were never set: %+v", limits)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+// Package winjob provides access to job objects on Windows.
+//
+// A job is a kernel object that groups processes together so that they
+// can be managed as a unit. Processes started by a member of a job become
+// members of the job as well, so a job captures an entire process tree.
+//
+// The most common use of a job is to ensure that child processes do not
+// outlive their parent. A job created by NewKillOnClose terminates every
+// process in it when its last handle is closed, which happens even if the
+// parent process crashes.
+package winjob
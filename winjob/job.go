@@ -0,0 +1,115 @@
+//go:build windows
+
+package winjob
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winobj"
+	"golang.org/x/sys/windows"
+)
+
+// ErrNotStarted is returned by Assign when the command has not been
+// started.
+var ErrNotStarted = errors.New("winjob: the command has not been started")
+
+// Job is a handle to a job object.
+type Job struct {
+	handle windows.Handle
+}
+
+// New creates an unnamed job with no limits. Options such as
+// winobj.WithSecurityDescriptor are applied to the job.
+//
+// It is the caller's responsibility to close the job.
+func New(opts ...winobj.Option) (*Job, error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("winjob: failed to create job: %w", err)
+	}
+	handle, err := windows.CreateJobObject(options.SecurityAttributes(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("winjob: failed to create job: %w", err)
+	}
+	return &Job{handle: handle}, nil
+}
+
+// NewKillOnClose creates an unnamed job that terminates every process
+// assigned to it when the job is closed. Because Windows closes the
+// handles of a process when it exits, the processes are terminated even
+// if the calling process crashes.
+//
+// It is the caller's responsibility to close the job.
+func NewKillOnClose(opts ...winobj.Option) (*Job, error) {
+	job, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var info windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+	info.BasicLimitInformation.LimitFlags = windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE
+	if _, err := windows.SetInformationJobObject(job.handle, windows.JobObjectExtendedLimitInformation, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+		job.Close()
+		return nil, fmt.Errorf("winjob: failed to set kill on close limit: %w", err)
+	}
+
+	return job, nil
+}
+
+// Assign adds the process started by cmd to the job. Processes that it
+// starts after it has been assigned are added to the job as well.
+//
+// Assign must be called after cmd has been started. Call it immediately
+// after cmd.Start returns, because any processes that the child starts
+// before it has been assigned will not be members of the job.
+func (job *Job) Assign(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return ErrNotStarted
+	}
+
+	process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		return fmt.Errorf("winjob: failed to open process %d: %w", cmd.Process.Pid, err)
+	}
+	defer windows.CloseHandle(process)
+
+	return job.AssignHandle(process)
+}
+
+// AssignHandle adds the process with the given handle to the job. The
+// handle must have PROCESS_SET_QUOTA and PROCESS_TERMINATE access.
+func (job *Job) AssignHandle(process windows.Handle) error {
+	if err := windows.AssignProcessToJobObject(job.handle, process); err != nil {
+		return fmt.Errorf("winjob: failed to assign process to job: %w", err)
+	}
+	return nil
+}
+
+// Terminate terminates every process in the job with the given exit code.
+func (job *Job) Terminate(exitCode uint32) error {
+	if err := windows.TerminateJobObject(job.handle, exitCode); err != nil {
+		return fmt.Errorf("winjob: failed to terminate job: %w", err)
+	}
+	return nil
+}
+
+// Handle returns the underlying handle of the job. It remains owned by
+// the job and must not be closed by the caller.
+func (job *Job) Handle() windows.Handle {
+	return job.handle
+}
+
+// Close closes the job's handle. If the job was created by NewKillOnClose
+// and no other handles to it remain open, every process in the job is
+// terminated.
+func (job *Job) Close() error {
+	if job.handle == 0 {
+		return nil
+	}
+	err := windows.CloseHandle(job.handle)
+	job.handle = 0
+	return err
+}
@@ -0,0 +1,73 @@
+//go:build windows
+
+package winjob_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winjob"
+)
+
+const helperEnvVar = "WINOBJ_WINJOB_TEST_HELPER"
+
+func TestMain(m *testing.M) {
+	// When started as a helper, wait long enough to be killed.
+	if os.Getenv(helperEnvVar) != "" {
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestKillOnClose(t *testing.T) {
+	job, err := winjob.NewKillOnClose()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer job.Close()
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), helperEnvVar+"=1")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+
+	if err := job.Assign(cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	if err := job.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("The helper process exited cleanly when it should have been killed")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("The helper process was not killed when the job was closed")
+	}
+}
+
+func TestAssignNotStarted(t *testing.T) {
+	job, err := winjob.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer job.Close()
+
+	if err := job.Assign(exec.Command(os.Args[0])); !errors.Is(err, winjob.ErrNotStarted) {
+		t.Fatalf("Assign returned %v when it should have returned %v", err, winjob.ErrNotStarted)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+// Package winjournal provides an append-only journal that is shared
+// between processes on Windows.
+//
+// A journal is a circular log of length-prefixed entries that lives in a
+// named shared memory section. Any process can append entries, and each
+// consumer reads them with its own Reader, which tracks its position in
+// the journal independently of every other consumer. A named event wakes
+// readers when new entries are appended.
+//
+// Writers never wait for readers. When the journal is full, the oldest
+// entries are discarded to make room, and readers that fall behind are
+// told how far they have fallen. Because reader positions are kept in the
+// consuming process, a reader that crashes does not affect writers or
+// other readers.
+package winjournal
@@ -0,0 +1,355 @@
+//go:build windows
+
+package winjournal
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"github.com/gentlemanautomaton/winobj/winshm"
+	"golang.org/x/sys/windows"
+)
+
+// Errors returned by the journal.
+var (
+	ErrIncompatible  = errors.New("winjournal: the shared memory section does not hold a compatible journal")
+	ErrEntryTooLarge = errors.New("winjournal: the entry is too large to fit in the journal")
+)
+
+// Version is the version of the journal layout written by this package.
+// Journals with a different version are rejected with ErrIncompatible.
+const Version = 1
+
+// Suffixes appended to the name of a journal to form the names of the
+// objects that accompany it.
+const (
+	LockSuffix   = "-Lock"
+	SignalSuffix = "-Signal"
+)
+
+// MaxSize is the maximum size of a journal's entry area, in bytes.
+const MaxSize = 1 << 30
+
+// schema identifies a section that holds a journal.
+var schema = winshm.Schema{Magic: "WINOBJJL", Version: Version}
+
+// Layout of the journal header.
+const (
+	offsetSize = 0
+	offsetHead = 8  // Position at which the next entry will be written
+	offsetTail = 16 // Position of the oldest entry that is retained
+	headerSize = 24
+)
+
+// Layout of each entry, which is followed by its data and padded to a
+// multiple of 8 bytes.
+const (
+	offsetLength     = 0
+	offsetFlags      = 4
+	entryHeaderSize  = 8
+	flagPadding      = 1 // Marks unused space at the end of the entry area
+	entryAlignment   = 8
+	entryAlignedMask = entryAlignment - 1
+)
+
+// Journal is an append-only journal in a named shared memory section.
+//
+// Positions within the journal are byte offsets that increase for the
+// lifetime of the journal. They are mapped onto the circular entry area
+// by taking their remainder with its size.
+type Journal struct {
+	name    string
+	section *winshm.Section
+	mutex   *winmutex.Mutex
+	signal  windows.Handle
+	size    uint64
+}
+
+// Create creates a journal with the given name, or opens it if it already
+// exists. The journal's entry area holds size bytes, which is rounded up
+// to a multiple of 8. Each entry consumes 8 bytes plus the length of its
+// data, rounded up to a multiple of 8.
+//
+// If the journal already exists with a different size, or with an
+// incompatible layout, it returns ErrIncompatible.
+//
+// Options such as winobj.WithSecurityDescriptor are applied to each of the
+// objects that make up a new journal.
+//
+// It is the caller's responsibility to close the journal.
+func Create(name string, size int, opts ...winobj.Option) (*Journal, error) {
+	if size <= 0 || size > MaxSize {
+		return nil, fmt.Errorf("winjournal: the size of the %s journal must be between 1 and %d bytes", name, MaxSize)
+	}
+
+	j := &Journal{
+		name: name,
+		size: align(uint64(size)),
+	}
+
+	err := j.init(func() (bool, error) {
+		section, existed, err := winshm.Create(name, schema, headerSize+int(j.size), opts...)
+		if err != nil {
+			return false, err
+		}
+		j.section = section
+		return existed, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Open opens an existing journal with the given name. Its size is read
+// from the journal.
+//
+// It is the caller's responsibility to close the journal.
+func Open(name string) (*Journal, error) {
+	j := &Journal{name: name}
+
+	err := j.init(func() (bool, error) {
+		section, err := winshm.Open(name, schema)
+		if err != nil {
+			return false, err
+		}
+		j.section = section
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// init opens the journal's mutex, and while holding it, calls open to
+// create or open the section. It then writes the header of a new journal,
+// or validates the header of an existing one, and opens the journal's
+// signal. The mutex and signal are created with opts if they do not exist.
+func (j *Journal) init(open func() (existed bool, err error), opts ...winobj.Option) (err error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return fmt.Errorf("winjournal: failed to create the %s journal: %w", j.name, err)
+	}
+
+	j.mutex, err = winmutex.New(j.name+LockSuffix, opts...)
+	if err != nil {
+		return err
+	}
+
+	release, err := j.mutex.Acquire(context.Background())
+	if err != nil {
+		j.mutex.Close()
+		return err
+	}
+
+	existed, err := open()
+	if err != nil {
+		release()
+		j.mutex.Close()
+		var incompatible winshm.IncompatibleError
+		if errors.As(err, &incompatible) {
+			return fmt.Errorf("%w: %w", ErrIncompatible, err)
+		}
+		return err
+	}
+
+	if err := j.initHeader(existed); err != nil {
+		release()
+		j.section.Close()
+		j.mutex.Close()
+		return err
+	}
+
+	utf16Name, err := windows.UTF16PtrFromString(winobj.Qualify(j.name + SignalSuffix))
+	if err == nil {
+		j.signal, err = windows.CreateEvent(options.SecurityAttributes(), 1, 0, utf16Name)
+		if err == windows.ERROR_ALREADY_EXISTS {
+			err = nil
+		}
+	}
+	if err != nil {
+		release()
+		j.section.Close()
+		j.mutex.Close()
+		return fmt.Errorf("winjournal: failed to create the signal for the %s journal: %w", j.name, err)
+	}
+
+	release()
+	return nil
+}
+
+// initHeader writes the header of a new journal or validates the header
+// of an existing one. The caller must hold the journal's mutex.
+func (j *Journal) initHeader(existed bool) error {
+	data := j.section.Bytes()
+	if len(data) < headerSize {
+		return ErrIncompatible
+	}
+
+	le := binary.LittleEndian
+
+	if !existed {
+		le.PutUint64(data[offsetSize:], j.size)
+		le.PutUint64(data[offsetHead:], 0)
+		le.PutUint64(data[offsetTail:], 0)
+		return nil
+	}
+
+	size := le.Uint64(data[offsetSize:])
+
+	// When creating, the existing journal must match the requested size.
+	if j.size != 0 && size != j.size {
+		return ErrIncompatible
+	}
+
+	j.size = size
+	if size == 0 || size&entryAlignedMask != 0 || headerSize+size > uint64(len(data)) {
+		return ErrIncompatible
+	}
+
+	return nil
+}
+
+// Name returns the name of the journal.
+func (j *Journal) Name() string {
+	return j.name
+}
+
+// Size returns the size of the journal's entry area, in bytes.
+func (j *Journal) Size() int {
+	return int(j.size)
+}
+
+// Append adds an entry holding a copy of data to the end of the journal
+// and wakes any readers that are waiting for it. If the journal is full,
+// the oldest entries are discarded to make room.
+//
+// If the entry cannot fit in the journal, it returns ErrEntryTooLarge.
+func (j *Journal) Append(data []byte) error {
+	length := align(entryHeaderSize + uint64(len(data)))
+	if length > j.size {
+		return ErrEntryTooLarge
+	}
+
+	release, err := j.lock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// If the entry would cross the end of the entry area, fill the rest
+	// of the area with padding and write the entry at the beginning.
+	head := j.head()
+	if remaining := j.size - head%j.size; remaining < length {
+		j.discard(head + remaining)
+		j.writeEntry(head, nil, remaining-entryHeaderSize, flagPadding)
+		head += remaining
+		j.setHead(head)
+	}
+
+	j.discard(head + length)
+	j.writeEntry(head, data, uint64(len(data)), 0)
+	j.setHead(head + length)
+
+	if err := windows.SetEvent(j.signal); err != nil {
+		return fmt.Errorf("winjournal: failed to signal readers of the %s journal: %w", j.name, err)
+	}
+
+	return nil
+}
+
+// NewReader returns a reader that starts at the end of the journal and
+// receives only entries that are appended after it is created.
+func (j *Journal) NewReader() (*Reader, error) {
+	release, err := j.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return &Reader{journal: j, position: j.head()}, nil
+}
+
+// Close closes the journal's section, mutex and signal. The journal is
+// destroyed once every process has closed it.
+func (j *Journal) Close() error {
+	err1 := j.section.Close()
+	err2 := j.mutex.Close()
+	var err3 error
+	if j.signal != 0 {
+		err3 = windows.CloseHandle(j.signal)
+		j.signal = 0
+	}
+	return errors.Join(err1, err2, err3)
+}
+
+// lock acquires the journal's mutex.
+func (j *Journal) lock() (release func(), err error) {
+	release, err = j.mutex.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("winjournal: failed to lock the %s journal: %w", j.name, err)
+	}
+	return release, nil
+}
+
+// discard advances the tail past the oldest entries until the entry area
+// can hold everything up to end. The caller must hold the journal's mutex.
+func (j *Journal) discard(end uint64) {
+	tail := j.tail()
+	for end-tail > j.size {
+		length, _ := j.entryAt(tail)
+		tail += align(entryHeaderSize + length)
+	}
+	j.setTail(tail)
+}
+
+// entryAt returns the length and flags of the entry at position. The
+// caller must hold the journal's mutex.
+func (j *Journal) entryAt(position uint64) (length uint64, flags uint32) {
+	entry := j.section.Bytes()[headerSize+position%j.size:]
+	le := binary.LittleEndian
+	return uint64(le.Uint32(entry[offsetLength:])), le.Uint32(entry[offsetFlags:])
+}
+
+// dataAt returns the data of the entry at position, which must have the
+// given length. The caller must hold the journal's mutex.
+func (j *Journal) dataAt(position, length uint64) []byte {
+	start := headerSize + position%j.size + entryHeaderSize
+	return j.section.Bytes()[start : start+length]
+}
+
+// writeEntry writes an entry at position. The caller must hold the
+// journal's mutex.
+func (j *Journal) writeEntry(position uint64, data []byte, length uint64, flags uint32) {
+	entry := j.section.Bytes()[headerSize+position%j.size:]
+	le := binary.LittleEndian
+	le.PutUint32(entry[offsetLength:], uint32(length))
+	le.PutUint32(entry[offsetFlags:], flags)
+	copy(entry[entryHeaderSize:], data)
+}
+
+func (j *Journal) head() uint64 {
+	return binary.LittleEndian.Uint64(j.section.Bytes()[offsetHead:])
+}
+
+func (j *Journal) setHead(position uint64) {
+	binary.LittleEndian.PutUint64(j.section.Bytes()[offsetHead:], position)
+}
+
+func (j *Journal) tail() uint64 {
+	return binary.LittleEndian.Uint64(j.section.Bytes()[offsetTail:])
+}
+
+func (j *Journal) setTail(position uint64) {
+	binary.LittleEndian.PutUint64(j.section.Bytes()[offsetTail:], position)
+}
+
+// align rounds n up to a multiple of the entry alignment.
+func align(n uint64) uint64 {
+	return (n + entryAlignedMask) &^ entryAlignedMask
+}
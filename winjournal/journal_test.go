@@ -0,0 +1,171 @@
+//go:build windows
+
+package winjournal_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winjournal"
+)
+
+func TestJournalShared(t *testing.T) {
+	name := testJournalName("Shared")
+
+	writer, err := winjournal.Create(name, 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	journal, err := winjournal.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	reader, err := journal.NewReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 3 {
+		if err := writer.Append([]byte(fmt.Sprintf("entry %d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := range 3 {
+		data, err := reader.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("entry %d", i); string(data) != want {
+			t.Fatalf("Next returned %q when it should have returned %q", data, want)
+		}
+	}
+
+	if _, ok, err := reader.TryNext(); err != nil || ok {
+		t.Fatalf("TryNext returned %t (%v) when the reader should have been caught up", ok, err)
+	}
+}
+
+func TestJournalNextWaits(t *testing.T) {
+	journal, err := winjournal.Create(testJournalName("NextWaits"), 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	reader, err := journal.NewReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		journal.Append([]byte("wake"))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	data, err := reader.Next(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "wake" {
+		t.Fatalf("Next returned %q when it should have returned %q", data, "wake")
+	}
+}
+
+func TestJournalNextCancelled(t *testing.T) {
+	journal, err := winjournal.Create(testJournalName("NextCancelled"), 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	reader, err := journal.NewReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := reader.Next(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Next returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestJournalOverrun(t *testing.T) {
+	journal, err := winjournal.Create(testJournalName("Overrun"), 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	reader, err := journal.NewReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each entry consumes 16 bytes, so only the last four are retained.
+	for i := range 10 {
+		if err := journal.Append([]byte(fmt.Sprintf("entry %d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var overrun winjournal.OverrunError
+	if _, _, err := reader.TryNext(); !errors.As(err, &overrun) {
+		t.Fatalf("TryNext returned %v when it should have returned an overrun error", err)
+	}
+
+	for i := 6; i < 10; i++ {
+		data, ok, err := reader.TryNext()
+		if err != nil || !ok {
+			t.Fatalf("TryNext returned %t (%v) when it should have returned an entry", ok, err)
+		}
+		if want := fmt.Sprintf("entry %d", i); string(data) != want {
+			t.Fatalf("TryNext returned %q when it should have returned %q", data, want)
+		}
+	}
+}
+
+func TestJournalEntryTooLarge(t *testing.T) {
+	journal, err := winjournal.Create(testJournalName("EntryTooLarge"), 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	if err := journal.Append(make([]byte, 64)); !errors.Is(err, winjournal.ErrEntryTooLarge) {
+		t.Fatalf("Append returned %v when it should have returned %v", err, winjournal.ErrEntryTooLarge)
+	}
+}
+
+func TestJournalIncompatible(t *testing.T) {
+	name := testJournalName("Incompatible")
+
+	journal, err := winjournal.Create(name, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	if _, err := winjournal.Create(name, 128); !errors.Is(err, winjournal.ErrIncompatible) {
+		t.Fatalf("Create returned %v when it should have returned %v", err, winjournal.ErrIncompatible)
+	}
+}
+
+func testJournalName(name string) string {
+	return "WinObj-WinJournal-Test-" + name
+}
@@ -0,0 +1,160 @@
+//go:build windows
+
+package winjournal
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// pollInterval is the number of milliseconds that a reader waits for the
+// journal's signal before checking for new entries again. It bounds the
+// delay when another reader resets the signal just before it is waited on.
+const pollInterval = 100
+
+// OverrunError is returned by a Reader that has fallen so far behind that
+// entries it had not yet read were discarded. The reader skips ahead to
+// the oldest entry that is retained, and can continue reading.
+type OverrunError struct {
+	Journal string
+	Skipped uint64 // The number of bytes of entries that were skipped
+}
+
+// Error returns a description of the overrun.
+func (e OverrunError) Error() string {
+	return fmt.Sprintf("winjournal: the reader fell behind the %s journal and skipped %d bytes of entries", e.Journal, e.Skipped)
+}
+
+// Reader reads entries from a journal. Each reader tracks its own
+// position, so any number of readers in any number of processes can read
+// the same journal without affecting one another.
+//
+// A Reader is not safe for concurrent use.
+type Reader struct {
+	journal  *Journal
+	position uint64
+}
+
+// Rewind moves the reader to the oldest entry that is retained by the
+// journal.
+func (r *Reader) Rewind() error {
+	release, err := r.journal.lock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	r.position = r.journal.tail()
+	return nil
+}
+
+// TryNext returns a copy of the next entry in the journal without
+// waiting. If there are no new entries, ok is false.
+//
+// If entries were discarded before the reader could read them, it returns
+// an OverrunError and moves to the oldest entry that is retained.
+func (r *Reader) TryNext() (data []byte, ok bool, err error) {
+	release, err := r.journal.lock()
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
+	return r.next()
+}
+
+// Next returns a copy of the next entry in the journal, waiting for one to
+// be appended if necessary. It returns an error if ctx is cancelled before
+// an entry is available.
+//
+// If entries were discarded before the reader could read them, it returns
+// an OverrunError and moves to the oldest entry that is retained.
+func (r *Reader) Next(ctx context.Context) ([]byte, error) {
+	var cancelled windows.Handle
+	defer func() {
+		if cancelled != 0 {
+			windows.CloseHandle(cancelled)
+		}
+	}()
+
+	for {
+		data, ok, err := r.tryNextOrReset()
+		if err != nil || ok {
+			return data, err
+		}
+
+		// Prepare an event that will be signaled if ctx is cancelled, so
+		// that the wait can be interrupted.
+		if cancelled == 0 {
+			cancelled, err = windows.CreateEvent(nil, 1, 0, nil)
+			if err != nil {
+				return nil, fmt.Errorf("winjournal: failed to create cancellation event: %w", err)
+			}
+			stop := context.AfterFunc(ctx, func() {
+				windows.SetEvent(cancelled)
+			})
+			defer stop()
+		}
+
+		event, err := windows.WaitForMultipleObjects([]windows.Handle{r.journal.signal, cancelled}, false, pollInterval)
+		switch event {
+		case windows.WAIT_OBJECT_0, uint32(windows.WAIT_TIMEOUT):
+		case windows.WAIT_OBJECT_0 + 1:
+			return nil, ctx.Err()
+		default:
+			if err == nil {
+				err = fmt.Errorf("unexpected wait result: %d", event)
+			}
+			return nil, fmt.Errorf("winjournal: failed to wait for the %s journal: %w", r.journal.name, err)
+		}
+	}
+}
+
+// tryNextOrReset returns the next entry in the journal. If there are no
+// new entries, it resets the journal's signal while holding the journal's
+// mutex, so that the next append will set it again.
+func (r *Reader) tryNextOrReset() (data []byte, ok bool, err error) {
+	release, err := r.journal.lock()
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
+	data, ok, err = r.next()
+	if err != nil || ok {
+		return data, ok, err
+	}
+
+	if err := windows.ResetEvent(r.journal.signal); err != nil {
+		return nil, false, fmt.Errorf("winjournal: failed to reset the signal for the %s journal: %w", r.journal.name, err)
+	}
+	return nil, false, nil
+}
+
+// next returns a copy of the next entry in the journal, skipping padding.
+// The caller must hold the journal's mutex.
+func (r *Reader) next() (data []byte, ok bool, err error) {
+	j := r.journal
+
+	if tail := j.tail(); r.position < tail {
+		skipped := tail - r.position
+		r.position = tail
+		return nil, false, OverrunError{Journal: j.name, Skipped: skipped}
+	}
+
+	for head := j.head(); r.position < head; {
+		length, flags := j.entryAt(r.position)
+		start := r.position
+		r.position += align(entryHeaderSize + length)
+		if flags&flagPadding != 0 {
+			continue
+		}
+		data = make([]byte, length)
+		copy(data, j.dataAt(start, length))
+		return data, true, nil
+	}
+
+	return nil, false, nil
+}
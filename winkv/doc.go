@@ -0,0 +1,10 @@
+//go:build windows
+
+// Package winkv provides a small key-value store that is shared between
+// processes on Windows.
+//
+// The store is a fixed-capacity hash map that lives in a named shared
+// memory section and is guarded by a named mutex. It is intended for small
+// amounts of shared state, such as feature flags or service endpoints,
+// that several cooperating processes need to read and update.
+package winkv
@@ -0,0 +1,372 @@
+//go:build windows
+
+package winkv
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"github.com/gentlemanautomaton/winobj/winshm"
+)
+
+// Errors returned by the store.
+var (
+	ErrIncompatible  = errors.New("winkv: the shared memory section does not hold a compatible store")
+	ErrFull          = errors.New("winkv: the store is full")
+	ErrKeyTooLarge   = errors.New("winkv: the key exceeds the maximum key size of the store")
+	ErrValueTooLarge = errors.New("winkv: the value exceeds the maximum value size of the store")
+)
+
+// Version is the version of the store layout written by this package.
+// Stores with a different version are rejected with ErrIncompatible.
+const Version = 1
+
+// LockSuffix is appended to the name of a store to form the name of the
+// mutex that guards it.
+const LockSuffix = "-Lock"
+
+// schema identifies a section that holds a store.
+var schema = winshm.Schema{Magic: "WINOBJKV", Version: Version}
+
+// Layout of the store header.
+const (
+	offsetSlots    = 0
+	offsetMaxKey   = 4
+	offsetMaxValue = 8
+	offsetCount    = 12
+	headerSize     = 16
+)
+
+// Layout of each slot, which is followed by its key and value.
+const (
+	offsetState    = 0
+	offsetKeyLen   = 2
+	offsetValueLen = 4
+	slotHeaderSize = 8
+)
+
+// Slot states.
+const (
+	slotEmpty   = 0
+	slotUsed    = 1
+	slotDeleted = 2
+)
+
+// Store is a key-value store in a named shared memory section.
+type Store struct {
+	name     string
+	section  *winshm.Section
+	mutex    *winmutex.Mutex
+	slots    int
+	maxKey   int
+	maxValue int
+	slotSize int
+}
+
+// Create creates a store with the given name, or opens it if it already
+// exists. The store holds up to capacity entries, each with a key of up to
+// maxKeySize bytes and a value of up to maxValueSize bytes.
+//
+// If the store already exists with a different capacity or maximum sizes,
+// or with an incompatible layout, it returns ErrIncompatible.
+//
+// Options such as winobj.WithSecurityDescriptor are applied to each of the
+// objects that make up a new store.
+//
+// It is the caller's responsibility to close the store.
+func Create(name string, capacity, maxKeySize, maxValueSize int, opts ...winobj.Option) (*Store, error) {
+	if capacity <= 0 || maxKeySize <= 0 || maxValueSize <= 0 {
+		return nil, fmt.Errorf("winkv: the capacity and maximum sizes of the %s store must be positive", name)
+	}
+	if maxKeySize > 0xFFFF {
+		return nil, fmt.Errorf("winkv: the maximum key size of the %s store must not exceed %d bytes", name, 0xFFFF)
+	}
+
+	s := &Store{
+		name:     name,
+		slots:    capacity,
+		maxKey:   maxKeySize,
+		maxValue: maxValueSize,
+		slotSize: slotSize(maxKeySize, maxValueSize),
+	}
+
+	err := s.init(func() (bool, error) {
+		section, existed, err := winshm.Create(name, schema, headerSize+capacity*s.slotSize, opts...)
+		if err != nil {
+			return false, err
+		}
+		s.section = section
+		return existed, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Open opens an existing store with the given name. Its capacity and
+// maximum sizes are read from the store.
+//
+// It is the caller's responsibility to close the store.
+func Open(name string) (*Store, error) {
+	s := &Store{name: name}
+
+	err := s.init(func() (bool, error) {
+		section, err := winshm.Open(name, schema)
+		if err != nil {
+			return false, err
+		}
+		s.section = section
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// init opens the store's mutex, and while holding it, calls open to create
+// or open the section. It then writes the header of a new store, or
+// validates the header of an existing one. The mutex is created with opts
+// if it does not exist.
+func (s *Store) init(open func() (existed bool, err error), opts ...winobj.Option) (err error) {
+	s.mutex, err = winmutex.New(s.name+LockSuffix, opts...)
+	if err != nil {
+		return err
+	}
+
+	release, err := s.mutex.Acquire(context.Background())
+	if err != nil {
+		s.mutex.Close()
+		return err
+	}
+
+	existed, err := open()
+	if err != nil {
+		release()
+		s.mutex.Close()
+		var incompatible winshm.IncompatibleError
+		if errors.As(err, &incompatible) {
+			return fmt.Errorf("%w: %w", ErrIncompatible, err)
+		}
+		return err
+	}
+
+	if err := s.initHeader(existed); err != nil {
+		release()
+		s.section.Close()
+		s.mutex.Close()
+		return err
+	}
+
+	release()
+	return nil
+}
+
+// initHeader writes the header of a new store or validates the header of
+// an existing one. The caller must hold the store's mutex.
+func (s *Store) initHeader(existed bool) error {
+	data := s.section.Bytes()
+	if len(data) < headerSize {
+		return ErrIncompatible
+	}
+
+	le := binary.LittleEndian
+
+	if !existed {
+		le.PutUint32(data[offsetSlots:], uint32(s.slots))
+		le.PutUint32(data[offsetMaxKey:], uint32(s.maxKey))
+		le.PutUint32(data[offsetMaxValue:], uint32(s.maxValue))
+		le.PutUint32(data[offsetCount:], 0)
+		return nil
+	}
+
+	slots := int(le.Uint32(data[offsetSlots:]))
+	maxKey := int(le.Uint32(data[offsetMaxKey:]))
+	maxValue := int(le.Uint32(data[offsetMaxValue:]))
+
+	// When creating, the existing store must match the requested geometry.
+	if s.slots != 0 && (slots != s.slots || maxKey != s.maxKey || maxValue != s.maxValue) {
+		return ErrIncompatible
+	}
+
+	s.slots, s.maxKey, s.maxValue = slots, maxKey, maxValue
+	s.slotSize = slotSize(maxKey, maxValue)
+	if slots <= 0 || headerSize+slots*s.slotSize > len(data) {
+		return ErrIncompatible
+	}
+
+	return nil
+}
+
+// Name returns the name of the store.
+func (s *Store) Name() string {
+	return s.name
+}
+
+// Cap returns the maximum number of entries the store can hold.
+func (s *Store) Cap() int {
+	return s.slots
+}
+
+// Len returns the number of entries in the store.
+func (s *Store) Len() (int, error) {
+	release, err := s.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	return int(binary.LittleEndian.Uint32(s.section.Bytes()[offsetCount:])), nil
+}
+
+// Get returns a copy of the value stored for key, and reports whether the
+// key was present.
+func (s *Store) Get(key string) (value []byte, ok bool, err error) {
+	if len(key) > s.maxKey {
+		return nil, false, ErrKeyTooLarge
+	}
+
+	release, err := s.lock()
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
+	slot, found, _ := s.find(key)
+	if !found {
+		return nil, false, nil
+	}
+
+	valueLen := int(binary.LittleEndian.Uint32(slot[offsetValueLen:]))
+	value = make([]byte, valueLen)
+	copy(value, slot[slotHeaderSize+s.maxKey:])
+	return value, true, nil
+}
+
+// Put stores value for key, replacing any existing value. If the key is
+// not present and the store is full, it returns ErrFull.
+func (s *Store) Put(key string, value []byte) error {
+	if len(key) > s.maxKey {
+		return ErrKeyTooLarge
+	}
+	if len(value) > s.maxValue {
+		return ErrValueTooLarge
+	}
+
+	release, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	slot, found, free := s.find(key)
+	if !found {
+		if free == nil {
+			return ErrFull
+		}
+		slot = free
+
+		le := binary.LittleEndian
+		slot[offsetState] = slotUsed
+		le.PutUint16(slot[offsetKeyLen:], uint16(len(key)))
+		copy(slot[slotHeaderSize:], key)
+
+		count := s.section.Bytes()[offsetCount:]
+		le.PutUint32(count, le.Uint32(count)+1)
+	}
+
+	binary.LittleEndian.PutUint32(slot[offsetValueLen:], uint32(len(value)))
+	copy(slot[slotHeaderSize+s.maxKey:], value)
+
+	return nil
+}
+
+// Delete removes key from the store and reports whether it was present.
+func (s *Store) Delete(key string) (deleted bool, err error) {
+	if len(key) > s.maxKey {
+		return false, ErrKeyTooLarge
+	}
+
+	release, err := s.lock()
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	slot, found, _ := s.find(key)
+	if !found {
+		return false, nil
+	}
+
+	// Leave a tombstone so that probing continues past this slot.
+	clear(slot)
+	slot[offsetState] = slotDeleted
+
+	le := binary.LittleEndian
+	count := s.section.Bytes()[offsetCount:]
+	le.PutUint32(count, le.Uint32(count)-1)
+
+	return true, nil
+}
+
+// Close closes the store's section and mutex. The store is destroyed once
+// every process has closed it.
+func (s *Store) Close() error {
+	return errors.Join(s.section.Close(), s.mutex.Close())
+}
+
+// lock acquires the store's mutex.
+func (s *Store) lock() (release func(), err error) {
+	release, err = s.mutex.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("winkv: failed to lock the %s store: %w", s.name, err)
+	}
+	return release, nil
+}
+
+// find searches for key using linear probing. If the key is present, it
+// returns its slot and found is true. Otherwise it returns the first slot
+// that could hold the key, or nil if the store is full. The caller must
+// hold the store's mutex.
+func (s *Store) find(key string) (slot []byte, found bool, free []byte) {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	start := int(h.Sum64() % uint64(s.slots))
+
+	data := s.section.Bytes()
+	for i := range s.slots {
+		offset := headerSize + ((start+i)%s.slots)*s.slotSize
+		candidate := data[offset : offset+s.slotSize]
+
+		switch candidate[offsetState] {
+		case slotEmpty:
+			if free == nil {
+				free = candidate
+			}
+			return nil, false, free
+		case slotDeleted:
+			if free == nil {
+				free = candidate
+			}
+		case slotUsed:
+			keyLen := int(binary.LittleEndian.Uint16(candidate[offsetKeyLen:]))
+			if string(candidate[slotHeaderSize:slotHeaderSize+keyLen]) == key {
+				return candidate, true, nil
+			}
+		}
+	}
+
+	return nil, false, free
+}
+
+// slotSize returns the size of each slot, rounded up to a multiple of 8
+// bytes.
+func slotSize(maxKey, maxValue int) int {
+	return (slotHeaderSize + maxKey + maxValue + 7) &^ 7
+}
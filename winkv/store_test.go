@@ -0,0 +1,122 @@
+//go:build windows
+
+package winkv_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winkv"
+)
+
+func TestStoreShared(t *testing.T) {
+	name := testStoreName("Shared")
+
+	store1, err := winkv.Create(name, 16, 32, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store1.Close()
+
+	store2, err := winkv.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store2.Close()
+
+	if err := store1.Put("endpoint", []byte("https://example.com")); err != nil {
+		t.Fatal(err)
+	}
+
+	value, ok, err := store2.Get("endpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || string(value) != "https://example.com" {
+		t.Fatalf("Get returned %q (%t) when it should have returned the stored value", value, ok)
+	}
+
+	if err := store2.Put("endpoint", []byte("https://example.org")); err != nil {
+		t.Fatal(err)
+	}
+	if value, _, _ := store1.Get("endpoint"); string(value) != "https://example.org" {
+		t.Fatalf("Get returned %q after the value was replaced", value)
+	}
+
+	if n, err := store1.Len(); err != nil || n != 1 {
+		t.Fatalf("Len returned %d (%v) when it should have returned 1", n, err)
+	}
+
+	deleted, err := store1.Delete("endpoint")
+	if err != nil || !deleted {
+		t.Fatalf("Delete returned %t (%v) when it should have deleted the key", deleted, err)
+	}
+	if _, ok, _ := store2.Get("endpoint"); ok {
+		t.Fatal("A deleted key was still present")
+	}
+}
+
+func TestStoreFull(t *testing.T) {
+	store, err := winkv.Create(testStoreName("Full"), 4, 8, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for i := range 4 {
+		if err := store.Put(fmt.Sprintf("key%d", i), []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Put("key4", []byte("value")); !errors.Is(err, winkv.ErrFull) {
+		t.Fatalf("Put returned %v when it should have returned %v", err, winkv.ErrFull)
+	}
+
+	// Deleting an entry should make room for another, and the remaining
+	// entries should still be reachable past the tombstone.
+	if _, err := store.Delete("key0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("key4", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"key1", "key2", "key3", "key4"} {
+		if _, ok, err := store.Get(key); err != nil || !ok {
+			t.Fatalf("The %s key was not found (%v)", key, err)
+		}
+	}
+}
+
+func TestStoreLimits(t *testing.T) {
+	store, err := winkv.Create(testStoreName("Limits"), 4, 4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.Put("too-long", nil); !errors.Is(err, winkv.ErrKeyTooLarge) {
+		t.Fatalf("Put returned %v when it should have returned %v", err, winkv.ErrKeyTooLarge)
+	}
+	if err := store.Put("key", []byte("too large")); !errors.Is(err, winkv.ErrValueTooLarge) {
+		t.Fatalf("Put returned %v when it should have returned %v", err, winkv.ErrValueTooLarge)
+	}
+}
+
+func TestStoreIncompatible(t *testing.T) {
+	name := testStoreName("Incompatible")
+
+	store, err := winkv.Create(name, 4, 4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if _, err := winkv.Create(name, 8, 4, 4); !errors.Is(err, winkv.ErrIncompatible) {
+		t.Fatalf("Create returned %v when it should have returned %v", err, winkv.ErrIncompatible)
+	}
+}
+
+func testStoreName(name string) string {
+	return "WinObj-WinKV-Test-" + name
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+// Package winlease provides leases that are shared between processes on
+// Windows.
+//
+// A lease is a lock that expires unless its holder renews it. The expiry
+// is recorded in a named shared memory section, so a holder that hangs
+// loses the lease once its time to live elapses, and a waiting process
+// takes it over. A lease is useful when a plain mutex would leave other
+// processes blocked forever behind a holder that is alive but stuck.
+//
+// The holder of a lease also holds a named mutex for as long as it has
+// the lease. If the holder exits without releasing the lease, such as
+// when it crashes, the mutex is abandoned, and a waiting process takes
+// over the lease immediately instead of waiting for it to expire.
+//
+// Expiry is measured with the time since the system booted, which is the
+// same in every process and is not affected by changes to the system
+// clock.
+package winlease
@@ -0,0 +1,422 @@
+//go:build windows
+
+package winlease
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"github.com/gentlemanautomaton/winobj/winshm"
+	"golang.org/x/sys/windows"
+)
+
+// Errors returned by the lease.
+var (
+	ErrLost    = errors.New("winlease: the lease expired and was taken over by another holder")
+	ErrNotHeld = errors.New("winlease: the lease is not held")
+	ErrHeld    = errors.New("winlease: the lease is already held by this Lease")
+)
+
+// Version is the version of the lease layout written by this package.
+const Version = 1
+
+// Suffixes appended to the name of a lease to form the names of the
+// objects that accompany it.
+const (
+	LockSuffix   = "-Lock"
+	HolderSuffix = "-Holder"
+)
+
+// pollInterval is the longest time that a waiter goes without checking
+// the lease, for leases whose holder does not hold the holder mutex.
+const pollInterval = 100 * time.Millisecond
+
+// schema identifies a section that holds a lease.
+var schema = winshm.Schema{Magic: "WINOBJLS", Version: Version}
+
+// Layout of the lease state.
+const (
+	offsetToken   = 0  // Incremented each time the lease is acquired
+	offsetExpires = 8  // Time since boot at which the lease expires, or zero if free
+	offsetHolder  = 16 // The ID of the process that holds the lease
+	offsetLocked  = 20 // Nonzero if the holder holds the holder mutex
+	stateSize     = 24
+)
+
+// Lease is a lease with a time to live, shared by every process that uses
+// the same name.
+//
+// A Lease is safe for concurrent use, but it is held on behalf of the
+// process as a whole rather than a particular goroutine.
+type Lease struct {
+	name    string
+	ttl     time.Duration
+	section *winshm.Section
+	guard   *winmutex.Mutex // Held while the lease state is read or written
+	holder  *winmutex.Mutex // Held by the holder of the lease, when possible
+
+	mu      sync.Mutex
+	token   uint64 // The token of the lease while it is held, or zero
+	expires time.Duration
+	holding bool // True while holder is locked by this Lease
+}
+
+// New returns a Lease with the given name and time to live, creating the
+// objects that make it up if they do not exist. It does not acquire the
+// lease.
+//
+// Every process that uses the lease should give the same time to live,
+// because it is applied by the holder each time the lease is acquired or
+// renewed.
+//
+// Options such as winobj.WithSecurityDescriptor are applied to each of the
+// objects that make up a new lease.
+//
+// It is the caller's responsibility to close the lease.
+func New(name string, ttl time.Duration, opts ...winobj.Option) (*Lease, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("winlease: the time to live of the %s lease must be positive", name)
+	}
+
+	l := &Lease{name: name, ttl: ttl}
+
+	var err error
+	if l.guard, err = winmutex.New(name+LockSuffix, opts...); err != nil {
+		return nil, err
+	}
+	if l.holder, err = winmutex.New(name+HolderSuffix, opts...); err != nil {
+		l.guard.Close()
+		return nil, err
+	}
+
+	// A new section is zeroed, which describes a lease that is free.
+	if l.section, _, err = winshm.Create(name, schema, stateSize, opts...); err != nil {
+		l.holder.Close()
+		l.guard.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Name returns the name of the lease.
+func (l *Lease) Name() string {
+	return l.name
+}
+
+// TTL returns the time to live of the lease.
+func (l *Lease) TTL() time.Duration {
+	return l.ttl
+}
+
+// Acquire acquires the lease, blocking until it is free, until it expires
+// or its holder exits, or until ctx is cancelled. When successful, it
+// returns a function that releases the lease.
+//
+// The lease must be renewed with Renew before its time to live elapses,
+// or it may be taken over by another process.
+//
+// Acquire allows l to be used as a winobj.Locker.
+func (l *Lease) Acquire(ctx context.Context) (release func(), err error) {
+	for {
+		current, err := l.tryAcquire()
+		if err != nil {
+			return nil, err
+		}
+		if current.acquired {
+			return func() { l.Release() }, nil
+		}
+
+		wait := max(current.expires-windows.DurationSinceBoot(), time.Millisecond)
+		if !current.locked {
+			// The holder could not lock the holder mutex, because the
+			// previous holder still held it when the lease was taken
+			// over, so the lease is checked again periodically.
+			timer := time.NewTimer(min(wait, pollInterval))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
+
+		// Wait until the holder releases the holder mutex, abandons it by
+		// exiting, or the lease expires.
+		waitCtx, cancel := context.WithTimeout(ctx, wait)
+		abandoned, err := winmutex.WaitForRelease(waitCtx, l.holder.Name(), winmutex.WithoutPrefix())
+		cancel()
+
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case errors.Is(err, context.DeadlineExceeded):
+		case err != nil:
+			return nil, fmt.Errorf("winlease: failed to wait for the %s lease: %w", l.name, err)
+		case abandoned:
+			// The holder exited without releasing the lease. Expire it so
+			// that it can be taken over immediately.
+			if err := l.expire(current.token); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// TryAcquire acquires the lease if it is free or has expired, without
+// waiting, and reports whether it succeeded.
+func (l *Lease) TryAcquire() (bool, error) {
+	current, err := l.tryAcquire()
+	return current.acquired, err
+}
+
+// Renew extends the lease so that it expires after its time to live has
+// elapsed from now.
+//
+// If the lease expired and was taken over by another holder, it returns
+// ErrLost and l no longer holds the lease. If l does not hold the lease,
+// it returns ErrNotHeld.
+func (l *Lease) Renew() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.token == 0 {
+		return ErrNotHeld
+	}
+
+	release, err := l.lock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if l.stateToken() != l.token {
+		l.drop()
+		return fmt.Errorf("%w: %s", ErrLost, l.name)
+	}
+
+	l.expires = windows.DurationSinceBoot() + l.ttl
+	l.setExpires(l.expires)
+
+	// Take the holder mutex if the previous holder kept it when the lease
+	// was taken over, so that crashes are detected from now on.
+	if !l.holding {
+		l.holding, _ = l.holder.TryLockE()
+		l.setLocked(l.holding)
+	}
+	return nil
+}
+
+// Release releases the lease, so that it can be acquired by another
+// process without waiting for it to expire.
+//
+// If the lease expired and was taken over by another holder, it returns
+// ErrLost. If l does not hold the lease, it returns ErrNotHeld.
+func (l *Lease) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.token == 0 {
+		return ErrNotHeld
+	}
+
+	release, err := l.lock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	lost := l.stateToken() != l.token
+	if !lost {
+		l.setExpires(0)
+		l.setHolder(0)
+		l.setLocked(false)
+	}
+	l.drop()
+
+	if lost {
+		return fmt.Errorf("%w: %s", ErrLost, l.name)
+	}
+	return nil
+}
+
+// Held reports whether l holds the lease and it has not expired. A lease
+// that has expired may still be renewed if no other process has taken it
+// over.
+func (l *Lease) Held() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.token != 0 && windows.DurationSinceBoot() < l.expires
+}
+
+// Token returns the fencing token of the lease while l holds it, or zero
+// if it does not. The token increases each time the lease is acquired by
+// any process, so it can be passed to other systems to reject work from
+// holders that lost the lease.
+func (l *Lease) Token() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.token
+}
+
+// Holder returns the ID of the process that holds the lease. If the lease
+// is free or has expired, ok is false.
+func (l *Lease) Holder() (pid uint32, ok bool, err error) {
+	release, err := l.lock()
+	if err != nil {
+		return 0, false, err
+	}
+	defer release()
+
+	if l.expired() {
+		return 0, false, nil
+	}
+	return l.stateHolder(), true, nil
+}
+
+// Close closes the objects that make up the lease. If l holds the lease,
+// it is released first.
+func (l *Lease) Close() error {
+	var err1 error
+	if err := l.Release(); err != nil && !errors.Is(err, ErrNotHeld) && !errors.Is(err, ErrLost) {
+		err1 = err
+	}
+	err2 := l.section.Close()
+	err3 := l.holder.Close()
+	err4 := l.guard.Close()
+	return errors.Join(err1, err2, err3, err4)
+}
+
+// attempt is the outcome of an attempt to acquire the lease. If the lease
+// was not acquired, it describes the holder that prevented it.
+type attempt struct {
+	acquired bool
+	token    uint64        // The token of the holder
+	expires  time.Duration // The time since boot at which the holder's lease expires
+	locked   bool          // True if the holder holds the holder mutex
+}
+
+// tryAcquire acquires the lease if it is free or has expired.
+func (l *Lease) tryAcquire() (attempt, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.token != 0 {
+		return attempt{}, fmt.Errorf("%w: %s", ErrHeld, l.name)
+	}
+
+	release, err := l.lock()
+	if err != nil {
+		return attempt{}, err
+	}
+	defer release()
+
+	if !l.expired() {
+		return attempt{
+			token:   l.stateToken(),
+			expires: l.stateExpires(),
+			locked:  l.stateLocked(),
+		}, nil
+	}
+
+	l.token = l.stateToken() + 1
+	l.expires = windows.DurationSinceBoot() + l.ttl
+	l.setToken(l.token)
+	l.setExpires(l.expires)
+	l.setHolder(windows.GetCurrentProcessId())
+
+	// A previous holder whose lease was taken over may still hold the
+	// holder mutex, in which case it is taken when the lease is renewed.
+	l.holding, _ = l.holder.TryLockE()
+	l.setLocked(l.holding)
+	return attempt{acquired: true}, nil
+}
+
+// expire expires the lease if it is still held with the given token by a
+// holder that held the holder mutex. It is called once the holder mutex
+// has been abandoned, which shows that its holder exited.
+func (l *Lease) expire(holderToken uint64) error {
+	release, err := l.lock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if l.stateToken() == holderToken && l.stateLocked() {
+		l.setExpires(0)
+		l.setHolder(0)
+		l.setLocked(false)
+	}
+	return nil
+}
+
+// drop forgets that l holds the lease and unlocks the holder mutex. The
+// caller must hold l.mu.
+func (l *Lease) drop() {
+	l.token, l.expires = 0, 0
+	if l.holding {
+		l.holder.Unlock()
+		l.holding = false
+	}
+}
+
+// lock acquires the mutex that guards the lease state.
+func (l *Lease) lock() (release func(), err error) {
+	release, err = l.guard.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("winlease: failed to lock the %s lease: %w", l.name, err)
+	}
+	return release, nil
+}
+
+// expired reports whether the lease is free or has expired. The caller
+// must hold the guard mutex.
+func (l *Lease) expired() bool {
+	expires := l.stateExpires()
+	return expires == 0 || windows.DurationSinceBoot() >= expires
+}
+
+func (l *Lease) stateToken() uint64 {
+	return binary.LittleEndian.Uint64(l.section.Bytes()[offsetToken:])
+}
+
+func (l *Lease) setToken(token uint64) {
+	binary.LittleEndian.PutUint64(l.section.Bytes()[offsetToken:], token)
+}
+
+func (l *Lease) stateExpires() time.Duration {
+	return time.Duration(binary.LittleEndian.Uint64(l.section.Bytes()[offsetExpires:]))
+}
+
+func (l *Lease) setExpires(expires time.Duration) {
+	binary.LittleEndian.PutUint64(l.section.Bytes()[offsetExpires:], uint64(expires))
+}
+
+func (l *Lease) stateHolder() uint32 {
+	return binary.LittleEndian.Uint32(l.section.Bytes()[offsetHolder:])
+}
+
+func (l *Lease) setHolder(pid uint32) {
+	binary.LittleEndian.PutUint32(l.section.Bytes()[offsetHolder:], pid)
+}
+
+func (l *Lease) stateLocked() bool {
+	return binary.LittleEndian.Uint32(l.section.Bytes()[offsetLocked:]) != 0
+}
+
+func (l *Lease) setLocked(locked bool) {
+	var value uint32
+	if locked {
+		value = 1
+	}
+	binary.LittleEndian.PutUint32(l.section.Bytes()[offsetLocked:], value)
+}
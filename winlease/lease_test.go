@@ -0,0 +1,174 @@
+//go:build windows
+
+package winlease_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winlease"
+	"golang.org/x/sys/windows"
+)
+
+const helperEnvVar = "WINOBJ_WINLEASE_TEST_HELPER"
+
+func TestMain(m *testing.M) {
+	// When started as a helper, acquire the named lease and exit without
+	// releasing it.
+	if name := os.Getenv(helperEnvVar); name != "" {
+		os.Exit(runHelper(name))
+	}
+	os.Exit(m.Run())
+}
+
+func runHelper(name string) int {
+	lease, err := winlease.New(name, time.Hour)
+	if err != nil {
+		return 1
+	}
+	if acquired, err := lease.TryAcquire(); err != nil || !acquired {
+		return 2
+	}
+	return 0
+}
+
+func TestLeaseAcquireRelease(t *testing.T) {
+	name := testLeaseName("AcquireRelease")
+
+	lease1, err := winlease.New(name, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease1.Close()
+
+	lease2, err := winlease.New(name, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease2.Close()
+
+	release, err := lease1.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lease1.Held() || lease1.Token() == 0 {
+		t.Fatal("The lease was acquired but is not reported as held")
+	}
+	if pid, ok, err := lease2.Holder(); err != nil || !ok || pid != windows.GetCurrentProcessId() {
+		t.Fatalf("Holder returned %d, %t (%v) when this process held the lease", pid, ok, err)
+	}
+
+	if acquired, err := lease2.TryAcquire(); err != nil || acquired {
+		t.Fatalf("TryAcquire returned %t (%v) while the lease was held", acquired, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := lease2.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire returned %v while the lease was held", err)
+	}
+
+	if err := lease1.Renew(); err != nil {
+		t.Fatal(err)
+	}
+
+	token := lease1.Token()
+	release()
+	if lease1.Held() {
+		t.Fatal("The lease was reported as held after it was released")
+	}
+	if err := lease1.Renew(); !errors.Is(err, winlease.ErrNotHeld) {
+		t.Fatalf("Renew returned %v instead of ErrNotHeld after the lease was released", err)
+	}
+
+	if acquired, err := lease2.TryAcquire(); err != nil || !acquired {
+		t.Fatalf("TryAcquire returned %t (%v) after the lease was released", acquired, err)
+	}
+	if lease2.Token() <= token {
+		t.Fatalf("The token %d was not greater than the previous token %d", lease2.Token(), token)
+	}
+	if err := lease2.Release(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	name := testLeaseName("Expiry")
+
+	lease1, err := winlease.New(name, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease1.Close()
+
+	lease2, err := winlease.New(name, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease2.Close()
+
+	if acquired, err := lease1.TryAcquire(); err != nil || !acquired {
+		t.Fatalf("TryAcquire returned %t (%v)", acquired, err)
+	}
+
+	// The first holder stops renewing, so the second takes over once the
+	// lease expires.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	release, err := lease2.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	if waited := time.Since(start); waited < 100*time.Millisecond {
+		t.Fatalf("The lease was taken over after %s, before it expired", waited)
+	}
+
+	if err := lease1.Renew(); !errors.Is(err, winlease.ErrLost) {
+		t.Fatalf("Renew returned %v instead of ErrLost after the lease was taken over", err)
+	}
+	if lease1.Held() {
+		t.Fatal("The lease was reported as held after it was lost")
+	}
+
+	// The new holder takes the holder mutex once the previous holder
+	// has let it go.
+	if err := lease2.Renew(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLeaseHolderExit(t *testing.T) {
+	name := testLeaseName("HolderExit")
+
+	lease, err := winlease.New(name, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease.Close()
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), helperEnvVar+"="+name)
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("The helper failed to acquire the lease: %v", err)
+	}
+
+	// The helper exited without releasing the lease, which abandoned the
+	// holder mutex, so the lease is taken over without waiting an hour.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	release, err := lease.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
+
+func testLeaseName(name string) string {
+	return "WinObj-WinLease-Test-" + name
+}
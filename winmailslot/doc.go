@@ -0,0 +1,9 @@
+//go:build windows
+
+// Package winmailslot provides access to mailslots on Windows.
+//
+// A mailslot is a kernel object that receives datagrams. Any number of
+// processes can write messages to a mailslot, while only the process that
+// created it can read from it. Messages are delivered whole and in the
+// order they were written.
+package winmailslot
@@ -0,0 +1,203 @@
+//go:build windows
+
+package winmailslot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/winbase"
+	"golang.org/x/sys/windows"
+)
+
+// ErrClosed is returned when a mailslot is used after it has been closed.
+var ErrClosed = errors.New("winmailslot: the mailslot has been closed")
+
+// pollInterval is the number of milliseconds that a single read waits for
+// a message before checking whether the caller has given up.
+const pollInterval = 100
+
+// Mailslot is the receiving end of a mailslot.
+//
+// A Mailslot is safe for concurrent use by multiple goroutines.
+type Mailslot struct {
+	name string
+
+	mutex  sync.Mutex
+	handle syscall.Handle
+	buffer []byte
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Create creates a mailslot with the given name and returns its receiving
+// end. If name does not begin with "\\", it is treated as a local mailslot
+// name and the \\.\mailslot\ prefix is added to it.
+//
+// The maxMessageSize limits the size of messages that can be written to
+// the mailslot. A value of zero permits messages of any size. Options
+// such as winobj.WithSecurityDescriptor are applied to the mailslot.
+//
+// It is the caller's responsibility to close the mailslot when finished
+// with it.
+func Create(name string, maxMessageSize uint32, opts ...winobj.Option) (*Mailslot, error) {
+	path := Path(name)
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("winmailslot: failed to create %s: %w", path, err)
+	}
+
+	handle, err := winbase.CreateMailslot(path, maxMessageSize, pollInterval, options.SyscallSecurityAttributes())
+	if err != nil {
+		return nil, fmt.Errorf("winmailslot: failed to create %s: %w", path, err)
+	}
+	return &Mailslot{
+		name:   path,
+		handle: handle,
+		closed: make(chan struct{}),
+	}, nil
+}
+
+// Name returns the full path of the mailslot.
+func (m *Mailslot) Name() string {
+	return m.name
+}
+
+// Receive waits for the next message to arrive in the mailslot and
+// returns it. It returns early if ctx is cancelled or the mailslot is
+// closed.
+func (m *Mailslot) Receive(ctx context.Context) ([]byte, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-m.closed:
+			return nil, ErrClosed
+		default:
+		}
+
+		msg, ok, err := m.read()
+		if err != nil || ok {
+			return msg, err
+		}
+	}
+}
+
+// Listen receives messages from the mailslot and sends them to ch. It
+// blocks until ctx is cancelled, the mailslot is closed, or a read fails,
+// and returns the reason it stopped.
+func (m *Mailslot) Listen(ctx context.Context, ch chan<- []byte) error {
+	for {
+		msg, err := m.Receive(ctx)
+		if err != nil {
+			return err
+		}
+		select {
+		case ch <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.closed:
+			return ErrClosed
+		}
+	}
+}
+
+// read waits up to pollInterval for a message. It reports whether a
+// message was received.
+func (m *Mailslot) read() (msg []byte, ok bool, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.handle == 0 {
+		return nil, false, ErrClosed
+	}
+
+	if m.buffer == nil {
+		m.buffer = make([]byte, 4096)
+	}
+
+	var n uint32
+	err = windows.ReadFile(windows.Handle(m.handle), m.buffer, &n, nil)
+	switch err {
+	case nil:
+		msg = make([]byte, n)
+		copy(msg, m.buffer[:n])
+		return msg, true, nil
+	case windows.ERROR_SEM_TIMEOUT:
+		return nil, false, nil
+	case windows.ERROR_INSUFFICIENT_BUFFER:
+		// The next message is larger than the buffer. Grow the buffer so
+		// that the message will fit on the next attempt.
+		_, next, _, _, err := winbase.GetMailslotInfo(m.handle)
+		if err != nil {
+			return nil, false, fmt.Errorf("winmailslot: failed to query %s: %w", m.name, err)
+		}
+		if next != winbase.MailslotNoMessage && int(next) > len(m.buffer) {
+			m.buffer = make([]byte, next)
+		}
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("winmailslot: failed to read from %s: %w", m.name, err)
+	}
+}
+
+// Close closes the mailslot. Any pending calls to Receive or Listen
+// return ErrClosed.
+func (m *Mailslot) Close() error {
+	m.closeOnce.Do(func() { close(m.closed) })
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.handle == 0 {
+		return nil
+	}
+
+	err := syscall.CloseHandle(m.handle)
+	m.handle = 0
+
+	return err
+}
+
+// Send writes a message to the mailslot with the given name. If name does
+// not begin with "\\", it is treated as a local mailslot name and the
+// \\.\mailslot\ prefix is added to it.
+//
+// Names of the form \\computer\mailslot\name or \\*\mailslot\name can be
+// used to send to mailslots on other computers, subject to the size limits
+// of network mailslots.
+func Send(name string, msg []byte) error {
+	path := Path(name)
+	utf16Path, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+
+	h, err := windows.CreateFile(utf16Path, windows.GENERIC_WRITE, windows.FILE_SHARE_READ, nil, windows.OPEN_EXISTING, windows.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return fmt.Errorf("winmailslot: failed to open %s: %w", path, err)
+	}
+	defer windows.CloseHandle(h)
+
+	var n uint32
+	if err := windows.WriteFile(h, msg, &n, nil); err != nil {
+		return fmt.Errorf("winmailslot: failed to write to %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Path returns the full path of the mailslot with the given name. If name
+// already begins with "\\", it is returned unchanged.
+func Path(name string) string {
+	if strings.HasPrefix(name, `\\`) {
+		return name
+	}
+	return `\\.\mailslot\` + name
+}
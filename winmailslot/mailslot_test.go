@@ -0,0 +1,89 @@
+//go:build windows
+
+package winmailslot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmailslot"
+)
+
+func TestMailslotSendReceive(t *testing.T) {
+	name := testMailslotName("SendReceive")
+
+	slot, err := winmailslot.Create(name, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer slot.Close()
+
+	messages := []string{"first", "second", string(make([]byte, 10000))}
+	for _, msg := range messages {
+		if err := winmailslot.Send(name, []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i, want := range messages {
+		got, err := slot.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Fatalf("message %d: received %d bytes when %d bytes were expected", i, len(got), len(want))
+		}
+	}
+}
+
+func TestMailslotListen(t *testing.T) {
+	name := testMailslotName("Listen")
+
+	slot, err := winmailslot.Create(name, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer slot.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ch := make(chan []byte)
+	done := make(chan error, 1)
+	go func() {
+		done <- slot.Listen(ctx, ch)
+	}()
+
+	if err := winmailslot.Send(name, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-ch:
+		if string(msg) != "hello" {
+			t.Fatalf("received %q when \"hello\" was expected", msg)
+		}
+	case <-ctx.Done():
+		t.Fatal("the message was not received")
+	}
+
+	slot.Close()
+	if err := <-done; !errors.Is(err, winmailslot.ErrClosed) {
+		t.Fatalf("Listen returned %v when it should have returned %v", err, winmailslot.ErrClosed)
+	}
+}
+
+func TestMailslotSendWithoutReceiver(t *testing.T) {
+	if err := winmailslot.Send(testMailslotName("SendWithoutReceiver"), []byte("lost")); err == nil {
+		t.Fatal("a message was sent to a mailslot that does not exist")
+	}
+}
+
+func testMailslotName(name string) string {
+	return `WinObj\WinMailslot\Test\` + name
+}
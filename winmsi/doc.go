@@ -0,0 +1,10 @@
+//go:build windows
+
+// Package winmsi coordinates with Windows Installer on Windows.
+//
+// Windows Installer holds the Global\_MSIExecute mutex while an
+// installation is in progress, and rejects other installations with
+// ERROR_INSTALL_ALREADY_RUNNING until it is released. Deployment tools
+// can use this package to learn whether the installer is busy, or to wait
+// until it is idle before starting an installation of their own.
+package winmsi
@@ -0,0 +1,95 @@
+//go:build windows
+
+package winmsi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+// MutexName is the name of the mutex that Windows Installer holds while
+// an installation is in progress. It is used as given, without the
+// default prefix set by winobj.SetDefaultPrefix.
+const MutexName = `Global\_MSIExecute`
+
+// Settle is the period for which WaitForInstallerIdle first confirms that
+// the installer remains idle. Chained installations, such as a bundle that
+// installs several packages in turn, release the mutex briefly between
+// packages.
+const Settle = 2 * time.Second
+
+// MaxSettle is the longest period for which WaitForInstallerIdle confirms
+// that the installer remains idle. The period doubles from Settle each
+// time the installer turns out to be busy again, up to MaxSettle.
+const MaxSettle = 30 * time.Second
+
+// InstallerBusy reports whether Windows Installer is running an
+// installation.
+//
+// The mutex can exist while the installer is idle, because the Windows
+// Installer service keeps it open, so its existence is not enough to tell.
+// InstallerBusy acquires the mutex without waiting and releases it
+// immediately. It never creates the mutex.
+func InstallerBusy() (bool, error) {
+	mutex, err := winmutex.Open(MutexName, winmutex.WithoutPrefix())
+	if errors.Is(err, winmutex.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer mutex.Close()
+
+	locked, err := mutex.TryLockE()
+	if err != nil && !errors.Is(err, winmutex.ErrAbandoned) {
+		return false, err
+	}
+	if !locked {
+		return true, nil
+	}
+	if err := mutex.UnlockE(); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// WaitForInstallerIdle waits until Windows Installer is not running an
+// installation, or until ctx is cancelled.
+//
+// Once the installer becomes idle, WaitForInstallerIdle confirms that it
+// is still idle after Settle has elapsed. If it is not, it waits for the
+// installer again and doubles the settle period, up to MaxSettle, so that
+// a long chain of installations is not mistaken for an idle installer.
+//
+// The installer may start another installation as soon as
+// WaitForInstallerIdle returns. Callers that start an installation of
+// their own should still be prepared to retry if it fails with
+// ERROR_INSTALL_ALREADY_RUNNING.
+func WaitForInstallerIdle(ctx context.Context) error {
+	settle := Settle
+	for {
+		if _, err := winmutex.WaitForRelease(ctx, MutexName, winmutex.WithoutPrefix()); err != nil {
+			return err
+		}
+
+		timer := time.NewTimer(settle)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		busy, err := InstallerBusy()
+		if err != nil {
+			return err
+		}
+		if !busy {
+			return nil
+		}
+		settle = min(settle*2, MaxSettle)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package winmsi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmsi"
+)
+
+func TestInstallerBusy(t *testing.T) {
+	busy, err := winmsi.InstallerBusy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if busy {
+		t.Skip("Windows Installer is running an installation")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), winmsi.Settle+5*time.Second)
+	defer cancel()
+
+	if err := winmsi.WaitForInstallerIdle(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
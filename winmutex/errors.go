@@ -0,0 +1,58 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// ErrAccessDenied is returned when a mutex exists but the caller lacks the
+// access rights needed to create or open it, such as when a non-elevated
+// process opens a Global\ mutex created by a service.
+var ErrAccessDenied = errors.New("winmutex: access to the mutex was denied")
+
+// ErrInvalidName is returned when a mutex name is rejected by the system.
+var ErrInvalidName = errors.New("winmutex: the mutex name is invalid")
+
+// ErrObjectTypeMismatch is returned when a mutex cannot be created or
+// opened because its name is in use by another kind of object, such as an
+// event or a semaphore.
+var ErrObjectTypeMismatch = errors.New("winmutex: the name is in use by an object that is not a mutex")
+
+// systemError is a system error that also matches one of the sentinel
+// errors of this package. Both can be detected with errors.Is.
+type systemError struct {
+	sentinel error
+	err      error
+}
+
+func (e systemError) Error() string {
+	return e.err.Error()
+}
+
+func (e systemError) Unwrap() []error {
+	return []error{e.sentinel, e.err}
+}
+
+// mapError returns err wrapped so that it matches the sentinel error of
+// this package that corresponds to it, if any. Otherwise it returns err.
+func mapError(err error) error {
+	var sentinel error
+	switch err {
+	case windows.ERROR_ACCESS_DENIED:
+		sentinel = ErrAccessDenied
+	case windows.ERROR_FILE_NOT_FOUND, windows.ERROR_PATH_NOT_FOUND:
+		sentinel = ErrNotFound
+	case windows.ERROR_INVALID_NAME, windows.ERROR_BAD_PATHNAME:
+		sentinel = ErrInvalidName
+	case windows.ERROR_INVALID_HANDLE:
+		// CreateMutex and OpenMutex report this when the name belongs to
+		// an object of another type.
+		sentinel = ErrObjectTypeMismatch
+	default:
+		return err
+	}
+	return systemError{sentinel: sentinel, err: err}
+}
@@ -3,32 +3,99 @@
 package winmutex
 
 import (
-	"runtime"
+	"errors"
 	"syscall"
 
-	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"github.com/gentlemanautomaton/winobj"
 )
 
-// Exists returns true if a mutex with the given name exists.
-func Exists(name string) (bool, error) {
-	// Lock the operating system thread for the duration of this call, as
-	// the API calls require thread affinity.
-	runtime.LockOSThread()
-	defer runtime.UnlockOSThread()
+// Exists returns true if a mutex with the given name exists. The name may
+// be given as a plain string or as a winobj.Name.
+//
+// If the mutex exists but the caller is not permitted to open it, Exists
+// returns an error that matches ErrAccessDenied. Use ExistsDetail to treat
+// such a mutex as existing.
+//
+// Exists does not allocate or lock an operating system thread. Only mutex
+// ownership has thread affinity, and Exists never takes ownership of the
+// mutex it opens.
+func Exists[N winobj.ObjectName](name N) (bool, error) {
+	exists, denied, err := ExistsDetail(name)
+	if denied {
+		return false, mapError(syscall.ERROR_ACCESS_DENIED)
+	}
+	return exists, err
+}
 
+// ExistsDetail reports whether a mutex with the given name exists, and
+// whether the caller was denied access to it. A mutex that the caller
+// cannot open still exists, so exists is true whenever accessDenied is.
+// This commonly occurs when a non-elevated process probes a Global\ mutex
+// that was created by a service.
+//
+// The name may be given as a plain string or as a winobj.Name.
+func ExistsDetail[N winobj.ObjectName](name N) (exists, accessDenied bool, err error) {
 	// Attempt to open an existing mutex with the given name.
-	handle, err := synchapi.OpenMutex(name)
+	handle, err := openHandle(winobj.Qualify(name), winobj.Options{})
 	if err != nil {
-		if err, ok := (err).(syscall.Errno); ok {
-			if err == syscall.ERROR_FILE_NOT_FOUND {
-				return false, nil
-			}
+		switch err {
+		case syscall.ERROR_FILE_NOT_FOUND:
+			return false, false, nil
+		case syscall.ERROR_ACCESS_DENIED:
+			return true, true, nil
 		}
-		return false, err
+		return false, false, mapError(err)
 	}
 
 	// If we succeeded in opening the handle, be sure to close it.
 	defer syscall.CloseHandle(handle)
 
-	return true, nil
+	return true, false, nil
+}
+
+// ExistsAll reports which of the named mutexes exist. The returned map is
+// keyed by the names as they were given. A mutex that exists but cannot be
+// opened by the caller is reported as existing, as it is by ExistsDetail.
+//
+// Every name is checked, even if some of the checks fail. The map holds
+// the results of the checks that succeeded, and the error describes those
+// that did not.
+//
+// Like Exists, ExistsAll does not allocate or lock an operating system
+// thread, so probing many names is inexpensive.
+func ExistsAll[N winobj.ObjectName](names ...N) (map[string]bool, error) {
+	results := make(map[string]bool, len(names))
+	var errs []error
+	for _, name := range names {
+		exists, _, err := ExistsDetail(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results[string(name)] = exists
+	}
+	return results, errors.Join(errs...)
+}
+
+// ExistsAny reports whether any of the named mutexes exist, and returns
+// the name of the first of them, in the order given, that does. A mutex
+// that exists but cannot be opened by the caller is reported as existing,
+// as it is by ExistsDetail.
+//
+// If a check fails before an existing mutex is found, ExistsAny continues
+// with the remaining names. The error is returned only if none of them
+// exist.
+func ExistsAny[N winobj.ObjectName](names ...N) (found N, exists bool, err error) {
+	var errs []error
+	for _, name := range names {
+		exists, _, err := ExistsDetail(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if exists {
+			return name, true, nil
+		}
+	}
+	return found, false, errors.Join(errs...)
 }
@@ -3,6 +3,7 @@
 package winmutex_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/gentlemanautomaton/winobj/winmutex"
@@ -39,3 +40,57 @@ func TestExistsShouldNotExist(t *testing.T) {
 		t.Fatalf("The winmutex.Exists() call returned true when it should have returned false")
 	}
 }
+
+func TestExistsDetailAccessDenied(t *testing.T) {
+	name := testMutexName("ExistsDetailAccessDenied")
+
+	// Deny all access to everyone, including the creator.
+	mutex, err := winmutex.New(name, winmutex.WithSDDL("D:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	exists, denied, err := winmutex.ExistsDetail(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists || !denied {
+		t.Fatalf("ExistsDetail returned exists = %t, accessDenied = %t for an inaccessible mutex", exists, denied)
+	}
+
+	if _, err := winmutex.Exists(name); !errors.Is(err, winmutex.ErrAccessDenied) {
+		t.Fatalf("Exists returned %v for an inaccessible mutex instead of ErrAccessDenied", err)
+	}
+}
+
+func TestExistsAllAny(t *testing.T) {
+	present := testMutexName("ExistsAllAny-Present")
+	missing := testMutexName("ExistsAllAny-Missing")
+
+	mutex, err := winmutex.New(present)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	results, err := winmutex.ExistsAll(missing, present)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[present] || results[missing] || len(results) != 2 {
+		t.Fatalf("ExistsAll returned %v", results)
+	}
+
+	found, exists, err := winmutex.ExistsAny(missing, present)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists || found != present {
+		t.Fatalf("ExistsAny returned %q, %t when it should have returned %q, true", found, exists, present)
+	}
+
+	if _, exists, err := winmutex.ExistsAny(missing); err != nil || exists {
+		t.Fatalf("ExistsAny returned %t, %v for a missing mutex", exists, err)
+	}
+}
@@ -0,0 +1,519 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"github.com/gentlemanautomaton/winobj/internal/lockedthread"
+	"golang.org/x/sys/windows"
+)
+
+// ErrGroupClosed is returned when a MutexGroup or one of its members is
+// used after the group has been closed.
+var ErrGroupClosed = errors.New("winmutex: the mutex group has been closed")
+
+// MutexGroup owns a single locked operating system thread on which all of
+// its member mutexes are locked and unlocked. It is a lighter-weight
+// alternative to Mutex for components that manage a known set of locks,
+// because the members consume one thread between them no matter how many
+// of them are locked or being waited for.
+//
+// The group's thread waits for every member that is being locked at once,
+// so a member that is unavailable does not delay operations on the others.
+// No more than MaxLockAll-1 members are waited for at the same time;
+// further lock attempts wait their turn.
+//
+// A MutexGroup is safe for concurrent use by multiple goroutines.
+type MutexGroup struct {
+	thread *lockedthread.Thread
+	wake   syscall.Handle // Signaled when calls or waiters are queued
+	done   chan struct{}  // Closed once the group's thread has stopped
+	leaked bool           // True if a member could not be released; only accessed on the group's thread
+
+	mutex   sync.Mutex
+	calls   []func()       // Run on the group's thread
+	waiters []*groupWaiter // Members that are waiting to be locked
+	members []*GroupMutex
+	closed  bool
+}
+
+// groupWaiter is a pending attempt to lock a member of a MutexGroup.
+type groupWaiter struct {
+	member *GroupMutex
+	result chan error // Receives nil or ErrAbandoned once the member is locked
+}
+
+// GroupMutex is a system mutex that belongs to a MutexGroup. Like Mutex,
+// it can be held by only one goroutine at a time and is not reentrant.
+type GroupMutex struct {
+	group *MutexGroup
+	name  string
+	gate  chan struct{} // Holds a token while a goroutine holds or is locking m
+
+	closed bool // Guarded by the group's mutex
+
+	// The following fields are only accessed on the group's thread.
+	handle syscall.Handle // Zero once m has been closed
+	locked bool
+}
+
+// NewMutexGroup returns a MutexGroup with a locked operating system thread
+// of its own, which counts toward the limit set by SetThreadLimit.
+//
+// It is the caller's responsibility to close the group, which closes all
+// of its members.
+func NewMutexGroup() (*MutexGroup, error) {
+	return NewMutexGroupContext(context.Background())
+}
+
+// NewMutexGroupContext returns a MutexGroup in the same way as
+// NewMutexGroup. If the limit set by SetThreadLimit has been reached and
+// is configured to wait, it stops waiting for a thread when ctx is done.
+func NewMutexGroupContext(ctx context.Context) (*MutexGroup, error) {
+	wake, _, err := synchapi.CreateEventEx("", nil, 0, synchapi.EventAllAccess)
+	if err != nil {
+		return nil, fmt.Errorf("winmutex: failed to create a wake event for a mutex group: %w", err)
+	}
+
+	thread, err := getThread(ctx)
+	if err != nil {
+		syscall.CloseHandle(wake)
+		return nil, fmt.Errorf("winmutex: failed to create a mutex group: %w", err)
+	}
+
+	g := &MutexGroup{
+		thread: thread,
+		wake:   wake,
+		done:   make(chan struct{}),
+	}
+	go g.run()
+
+	return g, nil
+}
+
+// New returns a member of g for the system mutex with the given name,
+// creating the mutex if it does not already exist. The name and options
+// are interpreted as they are by New, except that WithInitialOwner is not
+// supported.
+func (g *MutexGroup) New(name string, opts ...Option) (*GroupMutex, error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(winobj.Qualify(name)), err)
+	}
+	qualified := options.QualifyName(name)
+
+	handle, _, err := createHandle(qualified, false, options)
+	if err != nil {
+		return nil, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(qualified), mapError(err))
+	}
+
+	return g.add(qualified, handle)
+}
+
+// Open returns a member of g for the existing system mutex with the given
+// name. The name and options are interpreted as they are by Open. If the
+// mutex does not exist, it returns an error that wraps ErrNotFound.
+func (g *MutexGroup) Open(name string, opts ...Option) (*GroupMutex, error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("winmutex: failed to open %s: %w", mutexDescription(winobj.Qualify(name)), err)
+	}
+	qualified := options.QualifyName(name)
+
+	handle, err := openHandle(qualified, options)
+	if err != nil {
+		return nil, fmt.Errorf("winmutex: failed to open %s: %w", mutexDescription(qualified), mapError(err))
+	}
+
+	return g.add(qualified, handle)
+}
+
+// Close closes every member of g, unlocking those that are locked, and
+// releases the group's thread. Pending calls to Lock and LockContext on
+// its members return ErrGroupClosed. Calling Close more than once has no
+// further effect.
+func (g *MutexGroup) Close() error {
+	var errs []error
+	err := g.call(func() {
+		for _, w := range g.takeWaiters() {
+			w.result <- ErrGroupClosed
+		}
+
+		g.mutex.Lock()
+		members := g.members
+		g.members = nil
+		g.closed = true
+		g.mutex.Unlock()
+
+		for _, m := range members {
+			if err := m.close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+	if err == ErrGroupClosed {
+		<-g.done
+		return nil
+	}
+	<-g.done
+
+	return errors.Join(errs...)
+}
+
+// add registers a member of g with the given qualified name and handle.
+func (g *MutexGroup) add(name string, handle syscall.Handle) (*GroupMutex, error) {
+	m := &GroupMutex{
+		group:  g,
+		name:   name,
+		gate:   make(chan struct{}, 1),
+		handle: handle,
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.closed {
+		syscall.CloseHandle(handle)
+		return nil, ErrGroupClosed
+	}
+	g.members = append(g.members, m)
+
+	return m, nil
+}
+
+// call runs fn on the group's thread and waits for it to return. It
+// returns ErrGroupClosed without running fn if g has been closed.
+func (g *MutexGroup) call(fn func()) error {
+	done := make(chan struct{})
+
+	g.mutex.Lock()
+	if g.closed {
+		g.mutex.Unlock()
+		return ErrGroupClosed
+	}
+	g.calls = append(g.calls, func() {
+		defer close(done)
+		fn()
+	})
+	g.mutex.Unlock()
+
+	synchapi.SetEvent(g.wake)
+	<-done
+
+	return nil
+}
+
+// lock waits until member m is locked on the group's thread or ctx is
+// cancelled.
+func (g *MutexGroup) lock(ctx context.Context, m *GroupMutex) error {
+	w := &groupWaiter{member: m, result: make(chan error, 1)}
+
+	g.mutex.Lock()
+	switch {
+	case g.closed:
+		g.mutex.Unlock()
+		return ErrGroupClosed
+	case m.closed:
+		g.mutex.Unlock()
+		return groupMutexClosedError("LockContext")
+	}
+	g.waiters = append(g.waiters, w)
+	g.mutex.Unlock()
+
+	synchapi.SetEvent(g.wake)
+
+	select {
+	case err := <-w.result:
+		return err
+	case <-ctx.Done():
+		if g.removeWaiter(w) {
+			synchapi.SetEvent(g.wake)
+			return ctx.Err()
+		}
+		// The member was locked before the wait could be abandoned.
+		return <-w.result
+	}
+}
+
+// removeWaiter removes w from the list of waiters and reports whether it
+// was present.
+func (g *MutexGroup) removeWaiter(w *groupWaiter) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	i := slices.Index(g.waiters, w)
+	if i < 0 {
+		return false
+	}
+	g.waiters = slices.Delete(g.waiters, i, i+1)
+	return true
+}
+
+// takeWaiters removes and returns every waiter.
+func (g *MutexGroup) takeWaiters() []*groupWaiter {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	waiters := g.waiters
+	g.waiters = nil
+	return waiters
+}
+
+// run serves g on its thread until g is closed, and then returns the
+// thread to the pool.
+func (g *MutexGroup) run() {
+	var healthy bool
+	g.thread.Run(func() {
+		healthy = g.serve()
+	})
+
+	// A thread that failed to release a mutex may still own it, so it
+	// must not be reused.
+	if healthy {
+		lockedthread.Put(g.thread)
+	} else {
+		g.thread.Close()
+	}
+	syscall.CloseHandle(g.wake)
+	close(g.done)
+}
+
+// serve runs queued calls and waits for members to become available. It
+// returns once g has been closed, reporting whether every mutex locked on
+// the thread was released.
+func (g *MutexGroup) serve() (healthy bool) {
+	handles := make([]syscall.Handle, 0, MaxLockAll)
+	for {
+		g.mutex.Lock()
+		calls := g.calls
+		g.calls = nil
+		waiters := slices.Clone(g.waiters[:min(len(g.waiters), MaxLockAll-1)])
+		closed := g.closed
+		g.mutex.Unlock()
+
+		for _, call := range calls {
+			call()
+		}
+		if len(calls) > 0 {
+			// Calls may have changed the waiters, so take another look.
+			continue
+		}
+		if closed {
+			return !g.leaked
+		}
+
+		handles = append(handles[:0], g.wake)
+		for _, w := range waiters {
+			handles = append(handles, w.member.handle)
+		}
+
+		result, err := synchapi.WaitForMultipleObjects(handles, false, windows.INFINITE)
+		if err != nil {
+			for _, w := range waiters {
+				if g.removeWaiter(w) {
+					w.result <- fmt.Errorf("winmutex: failed to lock %s: %w", mutexDescription(w.member.name), err)
+				}
+			}
+			continue
+		}
+
+		var (
+			index     uint32
+			abandoned bool
+		)
+		switch n := uint32(len(handles)); {
+		case result < windows.WAIT_OBJECT_0+n:
+			index = result - windows.WAIT_OBJECT_0
+		case result >= windows.WAIT_ABANDONED && result < windows.WAIT_ABANDONED+n:
+			index = result - windows.WAIT_ABANDONED
+			abandoned = true
+		default:
+			continue
+		}
+		if index == 0 {
+			continue
+		}
+
+		w := waiters[index-1]
+		if !g.removeWaiter(w) {
+			// The wait was abandoned after the mutex was acquired.
+			synchapi.ReleaseMutex(w.member.handle)
+			continue
+		}
+		w.member.locked = true
+		if abandoned {
+			w.result <- ErrAbandoned
+		} else {
+			w.result <- nil
+		}
+	}
+}
+
+// Name returns the name of the mutex, including any default prefix that
+// was applied to it.
+func (m *GroupMutex) Name() string {
+	return m.name
+}
+
+// Lock locks m, blocking until it is available. It panics if m or its
+// group is closed. If the mutex was abandoned by its previous owner, Lock
+// succeeds.
+func (m *GroupMutex) Lock() {
+	if err := m.LockContext(context.Background()); err != nil && err != ErrAbandoned {
+		panic(err)
+	}
+}
+
+// LockContext locks m, blocking until it is available or ctx is
+// cancelled. If ctx is cancelled first, it returns ctx.Err() and m is not
+// locked.
+//
+// If the mutex was abandoned by its previous owner, LockContext returns
+// ErrAbandoned with m locked.
+func (m *GroupMutex) LockContext(ctx context.Context) error {
+	select {
+	case m.gate <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	err := m.group.lock(ctx, m)
+	if err != nil && err != ErrAbandoned {
+		<-m.gate
+	}
+	return err
+}
+
+// TryLock tries to lock m without waiting and reports whether it
+// succeeded. If m is held or being locked by another goroutine in this
+// process, it returns false immediately. It panics if m or its group is
+// closed.
+func (m *GroupMutex) TryLock() bool {
+	select {
+	case m.gate <- struct{}{}:
+	default:
+		return false
+	}
+
+	var (
+		locked bool
+		err    error
+	)
+	if callErr := m.group.call(func() {
+		if m.handle == 0 {
+			err = groupMutexClosedError("TryLock")
+			return
+		}
+		var event uint32
+		event, err = syscall.WaitForSingleObject(m.handle, 0)
+		switch {
+		case err != nil:
+		case event == syscall.WAIT_OBJECT_0, event == synchapi.WaitAbandoned:
+			m.locked = true
+			locked = true
+		}
+	}); callErr != nil {
+		err = callErr
+	}
+
+	if !locked {
+		<-m.gate
+	}
+	if err != nil {
+		panic(err)
+	}
+	return locked
+}
+
+// Unlock unlocks m. It is a run-time error if m is not locked on entry to
+// Unlock.
+func (m *GroupMutex) Unlock() {
+	var err error
+	if callErr := m.group.call(func() {
+		switch {
+		case m.handle == 0:
+			err = groupMutexClosedError("Unlock")
+		case !m.locked:
+			err = fmt.Errorf("winmutex: GroupMutex.Unlock(): %w", ErrNotLocked)
+		default:
+			if _, err = synchapi.ReleaseMutex(m.handle); err == nil {
+				m.locked = false
+			}
+		}
+	}); callErr != nil {
+		err = callErr
+	}
+	if err != nil {
+		panic(err)
+	}
+	<-m.gate
+}
+
+// Close closes m, unlocking it if it is locked, and removes it from its
+// group. A pending call to LockContext on m returns an error, and a
+// pending call to Lock panics. Calling Close more than once has no
+// further effect.
+func (m *GroupMutex) Close() error {
+	var err error
+	if callErr := m.group.call(func() {
+		g := m.group
+		g.mutex.Lock()
+		if i := slices.Index(g.members, m); i >= 0 {
+			g.members = slices.Delete(g.members, i, i+1)
+		}
+		g.mutex.Unlock()
+
+		err = m.close()
+	}); callErr != nil && callErr != ErrGroupClosed {
+		return callErr
+	}
+	return err
+}
+
+// close releases m if it is locked and closes its handle. It must be
+// called on the group's thread.
+func (m *GroupMutex) close() error {
+	if m.handle == 0 {
+		return nil
+	}
+
+	// Stop waiting for m before its handle is closed.
+	g := m.group
+	g.mutex.Lock()
+	m.closed = true
+	g.waiters = slices.DeleteFunc(g.waiters, func(w *groupWaiter) bool {
+		if w.member != m {
+			return false
+		}
+		w.result <- groupMutexClosedError("LockContext")
+		return true
+	})
+	g.mutex.Unlock()
+
+	var errs []error
+	if m.locked {
+		if _, err := synchapi.ReleaseMutex(m.handle); err != nil {
+			errs = append(errs, fmt.Errorf("winmutex: failed to release %s: %w", mutexDescription(m.name), err))
+			g.leaked = true
+		}
+		m.locked = false
+	}
+	if err := syscall.CloseHandle(m.handle); err != nil {
+		errs = append(errs, fmt.Errorf("winmutex: failed to close %s: %w", mutexDescription(m.name), err))
+	}
+	m.handle = 0
+
+	return errors.Join(errs...)
+}
+
+func groupMutexClosedError(method string) error {
+	return fmt.Errorf("winmutex: GroupMutex.%s() called on a mutex that has been closed", method)
+}
@@ -0,0 +1,85 @@
+//go:build windows
+
+package winmutex_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+func TestMutexGroup(t *testing.T) {
+	group, err := winmutex.NewMutexGroup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer group.Close()
+
+	a, err := group.New(testMutexName("Group-A"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := group.New(testMutexName("Group-B"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold B from outside the group, so that waiting for it occupies the
+	// group's thread.
+	other, err := winmutex.New(testMutexName("Group-B"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	other.Lock()
+
+	waited := make(chan error, 1)
+	go func() {
+		waited <- b.LockContext(context.Background())
+	}()
+
+	// A must remain usable while B is being waited for.
+	a.Lock()
+	if other, err := winmutex.New(testMutexName("Group-A")); err != nil {
+		t.Fatal(err)
+	} else {
+		if other.TryLock() {
+			t.Errorf("A lock was acquired on a group member's mutex when it should have been blocked")
+		}
+		other.Close()
+	}
+	a.Unlock()
+
+	select {
+	case err := <-waited:
+		t.Fatalf("LockContext returned before the mutex was released: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	other.Unlock()
+	if err := <-waited; err != nil {
+		t.Fatal(err)
+	}
+	b.Unlock()
+
+	// Abandoning a wait must leave the member unlocked.
+	other.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := b.LockContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("LockContext returned %v when it should have timed out", err)
+	}
+	other.Unlock()
+	if !b.TryLock() {
+		t.Fatalf("A group member could not be locked after an abandoned wait")
+	}
+
+	if err := group.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.LockContext(context.Background()); err != winmutex.ErrGroupClosed {
+		t.Fatalf("LockContext returned %v after the group was closed", err)
+	}
+}
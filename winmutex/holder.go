@@ -0,0 +1,86 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/ntexapi"
+	"github.com/gentlemanautomaton/winobj/winhandle"
+	"golang.org/x/sys/windows"
+)
+
+// Holder returns the ID of the process that currently holds the named
+// mutex. If the mutex is not held, or if it does not exist, ok is false.
+// The name may be given as a plain string or as a winobj.Name.
+//
+// The owner is queried from the mutex itself. On versions of Windows that
+// do not report mutex owners, Holder falls back to enumerating the system
+// handle table and reports the holder only if exactly one other process
+// has the mutex open. Handle enumeration usually requires administrative
+// privileges.
+//
+// The result is a snapshot. The mutex may be released or acquired by
+// another process by the time Holder returns.
+func Holder[N winobj.ObjectName](name N) (pid uint32, ok bool, err error) {
+	qualified := winobj.Qualify(name)
+
+	handle, err := openHandle(qualified, winobj.Options{Access: ntexapi.MutantQueryState})
+	if err != nil {
+		if err == syscall.ERROR_FILE_NOT_FOUND {
+			return 0, false, nil
+		}
+		return 0, false, holderError(qualified, err)
+	}
+	defer syscall.CloseHandle(handle)
+	h := windows.Handle(handle)
+
+	owner, err := ntexapi.NtQueryMutantOwner(handle)
+	switch err {
+	case nil:
+		if owner.UniqueProcess == 0 {
+			return 0, false, nil
+		}
+		return uint32(owner.UniqueProcess), true, nil
+	case windows.STATUS_INVALID_INFO_CLASS:
+		if pid, ok, err = holderFromHandles(h); err != nil {
+			return 0, false, holderError(qualified, err)
+		}
+		return pid, ok, nil
+	default:
+		return 0, false, holderError(qualified, err)
+	}
+}
+
+// holderFromHandles determines the holder of the mutex with the given
+// handle by finding the other processes that have it open.
+func holderFromHandles(h windows.Handle) (pid uint32, ok bool, err error) {
+	info, err := ntexapi.NtQueryMutant(syscall.Handle(h))
+	if err != nil {
+		return 0, false, err
+	}
+	if info.CurrentCount > 0 {
+		return 0, false, nil
+	}
+
+	handles, err := winhandle.SameObject(h)
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, handle := range handles {
+		switch {
+		case !ok:
+			pid, ok = handle.ProcessID, true
+		case handle.ProcessID != pid:
+			return 0, false, nil
+		}
+	}
+	return pid, ok, nil
+}
+
+func holderError(name string, err error) error {
+	return fmt.Errorf("winmutex: failed to determine the holder of %s: %w", mutexDescription(name), err)
+}
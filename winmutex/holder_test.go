@@ -0,0 +1,42 @@
+//go:build windows
+
+package winmutex_test
+
+import (
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"golang.org/x/sys/windows"
+)
+
+func TestHolder(t *testing.T) {
+	name := testMutexName("Holder")
+
+	if _, ok, err := winmutex.Holder(name); err != nil || ok {
+		t.Fatalf("Holder of a missing mutex: ok = %t, err = %v", ok, err)
+	}
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	if _, ok, err := winmutex.Holder(name); err != nil || ok {
+		t.Fatalf("Holder of an unlocked mutex: ok = %t, err = %v", ok, err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	pid, ok, err := winmutex.Holder(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("Holder did not report a holder for a locked mutex")
+	}
+	if want := windows.GetCurrentProcessId(); pid != want {
+		t.Fatalf("Holder returned process %d when it should have returned %d", pid, want)
+	}
+}
@@ -0,0 +1,69 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gentlemanautomaton/winobj"
+)
+
+// instances holds the mutexes acquired by SingleInstance, which must stay
+// locked for the lifetime of the process.
+var instances struct {
+	sync.Mutex
+	held map[string]*Mutex
+}
+
+// SingleInstance reports whether the calling process is the first
+// instance of an application that identifies itself with the named mutex,
+// such as `Global\MyApp-Instance` for one instance per machine, or
+// `Local\MyApp-Instance` for one instance per session. The name may be
+// given as a plain string or as a winobj.Name.
+//
+// If first is true, the mutex is held until the process exits, and
+// another instance that calls SingleInstance with the same name will
+// receive false. The mutex is created with initial ownership, so two
+// instances that start at the same time cannot both be first. If the
+// mutex exists but is not held, as when a previous instance is exiting,
+// it is acquired and first is true.
+//
+// Calling SingleInstance again with the same name in the same process
+// returns true without acquiring the mutex again. Options are applied as
+// they are by New.
+func SingleInstance[N winobj.ObjectName](name N, opts ...Option) (first bool, err error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return false, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(winobj.Qualify(name)), err)
+	}
+	options.InitialOwner = true
+	qualified := options.QualifyName(string(name))
+
+	instances.Lock()
+	defer instances.Unlock()
+
+	if _, ok := instances.held[qualified]; ok {
+		return true, nil
+	}
+
+	m, owned, err := create(qualified, options)
+	if err != nil {
+		return false, err
+	}
+
+	if !owned {
+		locked, _, err := m.tryLock("TryLock")
+		if err != nil || !locked {
+			m.Close()
+			return false, err
+		}
+	}
+
+	if instances.held == nil {
+		instances.held = make(map[string]*Mutex)
+	}
+	instances.held[qualified] = m
+
+	return true, nil
+}
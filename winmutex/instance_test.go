@@ -0,0 +1,60 @@
+//go:build windows
+
+package winmutex_test
+
+import (
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+func TestSingleInstance(t *testing.T) {
+	name := testMutexName("SingleInstance")
+
+	first, err := winmutex.SingleInstance(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first {
+		t.Fatal("The first call to SingleInstance did not report the first instance")
+	}
+
+	again, err := winmutex.SingleInstance(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again {
+		t.Fatal("A repeated call to SingleInstance in the same process did not report the first instance")
+	}
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	if mutex.TryLock() {
+		t.Fatal("A lock was acquired on a mutex held by SingleInstance")
+	}
+}
+
+func TestSingleInstanceHeldElsewhere(t *testing.T) {
+	name := testMutexName("SingleInstanceHeldElsewhere")
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	first, err := winmutex.SingleInstance(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first {
+		t.Fatal("SingleInstance reported the first instance while another holder had the mutex")
+	}
+}
@@ -0,0 +1,170 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"github.com/gentlemanautomaton/winobj/internal/lockedthread"
+	"golang.org/x/sys/windows"
+)
+
+// MaxLockAll is the maximum number of mutexes that can be locked by a
+// single call to LockAll.
+const MaxLockAll = synchapi.MaximumWaitObjects
+
+// lockAllPollInterval is the number of milliseconds between checks of the
+// context passed to LockAll.
+const lockAllPollInterval = 100
+
+// Set is a group of system mutexes that were locked together by LockAll.
+// It holds all of them until Unlock is called.
+type Set struct {
+	names []string
+
+	mutex     sync.Mutex
+	handles   []syscall.Handle // Nil once the set has been unlocked
+	thread    *lockedthread.Thread
+	abandoned bool
+}
+
+// LockAll locks all of the named mutexes at once, creating any that do not
+// already exist, and returns a Set that holds them. It blocks until every
+// mutex is available at the same time or ctx is cancelled. No mutex is
+// held while any of the others is unavailable, so callers that lock the
+// same mutexes in different orders cannot deadlock one another.
+//
+// If one or more of the mutexes was abandoned by its previous owner,
+// LockAll returns the Set along with ErrAbandoned, and all of the mutexes
+// are held.
+//
+// The names may be given as plain strings or as winobj.Name values, and
+// the default prefix set by winobj.SetDefaultPrefix, if any, is applied to
+// each of them. No more than MaxLockAll names may be given, and each must
+// refer to a different mutex.
+//
+// It is the caller's responsibility to unlock the returned Set.
+func LockAll[N winobj.ObjectName](ctx context.Context, names ...N) (*Set, error) {
+	if len(names) == 0 {
+		return nil, errors.New("winmutex: no mutex names were provided")
+	}
+	if len(names) > MaxLockAll {
+		return nil, fmt.Errorf("winmutex: %d mutexes were provided but no more than %d can be locked at once", len(names), MaxLockAll)
+	}
+
+	s := &Set{
+		names:   make([]string, 0, len(names)),
+		handles: make([]syscall.Handle, 0, len(names)),
+	}
+	for _, name := range names {
+		qualified := winobj.Qualify(name)
+		handle, _, err := createHandle(qualified, false, winobj.Options{})
+		if err != nil {
+			s.closeHandles()
+			return nil, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(qualified), mapError(err))
+		}
+		s.names = append(s.names, qualified)
+		s.handles = append(s.handles, handle)
+	}
+
+	thread, err := getThread(ctx)
+	if err != nil {
+		s.closeHandles()
+		return nil, fmt.Errorf("winmutex: failed to lock %d mutexes: %w", len(s.handles), err)
+	}
+
+	// A cancellation event cannot be included in a wait for all objects,
+	// so check ctx between bounded waits instead.
+	n := uint32(len(s.handles))
+	for {
+		if err := ctx.Err(); err != nil {
+			lockedthread.Put(thread)
+			s.closeHandles()
+			return nil, err
+		}
+
+		var result uint32
+		thread.Run(func() {
+			result, err = synchapi.WaitForMultipleObjects(s.handles, true, lockAllPollInterval)
+		})
+		if err != nil {
+			lockedthread.Put(thread)
+			s.closeHandles()
+			return nil, fmt.Errorf("winmutex: failed to lock %d mutexes: %w", n, err)
+		}
+
+		switch {
+		case result == synchapi.WaitTimeout:
+			continue
+		case result < windows.WAIT_OBJECT_0+n:
+			s.thread = thread
+			return s, nil
+		case result >= windows.WAIT_ABANDONED && result < windows.WAIT_ABANDONED+n:
+			s.thread = thread
+			s.abandoned = true
+			return s, ErrAbandoned
+		default:
+			lockedthread.Put(thread)
+			s.closeHandles()
+			return nil, fmt.Errorf("winmutex: failed to lock %d mutexes: unexpected wait result: %#x", n, result)
+		}
+	}
+}
+
+// Names returns the names of the mutexes in the set, including any default
+// prefix that was applied to them.
+func (s *Set) Names() []string {
+	return s.names
+}
+
+// Abandoned reports whether one or more of the mutexes in the set was
+// abandoned by its previous owner when the set was locked.
+func (s *Set) Abandoned() bool {
+	return s.abandoned
+}
+
+// Unlock releases all of the mutexes in the set and closes their handles.
+// If the set has already been unlocked, it returns ErrNotLocked.
+func (s *Set) Unlock() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.handles == nil {
+		return fmt.Errorf("winmutex: Set.Unlock(): %w", ErrNotLocked)
+	}
+
+	var errs []error
+	s.thread.Run(func() {
+		for i, handle := range s.handles {
+			if _, err := synchapi.ReleaseMutex(handle); err != nil {
+				errs = append(errs, fmt.Errorf("winmutex: failed to release %s: %w", mutexDescription(s.names[i]), err))
+			}
+		}
+	})
+
+	// A thread that failed to release a mutex may still own it, so it
+	// must not be reused.
+	if len(errs) > 0 {
+		s.thread.Close()
+	} else {
+		lockedthread.Put(s.thread)
+	}
+	s.thread = nil
+	s.closeHandles()
+
+	return errors.Join(errs...)
+}
+
+// closeHandles closes the handles of the mutexes in the set.
+func (s *Set) closeHandles() {
+	for _, handle := range s.handles {
+		syscall.CloseHandle(handle)
+	}
+	s.handles = nil
+}
@@ -0,0 +1,77 @@
+//go:build windows
+
+package winmutex_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+func TestLockAll(t *testing.T) {
+	name1 := testMutexName("LockAll-1")
+	name2 := testMutexName("LockAll-2")
+
+	set, err := winmutex.LockAll(context.Background(), name1, name2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mutex, err := winmutex.New(name2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	if mutex.TryLock() {
+		t.Fatal("A lock was acquired on a mutex held by LockAll")
+	}
+
+	if err := set.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := set.Unlock(); !errors.Is(err, winmutex.ErrNotLocked) {
+		t.Fatalf("A second call to Unlock returned %v instead of ErrNotLocked", err)
+	}
+
+	if !mutex.TryLock() {
+		t.Fatal("A lock could not be acquired after the set was unlocked")
+	}
+	mutex.Unlock()
+}
+
+func TestLockAllCancelled(t *testing.T) {
+	name1 := testMutexName("LockAllCancelled-1")
+	name2 := testMutexName("LockAllCancelled-2")
+
+	mutex, err := winmutex.New(name2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	if _, err := winmutex.LockAll(ctx, name1, name2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LockAll returned %v instead of context.DeadlineExceeded", err)
+	}
+
+	// The mutex that was available must not have been left locked.
+	other, err := winmutex.New(name1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+
+	if !other.TryLock() {
+		t.Fatal("LockAll held a mutex after it was cancelled")
+	}
+	other.Unlock()
+}
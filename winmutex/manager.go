@@ -0,0 +1,245 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrManagerClosed is returned when a Manager is used after it has been
+// closed.
+var ErrManagerClosed = errors.New("winmutex: the manager has been closed")
+
+// Manager provides keyed locking on top of named system mutexes. Each key
+// maps to a system mutex whose name is the manager's prefix followed by
+// the key.
+//
+// Mutexes are created the first time their key is locked and are cached
+// for reuse. A cached mutex is closed once its key has gone unused for the
+// manager's idle timeout.
+//
+// Within a process, each key can be held by one goroutine at a time.
+// Across processes, keys are mutually exclusive with any other process
+// that locks a system mutex of the same name.
+//
+// A Manager is safe for concurrent use by multiple goroutines.
+type Manager struct {
+	prefix string
+	idle   time.Duration
+
+	ctx    context.Context // Cancelled when the manager is closed
+	cancel context.CancelFunc
+
+	mutex   sync.Mutex
+	entries map[string]*managedMutex
+	closed  bool
+}
+
+// managedMutex is a system mutex cached by a Manager.
+type managedMutex struct {
+	mutex *Mutex
+	users int         // Number of goroutines holding or waiting for the key
+	timer *time.Timer // Closes the mutex once it has been idle
+}
+
+// NewManager returns a Manager that locks system mutexes with names formed
+// by appending keys to prefix, such as `Global\MyApp-Customer-`. The
+// default prefix set by winobj.SetDefaultPrefix, if any, is applied to the
+// resulting names as well.
+//
+// Mutexes that go unused for the idle timeout are closed. If idle is zero,
+// mutexes are closed as soon as they are unlocked and no other goroutines
+// are waiting for them.
+//
+// It is the caller's responsibility to close the manager when finished
+// with it.
+func NewManager(prefix string, idle time.Duration) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		prefix:  prefix,
+		idle:    idle,
+		ctx:     ctx,
+		cancel:  cancel,
+		entries: make(map[string]*managedMutex),
+	}
+}
+
+// Lock locks the system mutex for key, blocking until it is available or
+// ctx is cancelled. When successful, it returns a function that unlocks
+// the mutex. The unlock function must be called exactly once.
+func (m *Manager) Lock(ctx context.Context, key string) (unlock func(), err error) {
+	entry, err := m.join(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Abandon the wait if the manager is closed.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(m.ctx, cancel)
+	defer stop()
+
+	release, err := entry.mutex.Acquire(ctx)
+	if err != nil {
+		m.leave(key, entry)
+		if ctx.Err() != nil {
+			return nil, m.lockError(ctx)
+		}
+		return nil, err
+	}
+
+	return m.unlocker(key, entry, release), nil
+}
+
+// TryLock attempts to lock the system mutex for key without blocking and
+// reports whether it succeeded. When successful, it returns a function
+// that unlocks the mutex. The unlock function must be called exactly once.
+func (m *Manager) TryLock(key string) (unlock func(), ok bool, err error) {
+	entry, err := m.join(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The mutex may be closed concurrently by Close, so report that as an
+	// error rather than letting TryLock panic. Abandonment is not
+	// reported, as it is not by Lock.
+	locked, err := entry.mutex.TryLockE()
+	if errors.Is(err, ErrAbandoned) {
+		err = nil
+	}
+	if err != nil {
+		m.leave(key, entry)
+		if m.ctx.Err() != nil {
+			return nil, false, ErrManagerClosed
+		}
+		return nil, false, err
+	}
+	if !locked {
+		m.leave(key, entry)
+		return nil, false, nil
+	}
+
+	return m.unlocker(key, entry, entry.mutex.Unlock), true, nil
+}
+
+// Close closes all of the system mutexes cached by the manager. Pending
+// calls to Lock return ErrManagerClosed, and mutexes that are still locked
+// are unlocked.
+func (m *Manager) Close() error {
+	m.cancel()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+
+	var errs []error
+	for key, entry := range m.entries {
+		if entry.timer != nil {
+			entry.timer.Stop()
+		}
+		errs = append(errs, entry.mutex.Close())
+		delete(m.entries, key)
+	}
+
+	return errors.Join(errs...)
+}
+
+// join returns the cached mutex for key, creating it if necessary, and
+// registers the caller as one of its users.
+func (m *Manager) join(key string) (*managedMutex, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+
+	entry, ok := m.entries[key]
+	if !ok {
+		mutex, err := New(m.prefix + key)
+		if err != nil {
+			return nil, err
+		}
+		entry = &managedMutex{
+			mutex: mutex,
+		}
+		m.entries[key] = entry
+	}
+
+	if entry.timer != nil {
+		entry.timer.Stop()
+		entry.timer = nil
+	}
+	entry.users++
+
+	return entry, nil
+}
+
+// leave unregisters the caller as a user of the cached mutex for key. If
+// the mutex has no remaining users, it is closed, either immediately or
+// after the manager's idle timeout.
+func (m *Manager) leave(key string, entry *managedMutex) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry.users--
+	if entry.users > 0 || m.closed {
+		return
+	}
+
+	if m.idle <= 0 {
+		m.expire(key, entry)
+		return
+	}
+
+	entry.timer = time.AfterFunc(m.idle, func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		if entry.users == 0 && !m.closed {
+			m.expire(key, entry)
+		}
+	})
+}
+
+// expire removes the cached mutex for key and closes it. The caller must
+// hold m.mutex.
+func (m *Manager) expire(key string, entry *managedMutex) {
+	if m.entries[key] == entry {
+		delete(m.entries, key)
+	}
+	entry.mutex.Close()
+}
+
+// unlocker returns a function that releases a lock on the cached mutex
+// for key that was acquired by the caller.
+func (m *Manager) unlocker(key string, entry *managedMutex, release func()) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mutex.Lock()
+			if !m.closed {
+				release()
+			}
+			m.mutex.Unlock()
+
+			m.leave(key, entry)
+		})
+	}
+}
+
+// lockError returns the error that explains why a lock attempt with the
+// given context was abandoned.
+func (m *Manager) lockError(ctx context.Context) error {
+	if m.ctx.Err() != nil {
+		return ErrManagerClosed
+	}
+	return ctx.Err()
+}
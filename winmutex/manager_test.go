@@ -0,0 +1,172 @@
+//go:build windows
+
+package winmutex_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+func TestManagerLockBasic(t *testing.T) {
+	manager := winmutex.NewManager(testMutexName("ManagerLockBasic-"), time.Minute)
+	defer manager.Close()
+
+	unlock, err := manager.Lock(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+
+	unlock, err = manager.Lock(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+}
+
+func TestManagerTryLockContended(t *testing.T) {
+	prefix := testMutexName("ManagerTryLockContended-")
+
+	manager1 := winmutex.NewManager(prefix, time.Minute)
+	defer manager1.Close()
+
+	manager2 := winmutex.NewManager(prefix, time.Minute)
+	defer manager2.Close()
+
+	unlock, err := manager1.Lock(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same key should be blocked within the process and across
+	// system mutex handles.
+	if _, ok, err := manager1.TryLock("a"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatalf("A lock was acquired by the same manager when it should have been blocked")
+	}
+	if _, ok, err := manager2.TryLock("a"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatalf("A lock was acquired by another manager when it should have been blocked")
+	}
+
+	// A different key should be unaffected.
+	unlockB, ok, err := manager2.TryLock("b")
+	if err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatalf("A lock on an unrelated key was blocked")
+	}
+	unlockB()
+
+	unlock()
+
+	unlock, ok, err = manager2.TryLock("a")
+	if err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatalf("A lock was blocked after it had been released")
+	}
+	unlock()
+}
+
+func TestManagerLockCancelled(t *testing.T) {
+	manager := winmutex.NewManager(testMutexName("ManagerLockCancelled-"), time.Minute)
+	defer manager.Close()
+
+	unlock, err := manager.Lock(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := manager.Lock(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v, received %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestManagerIdle(t *testing.T) {
+	prefix := testMutexName("ManagerIdle-")
+
+	manager := winmutex.NewManager(prefix, 10*time.Millisecond)
+	defer manager.Close()
+
+	unlock, err := manager.Lock(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+
+	// Once the idle timeout has elapsed, the manager should have closed
+	// its handle and the system mutex should no longer exist.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		exists, err := winmutex.Exists(prefix + "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("The idle mutex was not closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestManagerClosed(t *testing.T) {
+	manager := winmutex.NewManager(testMutexName("ManagerClosed-"), time.Minute)
+
+	unlock, err := manager.Lock(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Releasing a lock after the manager has been closed should be safe.
+	unlock()
+
+	if _, err := manager.Lock(context.Background(), "a"); !errors.Is(err, winmutex.ErrManagerClosed) {
+		t.Fatalf("Expected %v, received %v", winmutex.ErrManagerClosed, err)
+	}
+}
+
+func TestManagerTryLockWhileClosing(t *testing.T) {
+	manager := winmutex.NewManager(testMutexName("ManagerTryLockWhileClosing-"), time.Minute)
+
+	// TryLock must not panic if the manager closes the mutex it is using.
+	done := make(chan error, 1)
+	go func() {
+		for {
+			unlock, ok, err := manager.TryLock("a")
+			if err != nil {
+				done <- err
+				return
+			}
+			if ok {
+				unlock()
+			}
+		}
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if err := manager.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-done; !errors.Is(err, winmutex.ErrManagerClosed) {
+		t.Fatalf("Expected %v, received %v", winmutex.ErrManagerClosed, err)
+	}
+}
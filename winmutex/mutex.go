@@ -3,24 +3,82 @@
 package winmutex
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
+	"github.com/gentlemanautomaton/winobj"
 	"github.com/gentlemanautomaton/winobj/api/synchapi"
 	"github.com/gentlemanautomaton/winobj/internal/lockedthread"
+	"golang.org/x/sys/windows"
 )
 
+// Mutex implements the winobj.Locker interface.
+var _ winobj.Locker = (*Mutex)(nil)
+
+// ErrAbandoned is returned when a mutex is locked after its previous owner
+// exited without unlocking it. The lock is held when ErrAbandoned is
+// returned, but the state it protects may be inconsistent and should be
+// recovered before it is used.
+var ErrAbandoned = errors.New("winmutex: the mutex was abandoned by its previous owner")
+
+// ErrNotLocked is returned by UnlockE when the mutex is not locked.
+var ErrNotLocked = errors.New("winmutex: the mutex is not locked")
+
+// ErrClosedWhileLocked is returned by Close when a mutex created with
+// WithReportLockedClose is closed while it is locked. The mutex has been
+// released and closed when it is returned.
+var ErrClosedWhileLocked = errors.New("winmutex: the mutex was closed while it was locked")
+
+// ErrNotFound is returned by Open when the named mutex does not exist.
+// Errors returned by other functions in this package match it when the
+// system reports that a mutex or its namespace does not exist.
+var ErrNotFound = errors.New("winmutex: the mutex does not exist")
+
 // Mutex provides access to a single named or unnamed system mutex on
 // Windows.
+//
+// A Mutex may be used by multiple goroutines. Like sync.Mutex, it can be
+// held by only one goroutine at a time. Goroutines that call Lock,
+// LockContext, LockFor or Acquire while it is held are queued until it is
+// released, and calls to TryLock and Name do not wait behind them.
+//
+// Although Windows mutexes may be acquired recursively by the thread that
+// owns them, a Mutex is not reentrant. As with sync.Mutex, a goroutine
+// that calls Lock while it holds m blocks until m is unlocked by another
+// goroutine. Each lock of m corresponds to exactly one acquisition of the
+// system mutex, which is released by the matching Unlock.
 type Mutex struct {
-	name string
+	name     string
+	existed  bool                // True if the system mutex existed before m was created
+	observer winobj.LockObserver // Notified of lock attempts, if not nil
+	logger   *slog.Logger        // Receives debug events, if not nil
+	strict   bool                // True if closing m while locked is reported
+
+	gate   chan struct{}   // Holds a token while a goroutine holds or is locking m
+	done   context.Context // Cancelled when m is closed
+	cancel context.CancelFunc
 
-	mutex  sync.Mutex
-	thread *lockedthread.Thread
-	handle syscall.Handle
-	locked bool
+	state     sync.RWMutex   // Held for writing by Close, and for reading by other methods
+	handle    syscall.Handle // Zero once m has been closed
+	thread    atomic.Pointer[lockedthread.Thread]
+	locked    atomic.Bool
+	abandoned atomic.Bool                // True while m is held after being abandoned
+	owner     atomic.Pointer[ownerToken] // The owner given to LockOwner, if any
+
+	// The following fields hold the results of tryWaitOnThread and
+	// releaseOnThread, which tryLock and unlock run on m's thread without
+	// allocating a closure for each call. They are only used by the
+	// goroutine that holds the gate.
+	result    uint32
+	released  bool
+	resultErr error
 }
 
 // New returns a system mutex with the given name. If name is empty, it
@@ -33,169 +91,951 @@ type Mutex struct {
 // If the name is prefixed with "Session\", the mutex will be created or
 // opened in the session namespace.
 //
-// If the call is successful, it returns a non-nil Mutex. An operating system
-// thread will be allocated for the duration of its existince. This is
-// necessary to retain thread affinity for the underlying system handle.
+// If the name begins with a backslash, it is treated as a full object
+// manager path, such as `\Sessions\3\BaseNamedObjects\MyApp-Lock`, and the
+// mutex will be created or opened at that path.
+//
+// The name may be given as a plain string or as a winobj.Name. The
+// default prefix set by winobj.SetDefaultPrefix, if any, is applied to it.
+//
+// Ownership of a Windows mutex is bound to the operating system thread
+// that acquired it. While the returned mutex is locked, an operating
+// system thread will be allocated to it from a shared pool, and returned
+// to the pool when it is unlocked. Mutexes that are not locked do not
+// consume threads.
 //
 // It is the caller's responsibility to close the mutex that is returned,
-// which will close the underlying system handle and allow the allocated
-// operating system thread to be returned to the goroutine thread pool.
-// Closing the mutex will automatically unlock the mutex if it is locked at
-// the time it is closed.
+// which will close the underlying system handle. Closing the mutex will
+// automatically unlock the mutex if it is locked at the time it is closed.
+// As a safety net, a mutex that is garbage collected without being closed
+// is closed, but programs should not rely on this, because it may happen
+// long after the mutex is last used, or not at all.
+//
+// Options such as WithSecurityDescriptor are applied when the mutex is
+// created. They have no effect if the mutex already exists. Without
+// options, the mutex is created unlocked with a default security
+// descriptor.
+//
+// Names longer than MAX_PATH, which CreateMutex does not accept, are
+// created through the native NtCreateMutant function instead, so long,
+// structured names do not need to be shortened by the caller. Such names
+// cannot be used within a private namespace.
 //
 // If the mutex name is invalid, or if the calling process does not have
 // sufficient permissions to create or access a named mutex, it returns
 // an error and the mutex is not created or opened.
-func New(name string) (*Mutex, error) {
-	// Mutexes are bound to a specific operating system threads in Windows.
-	// Prepare an OS thread that will be dedicated to holding the mutex.
-	//
-	// This is a sad waste of an operating system thread allocation, but
-	// there's not much that can be done about it.
-	thread := lockedthread.New()
-
-	// Attempt to create or open the mutex via the OS thread.
+func New[N winobj.ObjectName](name N, opts ...Option) (*Mutex, error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(winobj.Qualify(name)), err)
+	}
+	qualified := options.QualifyName(string(name))
+	m, _, err := create(qualified, options)
+	return m, err
+}
+
+// NewAcquired returns a system mutex with the given name, creating it in a
+// locked state if it does not already exist. Creation and acquisition are
+// performed atomically, which makes NewAcquired suitable for
+// single-instance guards. It is equivalent to calling New with the
+// WithInitialOwner option, but it also reports whether ownership was
+// obtained.
+//
+// If the mutex was created by the call, owned is true and the returned
+// mutex is locked. The caller should unlock or close it when finished.
+//
+// If the mutex already existed, owned is false and the returned mutex is
+// unlocked. Ownership is not obtained even if the existing mutex happens
+// to be available; the caller may call Lock or TryLock to acquire it.
+//
+// As with New, it is the caller's responsibility to close the mutex that
+// is returned.
+func NewAcquired[N winobj.ObjectName](name N, opts ...Option) (m *Mutex, owned bool, err error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, false, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(winobj.Qualify(name)), err)
+	}
+	qualified := options.QualifyName(string(name))
+	options.InitialOwner = true
+	return create(qualified, options)
+}
+
+// create creates or opens the mutex with the given qualified name. If
+// initial ownership is requested and the mutex is created, the returned
+// mutex is locked and owned is true.
+func create(name string, options winobj.Options) (m *Mutex, owned bool, err error) {
+	// Without initial ownership, the handle has no thread affinity, so it
+	// can be created from any thread.
+	if !options.InitialOwner {
+		handle, existed, err := createHandle(name, false, options)
+		if err != nil {
+			return nil, false, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(name), mapError(err))
+		}
+		m := newMutex(name, handle, nil)
+		m.existed = existed
+		m.useOptions(options, "created")
+		return m, false, nil
+	}
+
+	thread, err := getThread(context.Background())
+	if err != nil {
+		return nil, false, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(name), err)
+	}
+
 	var (
-		handle syscall.Handle
-		err    error
+		handle  syscall.Handle
+		existed bool
 	)
 	thread.Run(func() {
-		handle, _, err = synchapi.CreateMutex(name, false, nil)
+		handle, existed, err = createHandle(name, true, options)
 	})
 
-	// If mutex creation failed, close the thread and return the error.
 	if err != nil {
-		thread.Close()
-		return nil, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(name), err)
+		lockedthread.Put(thread)
+		return nil, false, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(name), mapError(err))
+	}
+
+	// If the mutex already existed, the thread does not own it and can be
+	// returned to the pool.
+	if existed {
+		lockedthread.Put(thread)
+		m := newMutex(name, handle, nil)
+		m.existed = true
+		m.useOptions(options, "created")
+		return m, false, nil
+	}
+
+	m = newMutex(name, handle, thread)
+	m.useOptions(options, "created")
+	return m, true, nil
+}
+
+// createHandle creates or opens the mutex with the given qualified name
+// and returns its handle. If options request specific access rights, the
+// handle is created with them. Names that are too long for CreateMutex are
+// handled by the native API.
+func createHandle(name string, initialOwner bool, options winobj.Options) (handle syscall.Handle, existed bool, err error) {
+	if needsNative(name) {
+		return createNativeHandle(name, initialOwner, options)
+	}
+	if options.Access == 0 {
+		return synchapi.CreateMutex(name, initialOwner, options.SyscallSecurityAttributes())
+	}
+
+	var flags uint32
+	if initialOwner {
+		flags = synchapi.CreateMutexInitialOwner
+	}
+
+	return synchapi.CreateMutexEx(name, options.SyscallSecurityAttributes(), flags, options.Access|Synchronize)
+}
+
+// Open returns an existing system mutex with the given name. Unlike New,
+// it never creates the mutex. If the mutex does not exist, it returns an
+// error that wraps ErrNotFound.
+//
+// The handle is opened with SYNCHRONIZE access unless other access rights
+// are requested with WithAccess, and it is inheritable if WithInheritable
+// is given. Other options are ignored.
+//
+// The name may be given as a plain string or as a winobj.Name. The
+// default prefix set by winobj.SetDefaultPrefix, if any, is applied to it.
+//
+// As with New, it is the caller's responsibility to close the mutex that
+// is returned.
+func Open[N winobj.ObjectName](name N, opts ...Option) (*Mutex, error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("winmutex: failed to open %s: %w", mutexDescription(winobj.Qualify(name)), err)
+	}
+	qualified := options.QualifyName(string(name))
+
+	handle, err := openHandle(qualified, options)
+	if err != nil {
+		if err == syscall.ERROR_FILE_NOT_FOUND {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, qualified)
+		}
+		return nil, fmt.Errorf("winmutex: failed to open %s: %w", mutexDescription(qualified), mapError(err))
+	}
+
+	m := newMutex(qualified, handle, nil)
+	m.existed = true
+	m.useOptions(options, "opened")
+	return m, nil
+}
+
+// openHandle opens the existing mutex with the given qualified name and
+// returns its handle.
+func openHandle(name string, options winobj.Options) (syscall.Handle, error) {
+	if needsNative(name) {
+		return openNativeHandle(name, options)
+	}
+	access := options.Access | Synchronize
+	if !options.Inheritable {
+		return synchapi.OpenMutex(name, access)
+	}
+
+	utf16Name, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	h, err := windows.OpenMutex(access, true, utf16Name)
+	if err != nil {
+		return 0, err
 	}
+	return syscall.Handle(h), nil
+}
+
+// FromHandle returns a Mutex that wraps an existing handle to a system
+// mutex, such as a handle that was inherited from a parent process. The
+// returned Mutex takes ownership of the handle and closes it when the Mutex
+// is closed. Its name is empty.
+//
+// The handle must not be owned by the calling thread. As with New, an
+// operating system thread will be allocated to the Mutex while it is
+// locked.
+func FromHandle(handle syscall.Handle) (*Mutex, error) {
+	return newMutex("", handle, nil), nil
+}
 
-	// Return the mutex that wraps the thread and system handle.
-	return &Mutex{
+// newMutex returns a Mutex for the given handle. If thread is not nil, it
+// must own the mutex, and the Mutex is returned in a locked state.
+func newMutex(name string, handle syscall.Handle, thread *lockedthread.Thread) *Mutex {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Mutex{
 		name:   name,
-		thread: thread,
+		gate:   make(chan struct{}, 1),
+		done:   ctx,
+		cancel: cancel,
 		handle: handle,
-		locked: false,
-	}, nil
+	}
+	if thread != nil {
+		m.gate <- struct{}{}
+		m.thread.Store(thread)
+		m.locked.Store(true)
+	}
+	runtime.SetFinalizer(m, (*Mutex).finalize)
+	return m
 }
 
-// Name returns the name of the mutex.
+// finalize closes m if it is garbage collected without having been closed,
+// so that its handle and any thread allocated to it are not leaked. If m
+// has a logger, a warning is logged, because it indicates a missing call
+// to Close.
+func (m *Mutex) finalize() {
+	if m.logger != nil {
+		m.logger.Warn("winmutex: a mutex was garbage collected without being closed", slog.String("mutex", m.name), slog.Bool("locked", m.locked.Load()))
+	}
+	m.Close()
+}
+
+// useOptions applies the observer and logger of options to m, and logs
+// the given event.
+func (m *Mutex) useOptions(options winobj.Options, event string) {
+	m.observer = options.Observer
+	m.logger = options.Logger
+	m.strict = options.ReportLockedClose
+	if m.debugEnabled() {
+		m.debug(event, slog.Bool("existed", m.existed), slog.Bool("locked", m.locked.Load()))
+	}
+}
+
+// OpenedExisting reports whether the system mutex already existed when m
+// was created or opened. It is true for mutexes returned by Open, and for
+// mutexes returned by New when another process or another call to New had
+// already created the mutex. Single-instance applications can use it to
+// detect that another instance is running.
+//
+// It is false for unnamed mutexes and for mutexes returned by FromHandle.
+func (m *Mutex) OpenedExisting() bool {
+	return m.existed
+}
+
+// Locked reports whether m is locked, meaning that it holds the
+// underlying system mutex. It does not report whether the system mutex is
+// held by another Mutex or by another process; use TryLock for that.
+func (m *Mutex) Locked() bool {
+	return m.locked.Load()
+}
+
+// Closed reports whether m has been closed.
+func (m *Mutex) Closed() bool {
+	m.state.RLock()
+	defer m.state.RUnlock()
+	return m.handle == 0
+}
+
+// Handle returns the underlying handle of the mutex. It remains owned by
+// m and must not be closed by the caller. It returns zero if m is closed.
+//
+// The handle may be used with APIs that this package does not cover, such
+// as security queries, DuplicateHandle, or WaitForMultipleObjects with
+// other objects. Ownership of a Windows mutex is bound to the operating
+// system thread that acquired it, so a wait that acquires the mutex through
+// the handle makes the calling thread its owner, without m knowing. Such a
+// thread must release the mutex itself, and must not exit while owning it,
+// or the mutex is abandoned. Do not release a mutex through the handle
+// that was locked through m.
+func (m *Mutex) Handle() windows.Handle {
+	m.state.RLock()
+	defer m.state.RUnlock()
+	return windows.Handle(m.handle)
+}
+
+// Fd returns the value of the underlying system handle, in the manner of
+// os.File.Fd. It is intended for passing an inheritable mutex to a child
+// process; see WithInheritable. The handle remains owned by m and must not
+// be closed by the caller. It returns zero if m is closed.
+func (m *Mutex) Fd() uintptr {
+	m.state.RLock()
+	defer m.state.RUnlock()
+	return uintptr(m.handle)
+}
+
+// Name returns the name of the mutex, including any default prefix that
+// was applied to it.
 //
 // If the mutex is unnamed, it returns an empty string.
 func (m *Mutex) Name() string {
 	return m.name
 }
 
+// String returns a description of m that includes its name, namespace,
+// lock state and handle value, such as:
+//
+//	Global\MyApp-Lock (global, locked, handle 0x1a4)
+func (m *Mutex) String() string {
+	m.state.RLock()
+	defer m.state.RUnlock()
+
+	name := m.name
+	if name == "" {
+		name = "unnamed mutex"
+	}
+	if m.handle == 0 {
+		return fmt.Sprintf("%s (closed)", name)
+	}
+
+	state := "unlocked"
+	switch {
+	case m.abandoned.Load():
+		state = "locked, abandoned"
+	case m.locked.Load():
+		state = "locked"
+	}
+
+	if m.name == "" {
+		return fmt.Sprintf("%s (%s, handle %#x)", name, state, uintptr(m.handle))
+	}
+	return fmt.Sprintf("%s (%s, %s, handle %#x)", name, winobj.Name(m.name).Namespace(), state, uintptr(m.handle))
+}
+
+// GoString returns a verbose description of m for debugging, in the form
+// of a Go expression. It is used by the %#v verb.
+func (m *Mutex) GoString() string {
+	m.state.RLock()
+	defer m.state.RUnlock()
+
+	return fmt.Sprintf("&winmutex.Mutex{Name: %q, Namespace: %q, Existed: %t, Locked: %t, Abandoned: %t, Closed: %t, Handle: %#x}",
+		m.name, winobj.Name(m.name).Namespace(), m.existed, m.locked.Load(), m.abandoned.Load(), m.handle == 0, uintptr(m.handle))
+}
+
 // Lock locks the underlying system mutex represented by m. If the lock is
 // already in use, the calling goroutine blocks until the mutex is available.
+//
+// Lock panics if m is closed, including when m is closed by another
+// goroutine while Lock is waiting. If the mutex was abandoned by its
+// previous owner, Lock succeeds and Abandoned reports true.
 func (m *Mutex) Lock() {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
+	if _, err := m.lock(context.Background(), infinite, "Lock", nil); err != nil && err != ErrAbandoned {
+		panic(err)
+	}
+}
+
+// LockE locks the underlying system mutex represented by m, blocking until
+// the mutex is available. Unlike Lock, it returns an error instead of
+// panicking, such as when m is closed or the wait fails, so that
+// long-running services can log the failure and retry.
+//
+// If the mutex was abandoned by its previous owner, LockE returns
+// ErrAbandoned with m locked.
+func (m *Mutex) LockE() error {
+	_, err := m.lock(context.Background(), infinite, "LockE", nil)
+	return err
+}
 
-	if m.thread == nil {
-		panic("winmutex: Mutex.Lock() called on a mutex that has been closed")
+// LockContext locks the underlying system mutex represented by m, blocking
+// until the mutex is available, ctx is cancelled or m is closed. If ctx is
+// cancelled first, it returns ctx.Err() and m is not locked.
+//
+// If the mutex was abandoned by its previous owner, LockContext returns
+// ErrAbandoned with m locked.
+//
+// While it waits, the mutex and a cancellation event are waited on
+// together, so abandoning the wait releases the operating system thread
+// that was allocated to it.
+func (m *Mutex) LockContext(ctx context.Context) error {
+	_, err := m.lock(ctx, infinite, "LockContext", nil)
+	return err
+}
+
+// LockFor locks the underlying system mutex represented by m, waiting up
+// to d for it to become available. It reports whether the lock was
+// acquired. The timeout is rounded up to a whole number of milliseconds.
+//
+// Time spent waiting for other goroutines in this process to release m
+// counts toward the timeout. If m is closed while LockFor is waiting, it
+// returns an error.
+//
+// If the mutex was abandoned by its previous owner, LockFor returns true
+// and ErrAbandoned with m locked.
+func (m *Mutex) LockFor(d time.Duration) (acquired bool, err error) {
+	if d < 0 {
+		d = 0
 	}
+	return m.lock(context.Background(), d, "LockFor", nil)
+}
 
-	var err error
-	m.thread.Run(func() {
-		_, err = syscall.WaitForSingleObject(m.handle, syscall.INFINITE)
+// Acquire locks the underlying system mutex represented by m, blocking
+// until the mutex is available or ctx is cancelled. When successful, it
+// returns a function that unlocks m.
+//
+// Acquire allows m to be used as a winobj.Locker. Because the Locker
+// contract treats any error as a failure to lock, abandonment is not
+// reported as an error; callers can check Abandoned instead.
+func (m *Mutex) Acquire(ctx context.Context) (release func(), err error) {
+	if _, err := m.lock(ctx, infinite, "Acquire", nil); err != nil && err != ErrAbandoned {
+		return nil, err
+	}
+	return m.Unlock, nil
+}
+
+// LockAlertable locks the underlying system mutex represented by m in the
+// same way as LockContext, except that the wait is alertable. Asynchronous
+// procedure calls (APCs) queued to the operating system thread that waits
+// for the mutex, such as the completion routines of overlapped I/O, are
+// run while it waits, rather than being starved until the wait ends.
+//
+// If start is not nil, it is called on that thread before the wait
+// begins. It can issue I/O with completion routines or otherwise arrange
+// for APCs to be queued to the thread, which remains allocated to m until
+// it is unlocked.
+//
+// If the mutex was abandoned by its previous owner, LockAlertable returns
+// ErrAbandoned with m locked.
+func (m *Mutex) LockAlertable(ctx context.Context, start func()) error {
+	if start == nil {
+		start = func() {}
+	}
+	_, err := m.lock(ctx, infinite, "LockAlertable", start)
+	return err
+}
+
+// infinite is passed to lock to wait without a timeout.
+const infinite time.Duration = -1
+
+// lock waits for m to be released by other goroutines in this process,
+// and then for the underlying system mutex to become available. It stops
+// waiting if ctx is cancelled or m is closed, or once timeout has elapsed
+// unless it is infinite. It reports whether m was locked, which is false
+// without an error only when the timeout elapsed. If the mutex was
+// abandoned, it returns true and ErrAbandoned.
+//
+// If alert is not nil, it is run on the locked thread before the wait,
+// which is alertable, so that asynchronous procedure calls queued to the
+// thread are run while it waits.
+func (m *Mutex) lock(ctx context.Context, timeout time.Duration, method string, alert func()) (locked bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	start := time.Now()
+	if m.observer != nil {
+		m.observer.LockWaiting(m.name)
+	}
+
+	var deadline time.Time
+	if timeout != infinite {
+		deadline = time.Now().Add(timeout)
+	}
+
+	// Wait for our turn within this process. Take it right away if it is
+	// available, so that a timeout that has already elapsed cannot win a
+	// race against an uncontended gate.
+	select {
+	case m.gate <- struct{}{}:
+	default:
+		var expired <-chan time.Time
+		if timeout != infinite {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			expired = timer.C
+		}
+
+		select {
+		case m.gate <- struct{}{}:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-m.done.Done():
+			return false, mutexClosedError(method)
+		case <-expired:
+			m.observeContended()
+			return false, nil
+		}
+	}
+
+	m.state.RLock()
+	defer m.state.RUnlock()
+
+	if m.handle == 0 {
+		<-m.gate
+		return false, mutexClosedError(method)
+	}
+
+	// Allocate a thread that will own the mutex. If the thread limit has
+	// been reached, this waits no longer than the mutex itself would.
+	thread, err := m.waitThread(ctx, deadline)
+	if err != nil {
+		<-m.gate
+		switch {
+		case ctx.Err() != nil:
+			return false, ctx.Err()
+		case m.done.Err() != nil:
+			return false, mutexClosedError(method)
+		case errors.Is(err, context.DeadlineExceeded):
+			m.observeContended()
+			return false, nil
+		}
+		return false, mutexWaitError(m.name, err)
+	}
+	m.thread.Store(thread)
+
+	// Prepare an event that will be signaled if ctx is cancelled or m is
+	// closed, so that the wait on the locked thread can be interrupted.
+	cancelled, _, err := synchapi.CreateEventEx("", nil, synchapi.CreateEventManualReset, synchapi.EventAllAccess)
+	if err != nil {
+		m.putThread()
+		<-m.gate
+		return false, fmt.Errorf("winmutex: failed to create cancellation event: %w", err)
+	}
+	defer syscall.CloseHandle(cancelled)
+
+	signal := func() {
+		synchapi.SetEvent(cancelled)
+	}
+	stop := context.AfterFunc(ctx, signal)
+	defer stop()
+	stopClosed := context.AfterFunc(m.done, signal)
+	defer stopClosed()
+
+	// Determine how long the system mutex may be waited on.
+	milliseconds := uint32(windows.INFINITE)
+	if timeout != infinite {
+		milliseconds = synchapi.WaitMilliseconds(time.Until(deadline))
+	}
+
+	var event uint32
+	thread.Run(func() {
+		handles := []syscall.Handle{m.handle, cancelled}
+		if alert == nil {
+			event, err = synchapi.WaitForMultipleObjects(handles, false, milliseconds)
+			return
+		}
+
+		alert()
+		for {
+			event, err = synchapi.WaitForMultipleObjectsEx(handles, false, milliseconds, true)
+			if err != nil || event != synchapi.WaitIOCompletion {
+				return
+			}
+			// One or more APCs were run, so resume waiting for whatever
+			// remains of the timeout.
+			if timeout != infinite {
+				milliseconds = synchapi.WaitMilliseconds(time.Until(deadline))
+			}
+		}
 	})
 	if err != nil {
-		panic(mutexWaitError(m.name, err))
+		m.putThread()
+		<-m.gate
+		return false, mutexWaitError(m.name, err)
 	}
 
-	m.locked = true
+	switch event {
+	case windows.WAIT_OBJECT_0:
+		m.locked.Store(true)
+		m.observeAcquired(start, false)
+		return true, nil
+	case windows.WAIT_ABANDONED:
+		m.locked.Store(true)
+		m.abandoned.Store(true)
+		m.observeAcquired(start, true)
+		return true, ErrAbandoned
+	case windows.WAIT_OBJECT_0 + 1:
+		m.putThread()
+		<-m.gate
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		return false, mutexClosedError(method)
+	case synchapi.WaitTimeout:
+		m.putThread()
+		<-m.gate
+		m.observeContended()
+		return false, nil
+	default:
+		m.putThread()
+		<-m.gate
+		return false, mutexWaitError(m.name, fmt.Errorf("unexpected wait result: %#x", event))
+	}
 }
 
 // TryLock tries to lock the underlying system mutex represented by m and
 // reports whether it succeeded.
+//
+// TryLock does not wait for other goroutines. If m is held or being locked
+// by another goroutine in this process, it returns false immediately. It
+// also returns false if the limit set by SetThreadLimit has been reached
+// and is configured to wait.
 func (m *Mutex) TryLock() bool {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
+	locked, _, err := m.tryLock("TryLock")
+	if err != nil {
+		panic(err)
+	}
+	return locked
+}
 
-	if m.thread == nil {
-		panic("winmutex: Mutex.TryLock() called on a mutex that has been closed")
+// TryLockE tries to lock the underlying system mutex represented by m and
+// reports whether it succeeded. Unlike TryLock, it returns an error
+// instead of panicking, such as when m is closed.
+//
+// If the mutex was abandoned by its previous owner, TryLockE returns true
+// and ErrAbandoned with m locked.
+func (m *Mutex) TryLockE() (bool, error) {
+	locked, abandoned, err := m.tryLock("TryLockE")
+	if err != nil {
+		return false, err
+	}
+	if abandoned {
+		return true, ErrAbandoned
 	}
+	return locked, nil
+}
 
-	var (
-		event uint32
-		err   error
-	)
-	m.thread.Run(func() {
-		event, err = syscall.WaitForSingleObject(m.handle, 0)
-	})
+// TryAcquire tries to lock the underlying system mutex represented by m
+// without waiting. If successful, it returns a function that unlocks m.
+// If the mutex is unavailable, release is nil.
+//
+// Unlike TryLock, TryAcquire returns an error instead of panicking, and it
+// reports whether the mutex was abandoned. A mutex is abandoned when its
+// previous owner exited without unlocking it, which usually means that
+// the state it protects is inconsistent and must be recovered.
+func (m *Mutex) TryAcquire() (release func(), abandoned bool, err error) {
+	locked, abandoned, err := m.tryLock("TryAcquire")
+	if err != nil || !locked {
+		return nil, false, err
+	}
+	return m.Unlock, abandoned, nil
+}
+
+// Abandoned reports whether m is locked and was abandoned by its previous
+// owner when it was locked. It allows callers of Lock, TryLock and Acquire,
+// which do not report abandonment, to detect it while they hold m.
+func (m *Mutex) Abandoned() bool {
+	return m.abandoned.Load()
+}
+
+// tryLock attempts to lock m without waiting, and reports whether it
+// succeeded and whether the mutex was abandoned by its previous owner.
+func (m *Mutex) tryLock(method string) (locked, abandoned bool, err error) {
+	m.state.RLock()
+	defer m.state.RUnlock()
+
+	if m.handle == 0 {
+		return false, false, mutexClosedError(method)
+	}
+
+	select {
+	case m.gate <- struct{}{}:
+	default:
+		m.observeContended()
+		return false, false, nil
+	}
+
+	start := time.Now()
+
+	// Waiting for a thread would break the promise not to wait, so a
+	// thread limit that has been reached is treated as contention.
+	thread, err := tryGetThread()
+	if err != nil {
+		<-m.gate
+		return false, false, mutexWaitError(m.name, err)
+	}
+	if thread == nil {
+		<-m.gate
+		m.observeContended()
+		return false, false, nil
+	}
+	m.thread.Store(thread)
+
+	thread.RunArg(tryWaitOnThread, m)
+	event, err := m.result, m.resultErr
 	if err != nil {
-		panic(mutexWaitError(m.name, err))
+		m.putThread()
+		<-m.gate
+		return false, false, mutexWaitError(m.name, err)
 	}
 
-	if event == synchapi.WaitTimeout {
-		return false
+	switch event {
+	case windows.WAIT_OBJECT_0:
+	case windows.WAIT_ABANDONED:
+		abandoned = true
+	case synchapi.WaitTimeout:
+		m.putThread()
+		<-m.gate
+		m.observeContended()
+		return false, false, nil
+	default:
+		m.putThread()
+		<-m.gate
+		return false, false, mutexWaitError(m.name, fmt.Errorf("unexpected wait result: %#x", event))
 	}
 
-	m.locked = true
+	m.locked.Store(true)
+	m.abandoned.Store(abandoned)
+	m.observeAcquired(start, abandoned)
 
-	return true
+	return true, abandoned, nil
+}
+
+// tryWaitOnThread polls the system mutex of the *Mutex given as arg
+// without waiting. It is run on the mutex's thread by tryLock.
+func tryWaitOnThread(arg any) {
+	m := arg.(*Mutex)
+	m.result, m.resultErr = synchapi.WaitForSingleObjectEx(m.handle, 0, false)
+}
+
+// releaseOnThread releases the system mutex of the *Mutex given as arg.
+// It is run on the mutex's thread by unlock.
+func releaseOnThread(arg any) {
+	m := arg.(*Mutex)
+	m.released, m.resultErr = synchapi.ReleaseMutex(m.handle)
 }
 
 // Unlock unlocks the underlying system mutex represented by m. It is a
 // run-time error if m is not locked on entry to Unlock.
 func (m *Mutex) Unlock() {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
+	if err := m.unlock("Unlock", nil); err != nil {
+		panic(err)
+	}
+}
 
-	if !m.locked {
-		panic("winmutex: Mutex.Unlock() called on a mutex that is not locked")
+// UnlockE unlocks the underlying system mutex represented by m. Unlike
+// Unlock, it returns an error instead of panicking. If m is not locked, it
+// returns ErrNotLocked.
+//
+// If the system mutex cannot be released, m remains locked and the
+// caller may retry or close m.
+func (m *Mutex) UnlockE() error {
+	return m.unlock("UnlockE", nil)
+}
+
+// unlock releases the underlying system mutex and returns m to the
+// unlocked state. The owner must match the one recorded by LockOwner, if
+// any.
+func (m *Mutex) unlock(method string, owner *ownerToken) error {
+	m.state.RLock()
+	defer m.state.RUnlock()
+
+	if !m.locked.Load() {
+		return fmt.Errorf("winmutex: Mutex.%s(): %w", method, ErrNotLocked)
+	}
+	if err := m.checkOwner(method, owner); err != nil {
+		return err
 	}
 
-	var (
-		released bool
-		err      error
-	)
-	m.thread.Run(func() {
-		released, err = synchapi.ReleaseMutex(m.handle)
-	})
+	m.thread.Load().RunArg(releaseOnThread, m)
+	released, err := m.released, m.resultErr
 	if err != nil {
-		panic(fmt.Errorf("winmutex: Mutex.Unlock(): %w", err))
+		return fmt.Errorf("winmutex: Mutex.%s(): %w", method, err)
 	}
 	if !released {
-		panic("winmutex: Mutex.Unlock() called on a mutex that was not locked, but was expected to be")
+		return fmt.Errorf("winmutex: Mutex.%s() called on a mutex that was not locked, but was expected to be", method)
+	}
+
+	if m.debugEnabled() {
+		m.debug("unlocked", m.threadAttr())
 	}
 
-	m.locked = false
+	m.locked.Store(false)
+	m.abandoned.Store(false)
+	m.owner.Store(nil)
+	m.putThread()
+	<-m.gate
 
-	return
+	return nil
 }
 
-// Close releases the underlying system mutex handle and releases its
-// operating system thread back into the goroutine thread pool.
+// HealthCheck verifies that the operating system thread allocated to m
+// is responsive by running a no-op on it. It returns an error if the
+// thread does not respond before ctx is cancelled, or if m is closed. If
+// m is not locked or being locked, no thread is allocated to it and the
+// check succeeds.
 //
-// If the mutex is locked, it will be unlocked before being closed.
+// The thread is busy while a call to Lock or Acquire is waiting for the
+// mutex, so supervisors should only treat a failed health check as a sign
+// of a wedged thread when no such wait is expected. A wedged Mutex can be
+// recycled by closing it and creating a new one.
+func (m *Mutex) HealthCheck(ctx context.Context) error {
+	m.state.RLock()
+	defer m.state.RUnlock()
+
+	if m.handle == 0 {
+		return mutexClosedError("HealthCheck")
+	}
+
+	thread := m.thread.Load()
+	if thread == nil {
+		return nil
+	}
+
+	if err := thread.Ping(ctx); err != nil && err != lockedthread.ErrClosed {
+		return fmt.Errorf("winmutex: the thread for %s is unresponsive: %w", mutexDescription(m.name), err)
+	}
+
+	return nil
+}
+
+// Close releases the underlying system mutex handle. If the mutex is
+// locked, it will be unlocked before being closed, and its operating
+// system thread will be returned to the pool. Calls to Lock or Acquire
+// that are waiting for the mutex are interrupted.
+//
+// Close never panics. If the mutex was created with WithReportLockedClose
+// and is locked, the returned error wraps ErrClosedWhileLocked, along with
+// any error encountered while releasing it. Calling Close more than once
+// has no further effect and returns nil.
 func (m *Mutex) Close() error {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-
-	var err1, err2, err3 error
-	if m.thread != nil {
-		if m.handle != 0 {
-			m.thread.Run(func() {
-				if m.locked {
-					_, err1 = synchapi.ReleaseMutex(m.handle)
-				}
-				err2 = syscall.CloseHandle(m.handle)
+	// Interrupt any pending waits before claiming exclusive access.
+	m.cancel()
+
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	if m.handle == 0 {
+		return nil
+	}
+	runtime.SetFinalizer(m, nil)
+
+	var err0, err1, err2 error
+	if m.strict && m.locked.Load() {
+		err0 = fmt.Errorf("winmutex: %s: %w", mutexDescription(m.name), ErrClosedWhileLocked)
+	}
+	if thread := m.thread.Load(); thread != nil {
+		if m.locked.Load() {
+			thread.Run(func() {
+				_, err1 = synchapi.ReleaseMutex(m.handle)
 			})
-			m.handle = 0
-			m.locked = false
 		}
-		err3 = m.thread.Close()
-		m.thread = nil
+		if err1 != nil {
+			m.discardThread()
+		} else {
+			m.putThread()
+		}
+	}
+	err2 = syscall.CloseHandle(m.handle)
+	m.handle = 0
+	m.locked.Store(false)
+	m.abandoned.Store(false)
+	m.owner.Store(nil)
+
+	if m.debugEnabled() {
+		m.debug("closed")
+	}
+
+	return errors.Join(err0, err1, err2)
+}
+
+// putThread returns the thread allocated to m to the pool.
+func (m *Mutex) putThread() {
+	if thread := m.thread.Swap(nil); thread != nil {
+		lockedthread.Put(thread)
+	}
+}
+
+// observeAcquired notifies m's observer, if any, that m was acquired
+// after waiting since start.
+func (m *Mutex) observeAcquired(start time.Time, abandoned bool) {
+	waited := time.Since(start)
+	if m.debugEnabled() {
+		result := "acquired"
+		if abandoned {
+			result = "abandoned"
+		}
+		m.debug("locked", slog.String("result", result), slog.Duration("waited", waited), m.threadAttr())
+	}
+	if m.observer == nil {
+		return
+	}
+	if abandoned {
+		m.observer.LockAbandoned(m.name)
+	}
+	m.observer.LockAcquired(m.name, waited)
+}
+
+// observeContended notifies m's observer, if any, that an attempt to
+// acquire m failed because it was held elsewhere.
+func (m *Mutex) observeContended() {
+	if m.debugEnabled() {
+		m.debug("contended")
 	}
+	if m.observer != nil {
+		m.observer.LockContended(m.name)
+	}
+}
 
-	return errors.Join(err1, err2, err3)
+// debugEnabled reports whether m has a logger that accepts debug events.
+// Callers check it before preparing attributes that are costly to obtain.
+func (m *Mutex) debugEnabled() bool {
+	return m.logger != nil && m.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// debug logs a debug event for m with the given attributes.
+func (m *Mutex) debug(event string, attrs ...slog.Attr) {
+	attrs = append([]slog.Attr{slog.String("mutex", m.name)}, attrs...)
+	m.logger.LogAttrs(context.Background(), slog.LevelDebug, "winmutex: "+event, attrs...)
+}
+
+// threadAttr returns an attribute that identifies the operating system
+// thread allocated to m, if any.
+func (m *Mutex) threadAttr() slog.Attr {
+	var id uint32
+	if thread := m.thread.Load(); thread != nil {
+		id = thread.ID()
+	}
+	return slog.Any("thread", id)
+}
+
+// discardThread closes the thread allocated to m instead of returning it
+// to the pool. It is used when the thread may still own the system mutex,
+// so that the ownership cannot carry over to another Mutex that reuses the
+// thread, where a later wait would acquire the mutex recursively. The
+// mutex is abandoned when the thread exits.
+func (m *Mutex) discardThread() {
+	if thread := m.thread.Swap(nil); thread != nil {
+		thread.Close()
+	}
 }
 
 func mutexWaitError(name string, err error) error {
 	return fmt.Errorf("winmutex: failed to wait for %s: %w", mutexDescription(name), err)
 }
 
+func mutexClosedError(method string) error {
+	return fmt.Errorf("winmutex: Mutex.%s() called on a mutex that has been closed", method)
+}
+
 func mutexDescription(name string) string {
 	if name == "" {
 		return "an unnamed windows mutex"
@@ -3,10 +3,25 @@
 package winmutex_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
+	"unsafe"
 
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/processthreadsapi"
 	"github.com/gentlemanautomaton/winobj/winmutex"
+	"github.com/gentlemanautomaton/winobj/winobjdir"
+	"golang.org/x/sys/windows"
 )
 
 func TestMutexLockBasic(t *testing.T) {
@@ -177,3 +192,1362 @@ func TestMutexBadName(t *testing.T) {
 func testMutexName(name string) string {
 	return "WinObj-WinMutex-Test-" + name
 }
+
+func TestMutexAcquireBasic(t *testing.T) {
+	name := testMutexName("AcquireBasic")
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	release, err := mutex.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
+
+func TestMutexAcquireCancelled(t *testing.T) {
+	name := testMutexName("AcquireCancelled")
+
+	mutex1, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex1.Lock()
+	defer mutex1.Unlock()
+
+	mutex2, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	release, err := mutex2.Acquire(ctx)
+	if err == nil {
+		release()
+		t.Fatal("A lock was acquired when it should have been blocked")
+	}
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Acquire returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestMutexLockContext(t *testing.T) {
+	name := testMutexName("LockContext")
+
+	mutex1, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	mutex1.Lock()
+	_, active := winmutex.ThreadLimit()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := mutex2.LockContext(ctx); err != context.DeadlineExceeded {
+		if err == nil {
+			mutex2.Unlock()
+		}
+		t.Fatalf("LockContext returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+
+	// The thread allocated to the abandoned wait must have been released.
+	if _, after := winmutex.ThreadLimit(); after != active {
+		t.Fatalf("%d threads are active after the wait was abandoned when %d should be", after, active)
+	}
+
+	mutex1.Unlock()
+
+	if err := mutex2.LockContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mutex2.Unlock()
+}
+
+func TestMutexLockFor(t *testing.T) {
+	name := testMutexName("LockFor")
+
+	mutex1, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	mutex1.Lock()
+
+	start := time.Now()
+	acquired, err := mutex2.LockFor(50 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acquired {
+		mutex2.Unlock()
+		t.Fatal("A lock was acquired when it should have been blocked")
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("LockFor gave up after %s when it should have waited for 50ms", elapsed)
+	}
+
+	// Release the first lock while the second is waiting.
+	time.AfterFunc(50*time.Millisecond, mutex1.Unlock)
+
+	acquired, err = mutex2.LockFor(5 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !acquired {
+		t.Fatal("A lock was not acquired after it was released")
+	}
+	mutex2.Unlock()
+}
+
+func TestMutexLockForZero(t *testing.T) {
+	mutex, err := winmutex.New(testMutexName("LockForZero"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	// An uncontended mutex must always be acquired, even though the
+	// timeout has already elapsed.
+	for i := range 100 {
+		acquired, err := mutex.LockFor(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !acquired {
+			t.Fatalf("An uncontended lock was not acquired on attempt %d", i+1)
+		}
+		mutex.Unlock()
+	}
+}
+
+func TestMutexOpen(t *testing.T) {
+	name := testMutexName("Open")
+
+	if _, err := winmutex.Open(name); !errors.Is(err, winmutex.ErrNotFound) {
+		t.Fatalf("Open returned %v for a missing mutex when it should have returned %v", err, winmutex.ErrNotFound)
+	}
+
+	mutex1, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	mutex2.Lock()
+	if mutex1.TryLock() {
+		mutex1.Unlock()
+		t.Fatal("A lock was acquired through the created mutex while the opened mutex held it")
+	}
+	mutex2.Unlock()
+}
+
+func TestMutexFromHandle(t *testing.T) {
+	handle, err := windows.CreateMutex(nil, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mutex, err := winmutex.FromHandle(syscall.Handle(handle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutex.Lock()
+	mutex.Unlock()
+	if err := mutex.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMutexNewAcquired(t *testing.T) {
+	name := testMutexName("NewAcquired")
+
+	mutex1, owned, err := winmutex.NewAcquired(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+	if !owned {
+		t.Fatal("The mutex was created but ownership was not reported")
+	}
+
+	mutex2, owned, err := winmutex.NewAcquired(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+	if owned {
+		t.Fatal("Ownership was reported for a mutex that already existed")
+	}
+	if mutex2.TryLock() {
+		t.Fatal("A lock was acquired when it should have been blocked")
+	}
+
+	mutex1.Unlock()
+
+	if !mutex2.TryLock() {
+		t.Fatal("A lock was blocked after it had been released")
+	}
+	mutex2.Unlock()
+}
+
+func TestMutexWithInitialOwner(t *testing.T) {
+	name := testMutexName("WithInitialOwner")
+
+	mutex1, err := winmutex.New(name, winmutex.WithInitialOwner())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(name, winmutex.WithInitialOwner())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	// The first mutex was created locked, so the second cannot be locked
+	// until it is released.
+	if mutex2.TryLock() {
+		mutex2.Unlock()
+		t.Fatal("A lock was acquired when the mutex should have been created locked")
+	}
+
+	mutex1.Unlock()
+
+	if !mutex2.TryLock() {
+		t.Fatal("A lock was not acquired after the initial owner released it")
+	}
+	mutex2.Unlock()
+}
+
+func TestMutexTryLockWhileWaiting(t *testing.T) {
+	name := testMutexName("TryLockWhileWaiting")
+
+	holder, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+	holder.Lock()
+	defer holder.Unlock()
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	// Start an indefinite wait on mutex in another goroutine.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	waiting := make(chan error, 1)
+	go func() {
+		release, err := mutex.Acquire(ctx)
+		if err == nil {
+			release()
+		}
+		waiting <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// TryLock should report failure immediately rather than waiting
+	// behind the other goroutine.
+	result := make(chan bool, 1)
+	go func() {
+		result <- mutex.TryLock()
+	}()
+	select {
+	case locked := <-result:
+		if locked {
+			t.Fatal("A lock was acquired when it should have been blocked")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("TryLock blocked behind a pending Acquire")
+	}
+
+	cancel()
+	if err := <-waiting; err != context.Canceled {
+		t.Fatalf("Acquire returned %v when it should have returned %v", err, context.Canceled)
+	}
+}
+
+func TestMutexCloseWhileWaiting(t *testing.T) {
+	name := testMutexName("CloseWhileWaiting")
+
+	holder, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+	holder.Lock()
+	defer holder.Unlock()
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waiting := make(chan error, 1)
+	go func() {
+		_, err := mutex.Acquire(context.Background())
+		waiting <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	if err := mutex.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-waiting:
+		if err == nil {
+			t.Fatal("A lock was acquired when it should have been blocked")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close did not interrupt a pending Acquire")
+	}
+}
+
+func TestMutexNewWithName(t *testing.T) {
+	name, err := winobj.ParseName(`Local\` + testMutexName("NewWithName"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	if mutex.Name() != name.String() {
+		t.Fatalf("The mutex was named %s when it should have been named %s", mutex.Name(), name)
+	}
+}
+
+func TestMutexTryAcquireAbandoned(t *testing.T) {
+	name := testMutexName("TryAcquireAbandoned")
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	abandonMutex(t, name)
+
+	// The thread exits shortly after the goroutine returns.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		release, abandoned, err := mutex.TryAcquire()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if release != nil {
+			release()
+			if !abandoned {
+				t.Fatal("The mutex was acquired but its abandonment was not reported")
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("The abandoned mutex could not be acquired")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMutexSecurityDescriptor(t *testing.T) {
+	name := testMutexName("SecurityDescriptor")
+
+	// Build a DACL that grants everyone full access, without SDDL.
+	everyone, err := windows.CreateWellKnownSid(windows.WinWorldSid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dacl, err := windows.ACLFromEntries([]windows.EXPLICIT_ACCESS{{
+		AccessPermissions: windows.GENERIC_ALL,
+		AccessMode:        windows.GRANT_ACCESS,
+		Trustee: windows.TRUSTEE{
+			TrusteeForm:  windows.TRUSTEE_IS_SID,
+			TrusteeType:  windows.TRUSTEE_IS_WELL_KNOWN_GROUP,
+			TrusteeValue: windows.TrusteeValueFromSID(everyone),
+		},
+	}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd, err := windows.NewSecurityDescriptor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sd.SetDACL(dacl, true, false); err != nil {
+		t.Fatal(err)
+	}
+
+	mutex, err := winmutex.New(name, winobj.WithSecurityDescriptor(sd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	got, err := windows.GetNamedSecurityInfo(name, windows.SE_KERNEL_OBJECT, windows.DACL_SECURITY_INFORMATION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := got.String(); s != "D:(A;;GA;;;WD)" && s != "D:(A;;0x1f0001;;;WD)" {
+		t.Fatalf("the mutex has the security descriptor %s", s)
+	}
+}
+
+func TestMutexLockContextAbandoned(t *testing.T) {
+	name := testMutexName("LockContextAbandoned")
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	abandonMutex(t, name)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := mutex.LockContext(ctx); err != winmutex.ErrAbandoned {
+		if err == nil {
+			mutex.Unlock()
+		}
+		t.Fatalf("LockContext returned %v when it should have returned %v", err, winmutex.ErrAbandoned)
+	}
+	if !mutex.Abandoned() {
+		t.Fatal("The mutex was locked after being abandoned but Abandoned reported false")
+	}
+
+	mutex.Unlock()
+	if mutex.Abandoned() {
+		t.Fatal("Abandoned reported true after the mutex was unlocked")
+	}
+}
+
+// abandonMutex locks the named mutex on an operating system thread that
+// exits without releasing it, which abandons the mutex once the thread
+// has exited.
+func abandonMutex(t *testing.T, name string) {
+	t.Helper()
+
+	abandon := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread() // Never unlocked, so the thread exits with the goroutine
+
+		utf16Name, err := windows.UTF16PtrFromString(name)
+		if err != nil {
+			abandon <- err
+			return
+		}
+		handle, err := windows.OpenMutex(windows.SYNCHRONIZE, false, utf16Name)
+		if err != nil {
+			abandon <- err
+			return
+		}
+		if _, err := windows.WaitForSingleObject(handle, windows.INFINITE); err != nil {
+			abandon <- err
+			return
+		}
+		abandon <- nil
+	}()
+	if err := <-abandon; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMutexWithSDDL(t *testing.T) {
+	name := testMutexName("WithSDDL")
+
+	if _, err := winmutex.New(name, winmutex.WithSDDL("not sddl")); err == nil {
+		t.Fatal("A mutex was created with a malformed SDDL string")
+	}
+
+	mutex, err := winmutex.New(name, winmutex.WithSDDL("D:(A;;0x100001;;;WD)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	got, err := windows.GetNamedSecurityInfo(name, windows.SE_KERNEL_OBJECT, windows.DACL_SECURITY_INFORMATION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := got.String(); s != "D:(A;;0x100001;;;WD)" {
+		t.Fatalf("the mutex has the security descriptor %s", s)
+	}
+}
+
+func TestMutexWithAccess(t *testing.T) {
+	name := testMutexName("WithAccess")
+
+	mutex1, err := winmutex.New(name, winmutex.WithAccess(winmutex.Synchronize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.Open(name, winmutex.WithAccess(winmutex.ReadControl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	// Synchronize is included in every access mask, so both handles can
+	// be used to lock the mutex.
+	mutex1.Lock()
+	if mutex2.TryLock() {
+		mutex2.Unlock()
+		t.Fatal("A lock was acquired through the opened mutex while the created mutex held it")
+	}
+	mutex1.Unlock()
+
+	if !mutex2.TryLock() {
+		t.Fatal("A lock was not acquired through the opened mutex")
+	}
+	mutex2.Unlock()
+}
+
+func TestMutexWithInheritable(t *testing.T) {
+	for _, inheritable := range []bool{false, true} {
+		var opts []winmutex.Option
+		if inheritable {
+			opts = append(opts, winmutex.WithInheritable())
+		}
+
+		mutex, err := winmutex.New("", opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer mutex.Close()
+
+		var flags uint32
+		if r0, _, err := procGetHandleInformation.Call(mutex.Fd(), uintptr(unsafe.Pointer(&flags))); r0 == 0 {
+			t.Fatal(err)
+		}
+		if got := flags&windows.HANDLE_FLAG_INHERIT != 0; got != inheritable {
+			t.Fatalf("The handle was inheritable=%t when it should have been inheritable=%t", got, inheritable)
+		}
+	}
+}
+
+var procGetHandleInformation = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetHandleInformation")
+
+func TestMutexErrorVariants(t *testing.T) {
+	name := testMutexName("ErrorVariants")
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mutex.UnlockE(); !errors.Is(err, winmutex.ErrNotLocked) {
+		t.Fatalf("UnlockE returned %v for an unlocked mutex when it should have returned %v", err, winmutex.ErrNotLocked)
+	}
+
+	if err := mutex.LockE(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mutex.UnlockE(); err != nil {
+		t.Fatal(err)
+	}
+
+	locked, err := mutex.TryLockE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !locked {
+		t.Fatal("TryLockE did not lock an available mutex")
+	}
+	if err := mutex.UnlockE(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mutex.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// None of the variants panic once the mutex is closed.
+	if err := mutex.LockE(); err == nil {
+		t.Fatal("LockE succeeded on a closed mutex")
+	}
+	if _, err := mutex.TryLockE(); err == nil {
+		t.Fatal("TryLockE succeeded on a closed mutex")
+	}
+	if err := mutex.UnlockE(); err == nil {
+		t.Fatal("UnlockE succeeded on a closed mutex")
+	}
+}
+
+func TestMutexOpenedExisting(t *testing.T) {
+	name := testMutexName("OpenedExisting")
+
+	mutex1, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+	if mutex1.OpenedExisting() {
+		t.Fatal("The first mutex was reported as existing before it was created")
+	}
+
+	mutex2, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+	if !mutex2.OpenedExisting() {
+		t.Fatal("The second mutex was not reported as existing")
+	}
+
+	mutex3, err := winmutex.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex3.Close()
+	if !mutex3.OpenedExisting() {
+		t.Fatal("The opened mutex was not reported as existing")
+	}
+}
+
+func TestMutexHandle(t *testing.T) {
+	name := testMutexName("Handle")
+
+	mutex, err := winmutex.New(name, winmutex.WithAccess(winmutex.AllAccess))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sd, err := windows.GetSecurityInfo(mutex.Handle(), windows.SE_KERNEL_OBJECT, windows.DACL_SECURITY_INFORMATION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := sd.DACL(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mutex.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if handle := mutex.Handle(); handle != 0 {
+		t.Fatalf("Handle returned %d after the mutex was closed", handle)
+	}
+}
+
+func TestMutexThreadReuse(t *testing.T) {
+	name := testMutexName("ThreadReuse")
+
+	mutex1, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	// Each lock returns its thread to the pool when it is unlocked, so the
+	// same thread is likely to be used by both mutexes. It must not carry
+	// ownership from one to the other.
+	for range 8 {
+		mutex1.Lock()
+		if mutex2.TryLock() {
+			t.Fatal("A lock was acquired recursively through a reused thread")
+		}
+		mutex1.Unlock()
+
+		mutex2.Lock()
+		if mutex1.TryLock() {
+			t.Fatal("A lock was acquired recursively through a reused thread")
+		}
+		mutex2.Unlock()
+	}
+}
+
+func TestMutexLockedClosed(t *testing.T) {
+	name := testMutexName("LockedClosed")
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mutex.Locked() || mutex.Closed() {
+		t.Fatalf("New mutex: Locked = %t, Closed = %t", mutex.Locked(), mutex.Closed())
+	}
+
+	mutex.Lock()
+	if !mutex.Locked() {
+		t.Fatal("The mutex was not reported as locked after Lock")
+	}
+
+	mutex.Unlock()
+	if mutex.Locked() {
+		t.Fatal("The mutex was reported as locked after Unlock")
+	}
+
+	mutex.Lock()
+	if err := mutex.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if mutex.Locked() || !mutex.Closed() {
+		t.Fatalf("Closed mutex: Locked = %t, Closed = %t", mutex.Locked(), mutex.Closed())
+	}
+}
+
+func TestMutexLockPumpingMessages(t *testing.T) {
+	name := testMutexName("LockPumpingMessages")
+
+	mutex1, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	mutex1.Lock()
+	time.AfterFunc(100*time.Millisecond, mutex1.Unlock)
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := mutex2.LockPumpingMessages(); err != nil {
+		t.Fatal(err)
+	}
+	mutex2.Unlock()
+}
+
+type countingObserver struct {
+	mutex                                   sync.Mutex
+	waiting, acquired, contended, abandoned int
+}
+
+func (o *countingObserver) LockWaiting(name string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.waiting++
+}
+
+func (o *countingObserver) LockAcquired(name string, waited time.Duration) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.acquired++
+}
+
+func (o *countingObserver) LockContended(name string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.contended++
+}
+
+func (o *countingObserver) LockAbandoned(name string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.abandoned++
+}
+
+func TestMutexWithObserver(t *testing.T) {
+	name := testMutexName("WithObserver")
+
+	var observer countingObserver
+	mutex1, err := winmutex.New(name, winmutex.WithObserver(&observer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	mutex1.Lock()
+	mutex1.Unlock()
+
+	mutex2.Lock()
+	if mutex1.TryLock() {
+		t.Fatal("A lock was acquired when it should have been blocked")
+	}
+	mutex2.Unlock()
+
+	observer.mutex.Lock()
+	defer observer.mutex.Unlock()
+	if observer.waiting != 1 || observer.acquired != 1 || observer.contended != 1 || observer.abandoned != 0 {
+		t.Fatalf("Unexpected observations: waiting %d, acquired %d, contended %d, abandoned %d", observer.waiting, observer.acquired, observer.contended, observer.abandoned)
+	}
+}
+
+func TestMutexWithLogger(t *testing.T) {
+	name := testMutexName("WithLogger")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mutex, err := winmutex.New(name, winmutex.WithLogger(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutex.Lock()
+	mutex.Unlock()
+	if err := mutex.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	for _, event := range []string{"winmutex: created", "winmutex: locked", "winmutex: unlocked", "winmutex: closed"} {
+		if !strings.Contains(output, event) {
+			t.Errorf("The log does not include the %q event:\n%s", event, output)
+		}
+	}
+}
+
+func TestMutexString(t *testing.T) {
+	name := `Local\` + testMutexName("String")
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := mutex.String(); !strings.HasPrefix(s, name+" (local, unlocked, handle 0x") {
+		t.Errorf("String returned %q for an unlocked mutex", s)
+	}
+
+	mutex.Lock()
+	if s := mutex.String(); !strings.HasPrefix(s, name+" (local, locked, handle 0x") {
+		t.Errorf("String returned %q for a locked mutex", s)
+	}
+	if s := fmt.Sprintf("%#v", mutex); !strings.Contains(s, "Locked: true") {
+		t.Errorf("GoString returned %q for a locked mutex", s)
+	}
+
+	if err := mutex.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s, want := mutex.String(), name+" (closed)"; s != want {
+		t.Errorf("String returned %q for a closed mutex instead of %q", s, want)
+	}
+}
+
+func TestMutexFinalizer(t *testing.T) {
+	name := testMutexName("Finalizer")
+
+	func() {
+		mutex, err := winmutex.New(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mutex.Lock()
+	}()
+
+	// Once the leaked mutex is finalized, its handle is closed and the
+	// named mutex ceases to exist.
+	for range 50 {
+		runtime.GC()
+		exists, err := winmutex.Exists(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("A leaked mutex was not closed after it was garbage collected")
+}
+
+func TestMutexSentinelErrors(t *testing.T) {
+	name := testMutexName("SentinelErrors")
+
+	event, err := windows.CreateEvent(nil, 1, 0, windows.StringToUTF16Ptr(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer windows.CloseHandle(event)
+
+	if _, err := winmutex.New(name); !errors.Is(err, winmutex.ErrObjectTypeMismatch) {
+		t.Errorf("New returned %v for a name in use by an event instead of ErrObjectTypeMismatch", err)
+	} else if !errors.Is(err, windows.ERROR_INVALID_HANDLE) {
+		t.Errorf("New returned %v, which does not match the underlying system error", err)
+	}
+
+	if _, err := winmutex.Open(testMutexName("SentinelErrors-Missing")); !errors.Is(err, winmutex.ErrNotFound) {
+		t.Errorf("Open returned %v for a missing mutex instead of ErrNotFound", err)
+	}
+
+	if _, err := winmutex.New(`Session\x\` + name); !errors.Is(err, winmutex.ErrInvalidName) && !errors.Is(err, winmutex.ErrNotFound) {
+		t.Errorf("New returned %v for an invalid name", err)
+	}
+}
+
+func TestMutexWithPrivateNamespace(t *testing.T) {
+	ns, err := winobj.NewPrivateNamespace("WinObj-WinMutex-Test-Boundary", "WinObjTest", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+
+	mutex1, err := winmutex.New("Lock", winmutex.WithPrivateNamespace(ns))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	if got, want := mutex1.Name(), `WinObjTest\Lock`; got != want {
+		t.Errorf("Name returned %q instead of %q", got, want)
+	}
+
+	mutex1.Lock()
+	defer mutex1.Unlock()
+
+	mutex2, err := winmutex.Open("Lock", winmutex.WithPrivateNamespace(ns))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	if mutex2.TryLock() {
+		t.Fatal("A lock was acquired when it should have been blocked")
+	}
+}
+
+func TestMutexLongName(t *testing.T) {
+	name := testMutexName("LongName-" + strings.Repeat("x", windows.MAX_PATH))
+
+	mutex1, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	if mutex1.OpenedExisting() {
+		t.Fatalf("New reported that the mutex already existed")
+	}
+
+	mutex2, err := winmutex.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	mutex1.Lock()
+	defer mutex1.Unlock()
+
+	if mutex2.TryLock() {
+		t.Fatalf("A lock was acquired on a long name when it should have been blocked")
+	}
+}
+
+func TestMutexNameLengthBoundary(t *testing.T) {
+	for _, filler := range []string{"x", "é"} {
+		for _, length := range []int{winobj.MaxNameLength - 1, winobj.MaxNameLength, winobj.MaxNameLength + 1} {
+			t.Run(fmt.Sprintf("%s-%d", filler, length), func(t *testing.T) {
+				prefix := testMutexName(fmt.Sprintf("NameLengthBoundary-%d-", length))
+				name := prefix + strings.Repeat(filler, length-len(prefix))
+
+				mutex1, err := winmutex.New(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer mutex1.Close()
+
+				mutex2, err := winmutex.Open(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer mutex2.Close()
+
+				mutex1.Lock()
+				defer mutex1.Unlock()
+
+				if mutex2.TryLock() {
+					mutex2.Unlock()
+					t.Fatalf("A lock was acquired on a %d character name when it should have been blocked", length)
+				}
+			})
+		}
+	}
+}
+
+func TestMutexLongNameFunctions(t *testing.T) {
+	testNameFunctions(t, testMutexName("LongNameFunctions-"+strings.Repeat("x", windows.MAX_PATH)))
+}
+
+func TestMutexObjectPath(t *testing.T) {
+	name := testMutexName("ObjectPath")
+
+	mutex1, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	path, err := winobjdir.Path(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mutex2, err := winmutex.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	mutex1.Lock()
+	defer mutex1.Unlock()
+
+	if mutex2.TryLock() {
+		t.Fatalf("A lock was acquired through %s when it should have been blocked", path)
+	}
+}
+
+func TestMutexObjectPathFunctions(t *testing.T) {
+	path, err := winobjdir.Path(testMutexName("ObjectPathFunctions"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testNameFunctions(t, path)
+}
+
+// testNameFunctions verifies that the package-level functions that accept
+// a mutex name, rather than a Mutex, work with the given name.
+func testNameFunctions(t *testing.T, name string) {
+	t.Helper()
+
+	if exists, err := winmutex.Exists(name); err != nil || exists {
+		t.Fatalf("Exists for a missing mutex: exists = %t, err = %v", exists, err)
+	}
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	if exists, err := winmutex.Exists(name); err != nil || !exists {
+		t.Fatalf("Exists for an existing mutex: exists = %t, err = %v", exists, err)
+	}
+
+	mutex.Lock()
+
+	pid, ok, err := winmutex.Holder(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || pid != windows.GetCurrentProcessId() {
+		t.Fatalf("Holder returned process %d (ok = %t) when it should have returned %d", pid, ok, windows.GetCurrentProcessId())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := winmutex.WaitForRelease(ctx, name); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForRelease returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+
+	mutex.Unlock()
+
+	set, err := winmutex.LockAll(context.Background(), name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mutex.TryLock() {
+		mutex.Unlock()
+		t.Fatalf("A lock was acquired when it should have been held by LockAll")
+	}
+	if err := set.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMutexLockOwner(t *testing.T) {
+	mutex, err := winmutex.New(testMutexName("LockOwner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	type job struct{ id int }
+	owner, other := &job{id: 1}, &job{id: 2}
+
+	if err := mutex.LockOwner(context.Background(), owner); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mutex.UnlockOwner(other); !errors.Is(err, winmutex.ErrNotOwner) {
+		t.Fatalf("UnlockOwner with another owner returned %v instead of ErrNotOwner", err)
+	}
+	if err := mutex.UnlockE(); !errors.Is(err, winmutex.ErrNotOwner) {
+		t.Fatalf("UnlockE without an owner returned %v instead of ErrNotOwner", err)
+	}
+	if !mutex.Locked() {
+		t.Fatalf("The mutex was unlocked by something other than its owner")
+	}
+
+	// The owner may unlock the mutex from any goroutine.
+	done := make(chan error)
+	go func() {
+		done <- mutex.UnlockOwner(owner)
+	}()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := mutex.UnlockOwner(owner); !errors.Is(err, winmutex.ErrNotLocked) {
+		t.Fatalf("A second call to UnlockOwner returned %v instead of ErrNotLocked", err)
+	}
+
+	// A mutex locked without an owner cannot be unlocked by one.
+	mutex.Lock()
+	if err := mutex.UnlockOwner(owner); !errors.Is(err, winmutex.ErrNotOwner) {
+		t.Fatalf("UnlockOwner on a mutex locked without an owner returned %v instead of ErrNotOwner", err)
+	}
+	mutex.Unlock()
+
+	if err := mutex.LockOwner(context.Background(), []int{1}); err == nil {
+		t.Fatal("LockOwner accepted an owner that is not comparable")
+	}
+	if mutex.Locked() {
+		t.Fatal("LockOwner locked the mutex for an owner that is not comparable")
+	}
+}
+
+func TestMutexStat(t *testing.T) {
+	name := testMutexName("Stat")
+
+	mutex, err := winmutex.New(name, winmutex.WithSDDL("D:(A;;GA;;;SY)(A;;GA;;;BA)(A;;GA;;;OW)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	info, err := mutex.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Existed {
+		t.Errorf("Stat reported that the mutex already existed")
+	}
+	if !strings.HasSuffix(info.Path, `\BaseNamedObjects\`+name) {
+		t.Errorf("Stat returned the path %q, which does not end with the mutex name", info.Path)
+	}
+	if info.Access&winmutex.Synchronize == 0 {
+		t.Errorf("Stat returned an access mask without Synchronize: %#x", info.Access)
+	}
+	if info.SecurityDescriptor == nil || info.Owner == nil {
+		t.Fatalf("Stat did not return the security descriptor of a mutex created with full access")
+	}
+	if sddl := info.SecurityDescriptor.String(); !strings.Contains(sddl, "(A;;") {
+		t.Errorf("Stat returned a security descriptor without the expected DACL: %s", sddl)
+	}
+
+	opened, err := winmutex.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer opened.Close()
+
+	info, err = opened.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.SecurityDescriptor != nil {
+		t.Errorf("Stat returned a security descriptor for a handle without ReadControl access")
+	}
+}
+
+func TestMutexSetSecurityDescriptor(t *testing.T) {
+	mutex, err := winmutex.New(testMutexName("SetSecurityDescriptor"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	sd, err := windows.SecurityDescriptorFromString("D:(A;;GA;;;SY)(A;;GA;;;OW)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mutex.SetSecurityDescriptor(sd); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := mutex.SecurityDescriptor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sddl := updated.String(); !strings.Contains(sddl, ";;;SY)") {
+		t.Fatalf("The updated security descriptor does not grant access to SYSTEM: %s", sddl)
+	}
+}
+
+var alertableAPCs atomic.Int32
+
+var alertableAPC = windows.NewCallback(func(data uintptr) uintptr {
+	alertableAPCs.Add(1)
+	return 0
+})
+
+func TestMutexLockAlertable(t *testing.T) {
+	name := testMutexName("LockAlertable")
+
+	holder, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+	holder.Lock()
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	alertableAPCs.Store(0)
+	queued := make(chan error, 1)
+	start := func() {
+		// Queue an APC to the waiting thread, which should run while the
+		// mutex is still held by the holder.
+		thread, err := windows.OpenThread(windows.THREAD_SET_CONTEXT, false, windows.GetCurrentThreadId())
+		if err != nil {
+			queued <- err
+			return
+		}
+		defer windows.CloseHandle(thread)
+		queued <- processthreadsapi.QueueUserAPC(alertableAPC, syscall.Handle(thread), 0)
+	}
+
+	locked := make(chan error, 1)
+	go func() {
+		locked <- mutex.LockAlertable(context.Background(), start)
+	}()
+
+	if err := <-queued; err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for alertableAPCs.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if alertableAPCs.Load() == 0 {
+		t.Fatalf("The APC was not run while LockAlertable was waiting")
+	}
+
+	holder.Unlock()
+	if err := <-locked; err != nil {
+		t.Fatal(err)
+	}
+	mutex.Unlock()
+}
+
+func TestMutexReportLockedClose(t *testing.T) {
+	mutex, err := winmutex.New(testMutexName("ReportLockedClose"), winmutex.WithReportLockedClose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mutex.Lock()
+	if err := mutex.Close(); !errors.Is(err, winmutex.ErrClosedWhileLocked) {
+		t.Fatalf("Close returned %v instead of ErrClosedWhileLocked", err)
+	}
+	if err := mutex.Close(); err != nil {
+		t.Fatalf("A second call to Close returned %v", err)
+	}
+
+	// The mutex must have been released despite the error.
+	other, err := winmutex.New(testMutexName("ReportLockedClose"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if !other.TryLock() {
+		t.Fatalf("The mutex was not released when it was closed")
+	}
+	if err := other.Close(); err != nil {
+		t.Fatalf("Close on a locked mutex without WithReportLockedClose returned %v", err)
+	}
+}
+
+func BenchmarkMutexTryLock(b *testing.B) {
+	mutex, err := winmutex.New(testMutexName("BenchmarkTryLock"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer mutex.Close()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if !mutex.TryLock() {
+			b.Fatal("TryLock failed on an uncontended mutex")
+		}
+		mutex.Unlock()
+	}
+}
+
+func BenchmarkMutexTryLockContended(b *testing.B) {
+	name := testMutexName("BenchmarkTryLockContended")
+
+	holder, err := winmutex.New(name)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer holder.Close()
+	holder.Lock()
+	defer holder.Unlock()
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer mutex.Close()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if mutex.TryLock() {
+			b.Fatal("TryLock succeeded on a mutex held by another handle")
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package winmutex
+
+import "github.com/gentlemanautomaton/winobj"
+
+// GlobalName returns base as a mutex name in the global namespace, such as
+// `Global\MyApp-Lock`. It is equivalent to winobj.GlobalName.
+func GlobalName(base string) (winobj.Name, error) {
+	return winobj.GlobalName(base)
+}
+
+// LocalName returns base as a mutex name in the local namespace of the
+// caller's session, such as `Local\MyApp-Lock`. It is equivalent to
+// winobj.LocalName.
+func LocalName(base string) (winobj.Name, error) {
+	return winobj.LocalName(base)
+}
+
+// SessionName returns base as a mutex name in the namespace of the session
+// with the given ID, such as `Session\2\MyApp-Lock`. It is equivalent to
+// winobj.SessionName.
+func SessionName(session uint32, base string) (winobj.Name, error) {
+	return winobj.SessionName(session, base)
+}
@@ -0,0 +1,84 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"syscall"
+	"unicode/utf16"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/ntexapi"
+	"github.com/gentlemanautomaton/winobj/winobjdir"
+	"golang.org/x/sys/windows"
+)
+
+// needsNative reports whether the mutex with the given qualified name must
+// be created or opened through the native API, because it is an object
+// manager path or is too long for CreateMutex and OpenMutex.
+func needsNative(name string) bool {
+	if winobj.Name(name).Namespace() == winobj.ObjectPathNamespace {
+		return true
+	}
+	return len(name) > winobj.MaxNameLength && len(utf16.Encode([]rune(name))) > winobj.MaxNameLength
+}
+
+// createNativeHandle creates or opens the mutex with the given qualified
+// name through NtCreateMutant, which accepts names that are longer than
+// MAX_PATH.
+func createNativeHandle(name string, initialOwner bool, options winobj.Options) (handle syscall.Handle, existed bool, err error) {
+	path, err := nativePath(name, options)
+	if err != nil {
+		return 0, false, err
+	}
+
+	access := uint32(AllAccess)
+	if options.Access != 0 {
+		access = options.Access | Synchronize
+	}
+
+	handle, existed, err = ntexapi.NtCreateMutant(path, access, nativeAttributes(options), options.SecurityDescriptor, initialOwner)
+	return handle, existed, nativeError(err)
+}
+
+// openNativeHandle opens the existing mutex with the given qualified name
+// through NtOpenMutant, which accepts names that are longer than MAX_PATH.
+func openNativeHandle(name string, options winobj.Options) (syscall.Handle, error) {
+	path, err := nativePath(name, options)
+	if err != nil {
+		return 0, err
+	}
+
+	handle, err := ntexapi.NtOpenMutant(path, options.Access|Synchronize, nativeAttributes(options))
+	return handle, nativeError(err)
+}
+
+// nativePath returns the object manager path of the mutex with the given
+// qualified name. Names within a private namespace or an object directory
+// have no path that can be derived from them, so they are rejected with
+// ERROR_FILENAME_EXCED_RANGE, which the synchapi bindings also report for
+// names that are too long.
+func nativePath(name string, options winobj.Options) (string, error) {
+	if options.PrivateNamespace != nil || options.Directory != "" {
+		return "", windows.ERROR_FILENAME_EXCED_RANGE
+	}
+	return winobjdir.Path(name)
+}
+
+// nativeAttributes returns the object attribute flags requested by
+// options.
+func nativeAttributes(options winobj.Options) uint32 {
+	if options.Inheritable {
+		return windows.OBJ_INHERIT
+	}
+	return 0
+}
+
+// nativeError converts a status code returned by the native API into the
+// equivalent system error code, so that it is reported and mapped in the
+// same way as errors returned by CreateMutex and OpenMutex.
+func nativeError(err error) error {
+	if status, ok := err.(windows.NTStatus); ok {
+		return status.Errno()
+	}
+	return err
+}
@@ -0,0 +1,141 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"log/slog"
+
+	"github.com/gentlemanautomaton/winobj"
+	"golang.org/x/sys/windows"
+)
+
+// Access rights for mutexes, for use with WithAccess.
+//
+// https://learn.microsoft.com/en-us/windows/win32/sync/synchronization-object-security-and-access-rights
+const (
+	Synchronize = windows.SYNCHRONIZE        // Required to lock the mutex
+	ModifyState = windows.MUTEX_MODIFY_STATE // Required by some interop scenarios
+	ReadControl = windows.READ_CONTROL       // Required to query the mutex's security descriptor
+	WriteDAC    = windows.WRITE_DAC          // Required to replace the mutex's access control list
+	WriteOwner  = windows.WRITE_OWNER        // Required to replace the mutex's owner or integrity label
+	AllAccess   = windows.MUTEX_ALL_ACCESS   // All access rights
+)
+
+// Option configures the creation of a mutex. Options provided by the
+// winobj package are accepted as well.
+type Option = winobj.Option
+
+// WithSecurityDescriptor returns an option that assigns sd to a newly
+// created mutex. It is equivalent to winobj.WithSecurityDescriptor.
+func WithSecurityDescriptor(sd *windows.SECURITY_DESCRIPTOR) Option {
+	return winobj.WithSecurityDescriptor(sd)
+}
+
+// WithSDDL returns an option that assigns the security descriptor
+// described by the given SDDL string to a newly created mutex. For
+// example, a service can create a Global\ mutex that non-elevated user
+// processes are able to open by granting them access:
+//
+//	winmutex.New(`Global\MyApp-Lock`, winmutex.WithSDDL("D:(A;;GA;;;SY)(A;;GA;;;BA)(A;;0x100001;;;AU)"))
+//
+// If the string is malformed, New returns an error. It is equivalent to
+// winobj.WithSDDL.
+func WithSDDL(sddl string) Option {
+	return winobj.WithSDDL(sddl)
+}
+
+// WithAccess returns an option that requests the given access rights for
+// the handle to a created or opened mutex, such as ReadControl. Synchronize
+// is always included, because it is required to lock the mutex.
+//
+// By default, New requests all access rights and Open requests only
+// Synchronize.
+func WithAccess(access uint32) Option {
+	return winobj.WithAccess(access)
+}
+
+// WithInheritable returns an option that marks the handle to a created or
+// opened mutex as inheritable. It is equivalent to winobj.WithInheritable.
+//
+// To share an unnamed mutex with a child process, create it with this
+// option, add the value returned by Fd to the child's
+// SysProcAttr.AdditionalInheritedHandles, and pass the value to the child,
+// such as on its command line. The child can then call FromHandle.
+func WithInheritable() Option {
+	return winobj.WithInheritable()
+}
+
+// WithInitialOwner returns an option that causes New to create the mutex
+// in a locked state, as NewAcquired does. If the mutex already exists,
+// ownership is not obtained and the returned mutex is unlocked; use
+// NewAcquired to learn which occurred.
+func WithInitialOwner() Option {
+	return func(o *winobj.Options) {
+		o.InitialOwner = true
+	}
+}
+
+// Observer is notified of attempts to lock a mutex. It is supplied with
+// WithObserver.
+type Observer = winobj.LockObserver
+
+// WithObserver returns an option that notifies o whenever the mutex waits
+// to be locked, is locked, is contended or is found to be abandoned. It
+// is equivalent to winobj.WithObserver.
+func WithObserver(o Observer) Option {
+	return winobj.WithObserver(o)
+}
+
+// WithLogger returns an option that emits debug events to logger when the
+// mutex is created, opened, locked, unlocked or closed. Events include the
+// name of the mutex, the ID of the operating system thread that owns it,
+// and the result of each wait. It is equivalent to winobj.WithLogger.
+func WithLogger(logger *slog.Logger) Option {
+	return winobj.WithLogger(logger)
+}
+
+// WithPrivateNamespace returns an option that creates or opens the mutex
+// within the private namespace ns, rather than in the global or local
+// namespace. The default prefix set by winobj.SetDefaultPrefix is not
+// applied to its name. It is equivalent to winobj.WithPrivateNamespace.
+//
+// Private namespaces protect cooperating processes from other processes
+// that squat on the names of their mutexes, because only processes that
+// satisfy the namespace's boundary can create objects within it.
+func WithPrivateNamespace(ns *winobj.PrivateNamespace) Option {
+	return winobj.WithPrivateNamespace(ns)
+}
+
+// WithoutPrefix returns an option that uses the name of the mutex as it
+// is given, without applying the default prefix set by
+// winobj.SetDefaultPrefix, such as for `Global\_MSIExecute`. It is
+// equivalent to winobj.WithoutPrefix.
+func WithoutPrefix() Option {
+	return winobj.WithoutPrefix()
+}
+
+// WithLowIntegrity returns an option that applies a low mandatory
+// integrity label to a newly created mutex, so that sandboxed processes
+// running at low integrity can open and lock it. It is equivalent to
+// winobj.WithLowIntegrity.
+func WithLowIntegrity() Option {
+	return winobj.WithLowIntegrity()
+}
+
+// WithReportLockedClose returns an option that causes Close to return an
+// error wrapping ErrClosedWhileLocked if the mutex is locked when it is
+// closed. The mutex is still released and closed. It is equivalent to
+// winobj.WithReportLockedClose.
+func WithReportLockedClose() Option {
+	return winobj.WithReportLockedClose()
+}
+
+// WithAppContainer returns an option that creates or opens the mutex
+// within the named object directory of the AppContainer with the given
+// SID, so that a broker process and a packaged or sandboxed application
+// running in the AppContainer can share it. If sid is nil, the
+// AppContainer of the calling process is used. It is equivalent to
+// winobj.WithAppContainer.
+func WithAppContainer(sid *windows.SID) Option {
+	return winobj.WithAppContainer(sid)
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+// Package otelwinmutex records OpenTelemetry trace spans and metrics for
+// system mutexes provided by the winmutex package.
+//
+// Waits for a named mutex can span processes that are traced separately,
+// such as a service and the installer it launches. Wrapping a mutex with
+// this package makes those waits, and the time for which the mutex is
+// held, visible in distributed traces.
+//
+// Metrics, which is passed to mutexes with winmutex.WithObserver, records
+// wait latencies and contention counts keyed by mutex name, for teams
+// that monitor them through OTLP pipelines.
+//
+// The package is a module of its own, separate from winobj, so that
+// programs that do not use OpenTelemetry do not depend on it.
+package otelwinmutex
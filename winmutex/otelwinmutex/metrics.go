@@ -0,0 +1,92 @@
+//go:build windows
+
+package otelwinmutex
+
+import (
+	"context"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Metrics implements the winmutex.Observer interface.
+var _ winmutex.Observer = (*Metrics)(nil)
+
+// Metrics records the wait latency and contention of system mutexes as
+// OpenTelemetry metrics. Each measurement carries the name of the mutex
+// as its NameKey attribute.
+//
+// A single Metrics may observe any number of mutexes. It is passed to
+// them with winmutex.WithObserver.
+type Metrics struct {
+	wait      metric.Float64Histogram
+	contended metric.Int64Counter
+	abandoned metric.Int64Counter
+}
+
+// NewMetrics returns a Metrics that records measurements using the given
+// meter provider. If mp is nil, the global meter provider is used.
+//
+// The following instruments are recorded:
+//
+//   - winmutex.lock.wait: a histogram of the seconds spent waiting for
+//     each mutex that was locked
+//   - winmutex.lock.contended: the number of attempts to lock a mutex that
+//     failed because it was held, such as by TryLock or LockFor
+//   - winmutex.lock.abandoned: the number of times a mutex was locked
+//     after its previous owner exited without unlocking it
+func NewMetrics(mp metric.MeterProvider) (*Metrics, error) {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+
+	wait, err := meter.Float64Histogram("winmutex.lock.wait",
+		metric.WithDescription("Time spent waiting to lock a system mutex."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	contended, err := meter.Int64Counter("winmutex.lock.contended",
+		metric.WithDescription("Attempts to lock a system mutex that failed because it was held."),
+		metric.WithUnit("{attempt}"))
+	if err != nil {
+		return nil, err
+	}
+
+	abandoned, err := meter.Int64Counter("winmutex.lock.abandoned",
+		metric.WithDescription("System mutexes locked after their previous owner exited without unlocking them."),
+		metric.WithUnit("{lock}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Metrics{
+		wait:      wait,
+		contended: contended,
+		abandoned: abandoned,
+	}, nil
+}
+
+// LockWaiting is called when a goroutine begins to wait for a mutex. The
+// wait is recorded once it ends, by LockAcquired.
+func (m *Metrics) LockWaiting(name string) {}
+
+// LockAcquired records the time spent waiting for the named mutex.
+func (m *Metrics) LockAcquired(name string, waited time.Duration) {
+	m.wait.Record(context.Background(), waited.Seconds(), metric.WithAttributes(NameKey.String(name)))
+}
+
+// LockContended records a failed attempt to lock the named mutex.
+func (m *Metrics) LockContended(name string) {
+	m.contended.Add(context.Background(), 1, metric.WithAttributes(NameKey.String(name)))
+}
+
+// LockAbandoned records that the named mutex was abandoned by its previous
+// owner.
+func (m *Metrics) LockAbandoned(name string) {
+	m.abandoned.Add(context.Background(), 1, metric.WithAttributes(NameKey.String(name)))
+}
@@ -0,0 +1,137 @@
+//go:build windows
+
+package otelwinmutex_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"github.com/gentlemanautomaton/winobj/winmutex/otelwinmutex"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"golang.org/x/sys/windows"
+)
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer provider.Shutdown(context.Background())
+
+	metrics, err := otelwinmutex.NewMetrics(provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := "WinObj-OtelWinMutex-Test-Metrics"
+
+	mutex1, err := winmutex.New(name, winmutex.WithObserver(metrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(name, winmutex.WithObserver(metrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	// Lock the mutex once, and fail to lock it once while it is held.
+	mutex1.Lock()
+	if mutex2.TryLock() {
+		t.Fatal("A lock was acquired when it should have been blocked")
+	}
+	mutex1.Unlock()
+
+	// Lock it a second time after it has been abandoned.
+	abandonMutex(t, name)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := mutex2.LockContext(ctx); !errors.Is(err, winmutex.ErrAbandoned) {
+		t.Fatalf("LockContext returned %v when it should have returned %v", err, winmutex.ErrAbandoned)
+	}
+	mutex2.Unlock()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	wait, ok := findMetric(rm, "winmutex.lock.wait").(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatal("The winmutex.lock.wait histogram was not recorded")
+	}
+	if len(wait.DataPoints) != 1 {
+		t.Fatalf("The winmutex.lock.wait histogram has %d data points instead of 1", len(wait.DataPoints))
+	}
+	if point := wait.DataPoints[0]; point.Count != 2 || !hasName(point.Attributes.Value(otelwinmutex.NameKey)) {
+		t.Fatalf("The winmutex.lock.wait histogram recorded %d waits with attributes %v instead of 2 waits for %s", point.Count, point.Attributes.ToSlice(), name)
+	}
+
+	for _, counter := range []string{"winmutex.lock.contended", "winmutex.lock.abandoned"} {
+		sum, ok := findMetric(rm, counter).(metricdata.Sum[int64])
+		if !ok {
+			t.Fatalf("The %s counter was not recorded", counter)
+		}
+		if len(sum.DataPoints) != 1 {
+			t.Fatalf("The %s counter has %d data points instead of 1", counter, len(sum.DataPoints))
+		}
+		if point := sum.DataPoints[0]; point.Value != 1 || !hasName(point.Attributes.Value(otelwinmutex.NameKey)) {
+			t.Fatalf("The %s counter recorded %d with attributes %v instead of 1 for %s", counter, point.Value, point.Attributes.ToSlice(), name)
+		}
+	}
+}
+
+// findMetric returns the data of the metric with the given name, or nil
+// if it was not recorded.
+func findMetric(rm metricdata.ResourceMetrics, name string) metricdata.Aggregation {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	return nil
+}
+
+// hasName reports whether value holds the name of the mutex used by
+// TestMetrics.
+func hasName(value attribute.Value, ok bool) bool {
+	return ok && value.AsString() == "WinObj-OtelWinMutex-Test-Metrics"
+}
+
+// abandonMutex locks the named mutex on a thread that exits without
+// unlocking it, so that the mutex is abandoned.
+func abandonMutex(t *testing.T, name string) {
+	t.Helper()
+
+	abandon := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread() // Never unlocked, so the thread exits with the goroutine
+
+		utf16Name, err := windows.UTF16PtrFromString(name)
+		if err != nil {
+			abandon <- err
+			return
+		}
+		handle, err := windows.OpenMutex(windows.SYNCHRONIZE, false, utf16Name)
+		if err != nil {
+			abandon <- err
+			return
+		}
+		if _, err := windows.WaitForSingleObject(handle, windows.INFINITE); err != nil {
+			abandon <- err
+			return
+		}
+		abandon <- nil
+	}()
+	if err := <-abandon; err != nil {
+		t.Fatal(err)
+	}
+}
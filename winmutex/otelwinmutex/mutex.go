@@ -0,0 +1,199 @@
+//go:build windows
+
+package otelwinmutex
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the tracer used by this package.
+const instrumentationName = "github.com/gentlemanautomaton/winobj/winmutex/otelwinmutex"
+
+// Attribute keys recorded on spans.
+const (
+	NameKey      = attribute.Key("winmutex.name")      // The name of the mutex
+	WaitKey      = attribute.Key("winmutex.wait_ms")   // Milliseconds spent waiting for the mutex
+	AbandonedKey = attribute.Key("winmutex.abandoned") // Whether the mutex was abandoned by its previous owner
+	AcquiredKey  = attribute.Key("winmutex.acquired")  // Whether a call to TryLock acquired the mutex
+)
+
+// Mutex implements the winobj.Locker interface.
+var _ winobj.Locker = (*Mutex)(nil)
+
+// Mutex wraps a winmutex.Mutex and records spans for each attempt to lock
+// it and for the time it is held.
+//
+// Mutex does not expose the methods of the winmutex.Mutex it wraps, so
+// that it cannot be locked or unlocked without being traced. Unwrap
+// returns the underlying mutex for other uses.
+type Mutex struct {
+	mutex  *winmutex.Mutex
+	tracer trace.Tracer
+	hold   trace.Span // Ends when the mutex is unlocked; only accessed by the holder
+}
+
+// Wrap returns a Mutex that records spans for m using the given tracer
+// provider. If tp is nil, the global tracer provider is used.
+//
+// The returned Mutex shares m, so closing either of them closes both. It
+// only traces locks that are taken through it.
+func Wrap(m *winmutex.Mutex, tp trace.TracerProvider) *Mutex {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Mutex{
+		mutex:  m,
+		tracer: tp.Tracer(instrumentationName),
+	}
+}
+
+// Unwrap returns the winmutex.Mutex wrapped by m. Locks taken through it
+// directly are not traced.
+func (m *Mutex) Unwrap() *winmutex.Mutex {
+	return m.mutex
+}
+
+// Name returns the name of the mutex.
+func (m *Mutex) Name() string {
+	return m.mutex.Name()
+}
+
+// Lock locks the mutex, blocking until it is available. Like
+// winmutex.Mutex.Lock, it panics if the mutex is closed, and it succeeds
+// if the mutex was abandoned by its previous owner.
+//
+// The wait is recorded as a "winmutex.Lock" span, and the time for which
+// the mutex is held as a "winmutex.Hold" span that ends when it is
+// unlocked.
+func (m *Mutex) Lock() {
+	if _, err := m.lock(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+// LockContext locks the mutex, blocking until it is available or ctx is
+// cancelled. Like winmutex.Mutex.LockContext, it returns
+// winmutex.ErrAbandoned with the mutex locked if the mutex was abandoned
+// by its previous owner.
+//
+// It records spans in the same way as Lock. They are children of the
+// span in ctx, if any.
+func (m *Mutex) LockContext(ctx context.Context) error {
+	abandoned, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	if abandoned {
+		return winmutex.ErrAbandoned
+	}
+	return nil
+}
+
+// TryLock tries to lock the mutex without waiting and reports whether it
+// succeeded.
+//
+// The attempt is recorded as a "winmutex.TryLock" span. If it succeeds,
+// the time for which the mutex is held is recorded as a "winmutex.Hold"
+// span that ends when it is unlocked.
+func (m *Mutex) TryLock() bool {
+	ctx := context.Background()
+	attrs := []attribute.KeyValue{NameKey.String(m.Name())}
+
+	_, span := m.tracer.Start(ctx, "winmutex.TryLock", trace.WithAttributes(attrs...))
+	locked, err := m.mutex.TryLockE()
+	abandoned := errors.Is(err, winmutex.ErrAbandoned)
+	span.SetAttributes(AcquiredKey.Bool(locked), AbandonedKey.Bool(abandoned))
+	if err != nil && !abandoned {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		panic(err)
+	}
+	span.End()
+
+	if locked {
+		_, m.hold = m.tracer.Start(ctx, "winmutex.Hold", trace.WithAttributes(append(attrs, AbandonedKey.Bool(abandoned))...))
+	}
+	return locked
+}
+
+// Acquire locks the mutex, blocking until it is available or ctx is
+// cancelled. When successful, it returns a function that unlocks the
+// mutex.
+//
+// It records spans in the same way as LockContext. Unlike
+// winmutex.Mutex.Acquire, abandonment is not reported as an error, but it
+// is recorded on both spans.
+func (m *Mutex) Acquire(ctx context.Context) (release func(), err error) {
+	if _, err := m.lock(ctx); err != nil {
+		return nil, err
+	}
+	return func() {
+		m.UnlockE()
+	}, nil
+}
+
+// Unlock unlocks the mutex and ends its "winmutex.Hold" span. Like
+// winmutex.Mutex.Unlock, it panics if the mutex is not locked.
+func (m *Mutex) Unlock() {
+	if err := m.UnlockE(); err != nil {
+		panic(err)
+	}
+}
+
+// UnlockE unlocks the mutex and ends its "winmutex.Hold" span. Unlike
+// Unlock, it returns an error instead of panicking, and records the error
+// on the span.
+func (m *Mutex) UnlockE() error {
+	hold := m.hold
+	m.hold = nil
+
+	err := m.mutex.UnlockE()
+	if hold != nil {
+		if err != nil {
+			hold.RecordError(err)
+			hold.SetStatus(codes.Error, err.Error())
+		}
+		hold.End()
+	}
+	return err
+}
+
+// Close closes the mutex, as winmutex.Mutex.Close does.
+func (m *Mutex) Close() error {
+	return m.mutex.Close()
+}
+
+// lock waits for the mutex and records its spans. It reports whether the
+// mutex was abandoned by its previous owner, which is not treated as an
+// error.
+func (m *Mutex) lock(ctx context.Context) (abandoned bool, err error) {
+	attrs := []attribute.KeyValue{NameKey.String(m.Name())}
+
+	_, span := m.tracer.Start(ctx, "winmutex.Lock", trace.WithAttributes(attrs...))
+	start := time.Now()
+	err = m.mutex.LockContext(ctx)
+	abandoned = errors.Is(err, winmutex.ErrAbandoned)
+	span.SetAttributes(
+		WaitKey.Int64(time.Since(start).Milliseconds()),
+		AbandonedKey.Bool(abandoned))
+	if err != nil && !abandoned {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return false, err
+	}
+	span.End()
+
+	_, m.hold = m.tracer.Start(ctx, "winmutex.Hold", trace.WithAttributes(append(attrs, AbandonedKey.Bool(abandoned))...))
+	return abandoned, nil
+}
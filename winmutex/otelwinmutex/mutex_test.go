@@ -0,0 +1,73 @@
+//go:build windows
+
+package otelwinmutex_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"github.com/gentlemanautomaton/winobj/winmutex/otelwinmutex"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestAcquire(t *testing.T) {
+	mutex, err := winmutex.New("WinObj-OtelWinMutex-Test-Acquire")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	traced := otelwinmutex.Wrap(mutex, noop.NewTracerProvider())
+
+	release, err := traced.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mutex.Locked() {
+		t.Fatal("The mutex was not locked by Acquire")
+	}
+
+	release()
+	if mutex.Locked() {
+		t.Fatal("The mutex was still locked after it was released")
+	}
+}
+
+func TestLockUnlock(t *testing.T) {
+	name := "WinObj-OtelWinMutex-Test-LockUnlock"
+
+	mutex1, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	traced1 := otelwinmutex.Wrap(mutex1, noop.NewTracerProvider())
+	traced2 := otelwinmutex.Wrap(mutex2, noop.NewTracerProvider())
+
+	traced1.Lock()
+	if !mutex1.Locked() {
+		t.Fatal("The mutex was not locked by Lock")
+	}
+	if traced2.TryLock() {
+		t.Fatal("A lock was acquired when it should have been blocked")
+	}
+	traced1.Unlock()
+
+	if !traced2.TryLock() {
+		t.Fatal("A lock was not acquired by TryLock after it was unlocked")
+	}
+	if err := traced2.UnlockE(); err != nil {
+		t.Fatal(err)
+	}
+	if err := traced2.UnlockE(); err == nil {
+		t.Fatal("A second call to UnlockE did not return an error")
+	}
+}
@@ -0,0 +1,72 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNotOwner is returned when a mutex that was locked by LockOwner is
+// unlocked by anything other than UnlockOwner with the same owner, or
+// when UnlockOwner is called with an owner that did not lock it.
+var ErrNotOwner = errors.New("winmutex: the mutex is locked by another owner")
+
+// ownerToken holds the owner given to LockOwner.
+type ownerToken struct {
+	value any
+}
+
+// LockOwner locks m on behalf of owner, blocking until the mutex is
+// available or ctx is cancelled, like LockContext. The owner identifies
+// the logical holder of the lock, such as a request or a job, and must be
+// a non-nil comparable value, such as a pointer or a string.
+//
+// A mutex locked by LockOwner can only be unlocked by UnlockOwner with an
+// equal owner. Other attempts to unlock it, including calls to Unlock and
+// UnlockE, fail with ErrNotOwner and leave it locked. This catches code
+// that releases a lock it does not hold, which would otherwise succeed or
+// fail deep inside ReleaseMutex depending on the thread it runs on.
+//
+// If the mutex was abandoned by its previous owner, LockOwner returns
+// ErrAbandoned with m locked on behalf of owner.
+func (m *Mutex) LockOwner(ctx context.Context, owner any) error {
+	if owner == nil || !reflect.TypeOf(owner).Comparable() {
+		return fmt.Errorf("winmutex: Mutex.LockOwner() called with an owner that is nil or not comparable: %T", owner)
+	}
+	err := m.LockContext(ctx)
+	if err != nil && !errors.Is(err, ErrAbandoned) {
+		return err
+	}
+	m.owner.Store(&ownerToken{value: owner})
+	return err
+}
+
+// UnlockOwner unlocks m on behalf of owner. It returns an error wrapping
+// ErrNotOwner if m was not locked by LockOwner with an equal owner, and
+// ErrNotLocked if m is not locked, such as when it has already been
+// unlocked.
+func (m *Mutex) UnlockOwner(owner any) error {
+	return m.unlock("UnlockOwner", &ownerToken{value: owner})
+}
+
+// checkOwner returns an error wrapping ErrNotOwner if the owner recorded
+// for m by LockOwner does not match owner. A nil owner matches a mutex
+// that was locked without one.
+func (m *Mutex) checkOwner(method string, owner *ownerToken) error {
+	held := m.owner.Load()
+	switch {
+	case held == nil && owner == nil:
+		return nil
+	case held == nil:
+		return fmt.Errorf("winmutex: Mutex.%s() called by owner %v on %s, which was not locked by LockOwner: %w", method, owner.value, mutexDescription(m.name), ErrNotOwner)
+	case owner == nil:
+		return fmt.Errorf("winmutex: Mutex.%s() called on %s, which was locked by owner %v: %w", method, mutexDescription(m.name), held.value, ErrNotOwner)
+	case held.value != owner.value:
+		return fmt.Errorf("winmutex: Mutex.%s() called by owner %v on %s, which was locked by owner %v: %w", method, owner.value, mutexDescription(m.name), held.value, ErrNotOwner)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"github.com/gentlemanautomaton/winobj/api/winuser"
+	"golang.org/x/sys/windows"
+)
+
+// LockPumpingMessages locks the underlying system mutex represented by m,
+// blocking until the mutex is available, while it continues to dispatch
+// window messages sent or posted to the calling thread. It allows the UI
+// thread of a GUI application to wait for m without freezing its windows.
+//
+// The calling goroutine must be locked to the operating system thread
+// that runs the message loop, as it is when it has called
+// runtime.LockOSThread. The mutex itself is still owned by a thread
+// allocated to m, as it is by Lock.
+//
+// If a WM_QUIT message is received while waiting, LockPumpingMessages
+// stops dispatching messages and posts it again once m is locked, so that
+// the caller's message loop sees it.
+//
+// Like LockE, it returns an error instead of panicking. If the mutex was
+// abandoned by its previous owner, LockPumpingMessages returns
+// ErrAbandoned with m locked.
+func (m *Mutex) LockPumpingMessages() error {
+	done, _, err := synchapi.CreateEventEx("", nil, synchapi.CreateEventManualReset, synchapi.EventAllAccess)
+	if err != nil {
+		return fmt.Errorf("winmutex: failed to create completion event: %w", err)
+	}
+	defer syscall.CloseHandle(done)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := m.lock(context.Background(), infinite, "LockPumpingMessages", nil)
+		result <- err
+		synchapi.SetEvent(done)
+	}()
+
+	var (
+		quit     bool
+		exitCode int32
+	)
+	handles := []syscall.Handle{done}
+	for !quit {
+		event, err := winuser.MsgWaitForMultipleObjectsEx(handles, windows.INFINITE, winuser.QSAllInput, winuser.MWMOInputAvailable)
+		if err != nil {
+			// Stop dispatching messages, but still wait for the lock so
+			// that it is not left to complete unobserved.
+			break
+		}
+		if event == windows.WAIT_OBJECT_0 {
+			return <-result
+		}
+
+		var msg winuser.Msg
+		for winuser.PeekMessage(&msg, 0, 0, 0, winuser.PMRemove) {
+			if msg.Message == winuser.WMQuit {
+				quit, exitCode = true, int32(msg.WParam)
+				break
+			}
+			winuser.TranslateMessage(&msg)
+			winuser.DispatchMessage(&msg)
+		}
+	}
+
+	synchapi.WaitForSingleObjectEx(done, windows.INFINITE, false)
+	if quit {
+		winuser.PostQuitMessage(exitCode)
+	}
+	return <-result
+}
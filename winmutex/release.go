@@ -0,0 +1,107 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"github.com/gentlemanautomaton/winobj/internal/lockedthread"
+	"golang.org/x/sys/windows"
+)
+
+// WaitForRelease waits until the named mutex is not held, such as
+// `Global\_MSIExecute` while Windows Installer is busy, and returns
+// without holding it. It returns an error if ctx is cancelled first. The
+// name may be given as a plain string or as a winobj.Name.
+//
+// The mutex is acquired once it is available and released immediately,
+// so that other lockers are blocked for as little time as possible.
+// Another process may acquire the mutex again as soon as WaitForRelease
+// returns.
+//
+// WaitForRelease never creates the mutex. If it does not exist, it is not
+// held, and WaitForRelease returns immediately. If the previous holder
+// exited without releasing the mutex, abandoned is true.
+//
+// Options that determine the name of the mutex, such as WithoutPrefix and
+// WithPrivateNamespace, are honored. Other options are ignored.
+func WaitForRelease[N winobj.ObjectName](ctx context.Context, name N, opts ...Option) (abandoned bool, err error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return false, mutexWaitError(winobj.Qualify(name), err)
+	}
+	qualified := options.QualifyName(string(name))
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	handle, err := openHandle(qualified, winobj.Options{})
+	if err != nil {
+		if err == syscall.ERROR_FILE_NOT_FOUND {
+			return false, nil
+		}
+		return false, mutexWaitError(qualified, err)
+	}
+	defer syscall.CloseHandle(handle)
+
+	thread, err := getThread(ctx)
+	if err != nil {
+		return false, mutexWaitError(qualified, err)
+	}
+
+	// Prepare an event that will be signaled if ctx is cancelled, so that
+	// the wait on the locked thread can be interrupted.
+	cancelled, _, err := synchapi.CreateEventEx("", nil, synchapi.CreateEventManualReset, synchapi.EventAllAccess)
+	if err != nil {
+		lockedthread.Put(thread)
+		return false, fmt.Errorf("winmutex: failed to create cancellation event: %w", err)
+	}
+	defer syscall.CloseHandle(cancelled)
+
+	stop := context.AfterFunc(ctx, func() {
+		synchapi.SetEvent(cancelled)
+	})
+	defer stop()
+
+	var (
+		event      uint32
+		releaseErr error
+	)
+	thread.Run(func() {
+		handles := []syscall.Handle{handle, cancelled}
+		event, err = synchapi.WaitForMultipleObjects(handles, false, windows.INFINITE)
+		if err == nil && (event == windows.WAIT_OBJECT_0 || event == windows.WAIT_ABANDONED) {
+			// Release the mutex immediately so that other lockers are not
+			// blocked.
+			_, releaseErr = synchapi.ReleaseMutex(handle)
+		}
+	})
+
+	// A thread that failed to release the mutex may still own it, so it
+	// must not be reused.
+	if releaseErr != nil {
+		thread.Close()
+		return false, fmt.Errorf("winmutex: failed to release %s: %w", mutexDescription(qualified), releaseErr)
+	}
+	lockedthread.Put(thread)
+
+	if err != nil {
+		return false, mutexWaitError(qualified, err)
+	}
+
+	switch event {
+	case windows.WAIT_OBJECT_0:
+		return false, nil
+	case windows.WAIT_ABANDONED:
+		return true, nil
+	case windows.WAIT_OBJECT_0 + 1:
+		return false, ctx.Err()
+	default:
+		return false, mutexWaitError(qualified, fmt.Errorf("unexpected wait result: %#x", event))
+	}
+}
@@ -0,0 +1,78 @@
+//go:build windows
+
+package winmutex_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+func TestWaitForRelease(t *testing.T) {
+	name := testMutexName("WaitForRelease")
+
+	// A mutex that does not exist is not held.
+	if _, err := winmutex.WaitForRelease(context.Background(), name); err != nil {
+		t.Fatal(err)
+	}
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	mutex.Lock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := winmutex.WaitForRelease(ctx, name); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForRelease returned %v while the mutex was held", err)
+	}
+
+	time.AfterFunc(100*time.Millisecond, mutex.Unlock)
+	if _, err := winmutex.WaitForRelease(context.Background(), name); err != nil {
+		t.Fatal(err)
+	}
+
+	// WaitForRelease must not leave the mutex held.
+	if !mutex.TryLock() {
+		t.Fatal("The mutex was held after WaitForRelease returned")
+	}
+	mutex.Unlock()
+}
+
+func TestWaitForReleaseWithoutPrefix(t *testing.T) {
+	name := testMutexName("WaitForReleaseWithoutPrefix")
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	if err := winobj.SetDefaultPrefix(`Local\WinObj-Test-Prefix-`); err != nil {
+		t.Fatal(err)
+	}
+	defer winobj.SetDefaultPrefix("")
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	// With the prefix applied, the name refers to a mutex that does not
+	// exist, which is not held.
+	if _, err := winmutex.WaitForRelease(context.Background(), name); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without it, the name refers to the held mutex.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := winmutex.WaitForRelease(ctx, name, winmutex.WithoutPrefix()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForRelease returned %v while the unprefixed mutex was held", err)
+	}
+}
@@ -0,0 +1,79 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// SecurityDescriptor returns the owner, group, discretionary access
+// control list and mandatory integrity label of the mutex. The handle
+// must have ReadControl access, which New requests by default.
+func (m *Mutex) SecurityDescriptor() (*windows.SECURITY_DESCRIPTOR, error) {
+	m.state.RLock()
+	defer m.state.RUnlock()
+
+	if m.handle == 0 {
+		return nil, mutexClosedError("SecurityDescriptor")
+	}
+
+	sd, err := windows.GetSecurityInfo(windows.Handle(m.handle), windows.SE_KERNEL_OBJECT,
+		windows.OWNER_SECURITY_INFORMATION|windows.GROUP_SECURITY_INFORMATION|
+			windows.DACL_SECURITY_INFORMATION|windows.LABEL_SECURITY_INFORMATION)
+	if err != nil {
+		return nil, fmt.Errorf("winmutex: failed to read the security descriptor of %s: %w", mutexDescription(m.name), mapError(err))
+	}
+	return sd, nil
+}
+
+// SetSecurityDescriptor replaces the parts of the mutex's security
+// descriptor that are present in sd, so that the creator of a named mutex
+// can tighten or loosen access to it without recreating it. It is
+// typically given a descriptor parsed with
+// windows.SecurityDescriptorFromString, such as "D:(A;;GA;;;SY)(A;;GA;;;BA)"
+// to replace only the discretionary access control list.
+//
+// Replacing the DACL requires WriteDAC access, and replacing the owner or
+// a mandatory integrity label in the SACL requires WriteOwner access. New
+// requests both by default. Handles that are already open keep the access
+// they were granted.
+func (m *Mutex) SetSecurityDescriptor(sd *windows.SECURITY_DESCRIPTOR) error {
+	m.state.RLock()
+	defer m.state.RUnlock()
+
+	if m.handle == 0 {
+		return mutexClosedError("SetSecurityDescriptor")
+	}
+
+	var info windows.SECURITY_INFORMATION
+	owner, _, _ := sd.Owner()
+	if owner != nil {
+		info |= windows.OWNER_SECURITY_INFORMATION
+	}
+	group, _, _ := sd.Group()
+	if group != nil {
+		info |= windows.GROUP_SECURITY_INFORMATION
+	}
+	dacl, _, err := sd.DACL()
+	if err == nil {
+		info |= windows.DACL_SECURITY_INFORMATION
+		if control, _, _ := sd.Control(); control&windows.SE_DACL_PROTECTED != 0 {
+			info |= windows.PROTECTED_DACL_SECURITY_INFORMATION
+		}
+	}
+	sacl, _, err := sd.SACL()
+	if err == nil {
+		info |= windows.LABEL_SECURITY_INFORMATION
+	}
+
+	if info == 0 {
+		return fmt.Errorf("winmutex: the security descriptor given for %s has nothing to apply", mutexDescription(m.name))
+	}
+
+	if err := windows.SetSecurityInfo(windows.Handle(m.handle), windows.SE_KERNEL_OBJECT, info, owner, group, dacl, sacl); err != nil {
+		return fmt.Errorf("winmutex: failed to update the security descriptor of %s: %w", mutexDescription(m.name), mapError(err))
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+// ShardedMutex provides keyed locking over a fixed number of named system
+// mutexes. Each key is hashed to one of the mutexes, so any number of keys
+// can be locked without creating a kernel object for each of them.
+//
+// Keys that hash to the same shard exclude one another. Because mutexes
+// are not reentrant, a goroutine that holds one key must not lock another
+// key that may share its shard.
+//
+// Processes that use the same prefix and shard count agree on the shard
+// of every key, and so exclude one another as well.
+type ShardedMutex struct {
+	shards []*Mutex
+}
+
+// Sharded returns a ShardedMutex made up of n named system mutexes, which
+// are named by appending a hyphen and the shard number to prefix, from
+// `prefix-0` through `prefix-<n-1>`. The mutexes are created as they are
+// by New, with the given options.
+//
+// It is the caller's responsibility to close the ShardedMutex that is
+// returned.
+func Sharded(prefix string, n int, opts ...Option) (*ShardedMutex, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("winmutex: a sharded mutex must have at least one shard, not %d", n)
+	}
+
+	s := &ShardedMutex{shards: make([]*Mutex, 0, n)}
+	for i := range n {
+		m, err := New(prefix+"-"+strconv.Itoa(i), opts...)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.shards = append(s.shards, m)
+	}
+
+	return s, nil
+}
+
+// Shard returns the mutex that key is hashed to. It can be used to lock
+// the key with a context or timeout.
+func (s *ShardedMutex) Shard(key string) *Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// LockKey locks the shard that key is hashed to, blocking until it is
+// available. It panics under the same conditions as Mutex.Lock.
+func (s *ShardedMutex) LockKey(key string) {
+	s.Shard(key).Lock()
+}
+
+// TryLockKey tries to lock the shard that key is hashed to without
+// waiting, and reports whether it succeeded.
+func (s *ShardedMutex) TryLockKey(key string) bool {
+	return s.Shard(key).TryLock()
+}
+
+// UnlockKey unlocks the shard that key is hashed to. It is a run-time
+// error if the shard is not locked.
+func (s *ShardedMutex) UnlockKey(key string) {
+	s.Shard(key).Unlock()
+}
+
+// Len returns the number of shards.
+func (s *ShardedMutex) Len() int {
+	return len(s.shards)
+}
+
+// Close closes all of the shards, unlocking any that are locked.
+func (s *ShardedMutex) Close() error {
+	var errs []error
+	for _, m := range s.shards {
+		errs = append(errs, m.Close())
+	}
+	return errors.Join(errs...)
+}
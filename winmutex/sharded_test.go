@@ -0,0 +1,40 @@
+//go:build windows
+
+package winmutex_test
+
+import (
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+func TestShardedMutex(t *testing.T) {
+	prefix := testMutexName("Sharded")
+
+	s1, err := winmutex.Sharded(prefix, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s1.Close()
+
+	s2, err := winmutex.Sharded(prefix, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s2.Close()
+
+	if got, want := s1.Shard("customer-42").Name(), s2.Shard("customer-42").Name(); got != want {
+		t.Fatalf("The same key was hashed to %s and %s", got, want)
+	}
+
+	s1.LockKey("customer-42")
+	if s2.TryLockKey("customer-42") {
+		t.Fatalf("A key was locked by a second sharded mutex when it should have been blocked")
+	}
+	s1.UnlockKey("customer-42")
+
+	if !s2.TryLockKey("customer-42") {
+		t.Fatalf("A key could not be locked after it was unlocked")
+	}
+	s2.UnlockKey("customer-42")
+}
@@ -0,0 +1,101 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gentlemanautomaton/winobj"
+)
+
+// shared holds the mutexes returned by NewShared, keyed by qualified name.
+var shared struct {
+	sync.Mutex
+	entries map[string]*sharedEntry
+}
+
+// sharedEntry is a mutex shared by one or more SharedMutex references.
+type sharedEntry struct {
+	mutex *Mutex
+	refs  int
+}
+
+// SharedMutex is a reference to a Mutex that is shared by every caller of
+// NewShared with the same name in the calling process. It provides all of
+// the methods of Mutex, except that Close releases the reference instead
+// of closing the Mutex.
+type SharedMutex struct {
+	*Mutex
+
+	once sync.Once
+	err  error
+}
+
+// NewShared returns a reference to a system mutex with the given name,
+// which is created or opened as it is by New. Callers within the process
+// that pass the same name share a single Mutex and system handle, which
+// is closed when the last reference is closed. This avoids redundant
+// handles in libraries that are instantiated many times in one process.
+//
+// Because the Mutex is shared, holders of different references exclude
+// one another in the same way that holders of separate mutexes with the
+// same name do. Closing a reference does not unlock the mutex unless it
+// is the last reference.
+//
+// Options are applied only by the call that creates the shared Mutex, and
+// are ignored by later calls. Unnamed mutexes cannot be shared, so an
+// empty name always returns a reference to a new Mutex.
+//
+// It is the caller's responsibility to close the reference that is
+// returned.
+func NewShared[N winobj.ObjectName](name N, opts ...Option) (*SharedMutex, error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("winmutex: failed to create %s: %w", mutexDescription(winobj.Qualify(name)), err)
+	}
+	qualified := options.QualifyName(string(name))
+
+	shared.Lock()
+	defer shared.Unlock()
+
+	if entry, ok := shared.entries[qualified]; ok && qualified != "" {
+		entry.refs++
+		return &SharedMutex{Mutex: entry.mutex}, nil
+	}
+
+	m, _, err := create(qualified, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if qualified != "" {
+		if shared.entries == nil {
+			shared.entries = make(map[string]*sharedEntry)
+		}
+		shared.entries[qualified] = &sharedEntry{mutex: m, refs: 1}
+	}
+
+	return &SharedMutex{Mutex: m}, nil
+}
+
+// Close releases the reference. If it is the last reference to the
+// shared Mutex, the Mutex is closed. Calling Close more than once has no
+// further effect.
+func (s *SharedMutex) Close() error {
+	s.once.Do(func() {
+		shared.Lock()
+		defer shared.Unlock()
+
+		name := s.Mutex.name
+		if entry, ok := shared.entries[name]; ok && entry.mutex == s.Mutex {
+			entry.refs--
+			if entry.refs > 0 {
+				return
+			}
+			delete(shared.entries, name)
+		}
+		s.err = s.Mutex.Close()
+	})
+	return s.err
+}
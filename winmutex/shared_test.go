@@ -0,0 +1,54 @@
+//go:build windows
+
+package winmutex_test
+
+import (
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+func TestNewShared(t *testing.T) {
+	name := testMutexName("NewShared")
+
+	ref1, err := winmutex.NewShared(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref1.Close()
+
+	ref2, err := winmutex.NewShared(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref2.Close()
+
+	if ref1.Mutex != ref2.Mutex {
+		t.Fatalf("NewShared returned different mutexes for the same name")
+	}
+
+	ref1.Lock()
+	if ref2.TryLock() {
+		t.Fatalf("A lock was acquired through a second reference when it should have been blocked")
+	}
+	ref1.Unlock()
+
+	// Closing one reference must leave the mutex usable by the other.
+	if err := ref1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if ref2.Closed() {
+		t.Fatalf("The shared mutex was closed while a reference remained")
+	}
+	if !ref2.TryLock() {
+		t.Fatalf("The shared mutex could not be locked through the remaining reference")
+	}
+	ref2.Unlock()
+
+	if err := ref2.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !ref2.Closed() {
+		t.Fatalf("The shared mutex was not closed when its last reference was closed")
+	}
+}
@@ -0,0 +1,94 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"fmt"
+
+	"github.com/gentlemanautomaton/winobj/api/ntobapi"
+	"github.com/gentlemanautomaton/winobj/winhandle"
+	"golang.org/x/sys/windows"
+)
+
+// Info describes an open mutex. It is returned by Mutex.Stat.
+type Info struct {
+	// Name is the name that the mutex was created or opened with,
+	// including any default prefix that was applied to it.
+	Name string
+
+	// Path is the full object manager path that the name resolved to,
+	// such as `\Sessions\1\BaseNamedObjects\MyApp-Lock`. It is empty for
+	// unnamed mutexes.
+	Path string
+
+	// Existed is true if the mutex already existed when it was created
+	// or opened.
+	Existed bool
+
+	// Access is the access mask that was granted to the handle.
+	Access uint32
+
+	// Inheritable is true if the handle can be inherited by child
+	// processes.
+	Inheritable bool
+
+	// Owner is the owner of the mutex's security descriptor. It is nil if
+	// the handle lacks ReadControl access.
+	Owner *windows.SID
+
+	// SecurityDescriptor holds the owner, group and discretionary access
+	// control list of the mutex. Its String method summarizes them in
+	// SDDL form. It is nil if the handle lacks ReadControl access.
+	SecurityDescriptor *windows.SECURITY_DESCRIPTOR
+}
+
+// Stat returns information about the open mutex, for use in diagnostics
+// and for verifying that a mutex was created with the intended security
+// descriptor.
+//
+// The security descriptor can only be read if the handle has ReadControl
+// access, which New requests by default. Handles returned by Open lack it
+// unless it is requested with WithAccess, in which case Stat leaves the
+// security fields empty rather than returning an error.
+func (m *Mutex) Stat() (Info, error) {
+	m.state.RLock()
+	defer m.state.RUnlock()
+
+	if m.handle == 0 {
+		return Info{}, mutexClosedError("Stat")
+	}
+
+	info := Info{
+		Name:    m.name,
+		Existed: m.existed,
+	}
+
+	basic, err := ntobapi.NtQueryObjectBasic(m.handle)
+	if err != nil {
+		return Info{}, statError(m.name, err)
+	}
+	info.Access = basic.GrantedAccess
+	info.Inheritable = basic.Attributes&windows.OBJ_INHERIT != 0
+
+	if m.name != "" {
+		if info.Path, err = winhandle.ObjectName(windows.Handle(m.handle)); err != nil {
+			return Info{}, statError(m.name, err)
+		}
+	}
+
+	if info.Access&ReadControl != 0 {
+		sd, err := windows.GetSecurityInfo(windows.Handle(m.handle), windows.SE_KERNEL_OBJECT,
+			windows.OWNER_SECURITY_INFORMATION|windows.GROUP_SECURITY_INFORMATION|windows.DACL_SECURITY_INFORMATION)
+		if err != nil {
+			return Info{}, statError(m.name, err)
+		}
+		info.SecurityDescriptor = sd
+		info.Owner, _, _ = sd.Owner()
+	}
+
+	return info, nil
+}
+
+func statError(name string, err error) error {
+	return fmt.Errorf("winmutex: failed to query %s: %w", mutexDescription(name), mapError(err))
+}
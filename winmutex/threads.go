@@ -0,0 +1,89 @@
+//go:build windows
+
+package winmutex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/internal/lockedthread"
+)
+
+// ErrThreadLimit is returned when a mutex cannot be locked because the
+// limit set by SetThreadLimit has been reached.
+var ErrThreadLimit = errors.New("winmutex: the limit on locked operating system threads has been reached")
+
+// SetThreadLimit sets the maximum number of operating system threads that
+// the package will lock at once. Each locked Mutex consumes one thread,
+// and a small number of idle threads are retained for reuse. A max of
+// zero, which is the default, removes the limit.
+//
+// When the limit has been reached, attempts to lock a Mutex either wait
+// for another Mutex to be unlocked, if wait is true, or fail with
+// ErrThreadLimit.
+//
+// Lowering the limit does not unlock any mutexes that are already locked.
+func SetThreadLimit(max int, wait bool) {
+	lockedthread.SetLimit(max, wait)
+}
+
+// ThreadLimit returns the limit set by SetThreadLimit and the number of
+// operating system threads currently locked by the package, including
+// idle threads.
+func ThreadLimit() (max, active int) {
+	return lockedthread.Limit()
+}
+
+// HealthCheck verifies that every operating system thread locked by the
+// package is responsive, as described by Mutex.HealthCheck. It returns an
+// error describing each thread that did not respond before ctx was
+// cancelled.
+func HealthCheck(ctx context.Context) error {
+	if err := lockedthread.PingAll(ctx); err != nil {
+		return fmt.Errorf("winmutex: health check failed: %w", err)
+	}
+	return nil
+}
+
+// getThread allocates a locked operating system thread from the pool,
+// within the limit set by SetThreadLimit. If it must wait for a thread,
+// it stops waiting when ctx is done.
+func getThread(ctx context.Context) (*lockedthread.Thread, error) {
+	thread, err := lockedthread.Get(ctx)
+	if errors.Is(err, lockedthread.ErrLimitReached) {
+		return nil, ErrThreadLimit
+	}
+	return thread, err
+}
+
+// tryGetThread allocates a locked operating system thread from the pool
+// as getThread does, but never waits. If the limit set by SetThreadLimit
+// has been reached and is configured to wait, it returns a nil thread and
+// a nil error.
+func tryGetThread() (*lockedthread.Thread, error) {
+	thread, err := lockedthread.TryGet()
+	if errors.Is(err, lockedthread.ErrLimitReached) {
+		return nil, ErrThreadLimit
+	}
+	return thread, err
+}
+
+// waitThread allocates a locked operating system thread for m as getThread
+// does. If it must wait for a thread, it stops waiting when ctx is done, m
+// is closed, or the deadline passes, unless the deadline is zero.
+func (m *Mutex) waitThread(ctx context.Context, deadline time.Time) (*lockedthread.Thread, error) {
+	var cancel context.CancelFunc
+	if deadline.IsZero() {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	stop := context.AfterFunc(m.done, cancel)
+	defer stop()
+
+	return getThread(ctx)
+}
@@ -0,0 +1,263 @@
+//go:build windows
+
+package winmutex_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+)
+
+func TestThreadLimitFail(t *testing.T) {
+	winmutex.SetThreadLimit(1, false)
+	defer winmutex.SetThreadLimit(0, false)
+
+	mutex1, err := winmutex.New(testMutexName("ThreadLimitFail-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(testMutexName("ThreadLimitFail-2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	// Unlocked mutexes do not consume threads, so the limit only applies
+	// once they are locked.
+	mutex1.Lock()
+	defer mutex1.Unlock()
+
+	release, err := mutex2.Acquire(context.Background())
+	if err == nil {
+		release()
+		t.Fatal("A mutex was locked when the thread limit should have been reached")
+	}
+	if !errors.Is(err, winmutex.ErrThreadLimit) {
+		t.Fatalf("Acquire returned %v when it should have returned %v", err, winmutex.ErrThreadLimit)
+	}
+}
+
+func TestThreadLimitWait(t *testing.T) {
+	winmutex.SetThreadLimit(1, true)
+	defer winmutex.SetThreadLimit(0, false)
+
+	mutex1, err := winmutex.New(testMutexName("ThreadLimitWait-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(testMutexName("ThreadLimitWait-2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	mutex1.Lock()
+
+	locked := make(chan error, 1)
+	go func() {
+		release, err := mutex2.Acquire(context.Background())
+		if err == nil {
+			release()
+		}
+		locked <- err
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("A mutex was locked when the thread limit should have been reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	mutex1.Unlock()
+
+	select {
+	case err := <-locked:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("A mutex was not locked after a thread was released")
+	}
+}
+
+func TestThreadLimitWaitCancel(t *testing.T) {
+	winmutex.SetThreadLimit(1, true)
+	defer winmutex.SetThreadLimit(0, false)
+
+	mutex1, err := winmutex.New(testMutexName("ThreadLimitWaitCancel-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(testMutexName("ThreadLimitWaitCancel-2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	mutex1.Lock()
+	defer mutex1.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := mutex2.LockContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LockContext returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+
+	locked, err := mutex2.LockFor(50 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if locked {
+		mutex2.Unlock()
+		t.Fatal("LockFor locked a mutex when the thread limit should have been reached")
+	}
+
+	// Closing the mutex must interrupt a goroutine waiting for a thread.
+	result := make(chan error, 1)
+	go func() {
+		result <- mutex2.LockContext(context.Background())
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- mutex2.Close()
+	}()
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked while a goroutine was waiting for a thread")
+	}
+
+	select {
+	case err := <-result:
+		if err == nil {
+			t.Fatal("LockContext locked a mutex that was closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("LockContext did not return after the mutex was closed")
+	}
+}
+
+func TestThreadLimitWaitTryLock(t *testing.T) {
+	winmutex.SetThreadLimit(1, true)
+	defer winmutex.SetThreadLimit(0, false)
+
+	mutex1, err := winmutex.New(testMutexName("ThreadLimitWaitTryLock-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex1.Close()
+
+	mutex2, err := winmutex.New(testMutexName("ThreadLimitWaitTryLock-2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex2.Close()
+
+	mutex1.Lock()
+	defer mutex1.Unlock()
+
+	// TryLock must not wait for a thread to become available.
+	result := make(chan bool, 1)
+	go func() {
+		result <- mutex2.TryLock()
+	}()
+
+	select {
+	case locked := <-result:
+		if locked {
+			mutex2.Unlock()
+			t.Fatal("TryLock locked a mutex when the thread limit should have been reached")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("TryLock waited for a thread when the thread limit was reached")
+	}
+}
+
+func TestThreadsReleasedWhenUnlocked(t *testing.T) {
+	const count = 32
+
+	_, before := winmutex.ThreadLimit()
+
+	mutexes := make([]*winmutex.Mutex, count)
+	for i := range mutexes {
+		mutex, err := winmutex.New(testMutexName(fmt.Sprintf("ThreadsReleased-%d", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer mutex.Close()
+		mutex.Lock()
+		mutex.Unlock()
+		mutexes[i] = mutex
+	}
+
+	// Only a handful of idle threads should be retained by the pool.
+	if _, after := winmutex.ThreadLimit(); after-before >= count {
+		t.Fatalf("%d unlocked mutexes are holding %d threads", count, after-before)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	mutex, err := winmutex.New(testMutexName("HealthCheck"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := mutex.HealthCheck(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := winmutex.HealthCheck(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHealthCheckBusy(t *testing.T) {
+	name := testMutexName("HealthCheckBusy")
+
+	holder, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+	holder.Lock()
+	defer holder.Unlock()
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	// Keep the mutex's thread busy with an indefinite wait.
+	waitCtx, stopWaiting := context.WithCancel(context.Background())
+	defer stopWaiting()
+	go mutex.Acquire(waitCtx)
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := mutex.HealthCheck(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("HealthCheck returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,78 @@
+//go:build windows
+
+package winobjdir
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winobj/api/ntobapi"
+	"golang.org/x/sys/windows"
+)
+
+// Entry describes a single object within an object directory.
+type Entry struct {
+	// Name is the name of the object within its directory.
+	Name string
+
+	// Type is the object manager's name for the type of the object, such
+	// as "Mutant", "Event", "Semaphore", "Section" or "Directory".
+	Type string
+}
+
+// List returns the entries of the object directory with the given object
+// manager path, such as GlobalDir or SessionDir(1).
+//
+// Entries are returned in the order they are reported by the object
+// manager, which is not sorted.
+func List(dir string) ([]Entry, error) {
+	handle, err := ntobapi.NtOpenDirectoryObject(dir, ntobapi.DirectoryQuery)
+	if err != nil {
+		return nil, fmt.Errorf("winobjdir: failed to open the %s directory: %w", dir, err)
+	}
+	defer syscall.CloseHandle(handle)
+
+	var (
+		entries []Entry
+		context uint32
+		restart = true
+		buffer  = make([]byte, 64*1024)
+	)
+	for {
+		_, status := ntobapi.NtQueryDirectoryObject(handle, buffer, restart, &context)
+		switch status {
+		case windows.STATUS_SUCCESS, windows.STATUS_MORE_ENTRIES:
+			entries = appendEntries(entries, buffer)
+			if status == windows.STATUS_SUCCESS {
+				return entries, nil
+			}
+			restart = false
+		case windows.STATUS_NO_MORE_ENTRIES:
+			return entries, nil
+		case windows.STATUS_BUFFER_TOO_SMALL:
+			// A single entry didn't fit within the buffer. Grow the buffer
+			// and try again from the same position.
+			buffer = make([]byte, len(buffer)*2)
+		default:
+			return nil, fmt.Errorf("winobjdir: failed to query the %s directory: %w", dir, status)
+		}
+	}
+}
+
+// appendEntries parses the directory information in buffer and appends
+// the entries it describes to entries.
+func appendEntries(entries []Entry, buffer []byte) []Entry {
+	const size = unsafe.Sizeof(ntobapi.DirectoryInformation{})
+	for offset := uintptr(0); offset+size <= uintptr(len(buffer)); offset += size {
+		info := (*ntobapi.DirectoryInformation)(unsafe.Pointer(&buffer[offset]))
+		if info.Name.Buffer == nil && info.TypeName.Buffer == nil {
+			break
+		}
+		entries = append(entries, Entry{
+			Name: info.Name.String(),
+			Type: info.TypeName.String(),
+		})
+	}
+	return entries
+}
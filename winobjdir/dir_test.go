@@ -0,0 +1,49 @@
+//go:build windows
+
+package winobjdir_test
+
+import (
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"github.com/gentlemanautomaton/winobj/winobjdir"
+	"golang.org/x/sys/windows"
+)
+
+func TestListFindsMutex(t *testing.T) {
+	const name = "WinObj-WinObjDir-Test-ListFindsMutex"
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Close()
+
+	var session uint32
+	if err := windows.ProcessIdToSessionId(windows.GetCurrentProcessId(), &session); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := winobjdir.List(winobjdir.SessionDir(session))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entry := range entries {
+		if entry.Name != name {
+			continue
+		}
+		if entry.Type != "Mutant" {
+			t.Fatalf("The %s entry has type %q when it should have type \"Mutant\"", name, entry.Type)
+		}
+		return
+	}
+
+	t.Fatalf("The %s mutex was not found in the session directory", name)
+}
+
+func TestListBadDirectory(t *testing.T) {
+	if _, err := winobjdir.List(`\WinObj-WinObjDir-Test-DoesNotExist`); err == nil {
+		t.Fatal("A directory that does not exist was listed successfully")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+// Package winobjdir provides access to object directories in the Windows
+// Object Manager namespace.
+//
+// Object directories are the containers that hold named kernel objects.
+// Named objects created with the "Global\" prefix live in the
+// \BaseNamedObjects directory, while objects created in a session's local
+// namespace live in \Sessions\<n>\BaseNamedObjects.
+package winobjdir
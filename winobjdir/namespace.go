@@ -0,0 +1,60 @@
+//go:build windows
+
+package winobjdir
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gentlemanautomaton/winobj"
+	"golang.org/x/sys/windows"
+)
+
+// GlobalDir is the object manager path of the directory that holds named
+// objects in the global namespace.
+const GlobalDir = `\BaseNamedObjects`
+
+// SessionDir returns the object manager path of the directory that holds
+// named objects in the local namespace of the given terminal services
+// session.
+//
+// Session 0 does not have a separate local namespace, so for session 0 it
+// returns GlobalDir.
+func SessionDir(session uint32) string {
+	if session == 0 {
+		return GlobalDir
+	}
+	return `\Sessions\` + strconv.FormatUint(uint64(session), 10) + `\BaseNamedObjects`
+}
+
+// Path returns the object manager path of the named object with the given
+// Win32 name. For example, `Global\MyApp-Lock` becomes
+// `\BaseNamedObjects\MyApp-Lock`, and `Local\MyApp-Lock` becomes
+// `\Sessions\1\BaseNamedObjects\MyApp-Lock` in session 1.
+//
+// Names with the "Local\" prefix or no prefix are placed in the local
+// namespace of the session that the calling process belongs to. Names
+// that are already object manager paths are returned unchanged. Names
+// within a private namespace cannot be translated, because the object
+// manager does not give private namespaces a path.
+func Path(name string) (string, error) {
+	n := winobj.Name(name)
+	switch n.Namespace() {
+	case winobj.ObjectPathNamespace:
+		return name, nil
+	case winobj.GlobalNamespace:
+		return GlobalDir + `\` + n.Base(), nil
+	case winobj.SessionNamespace:
+		session, ok := n.Session()
+		if !ok {
+			return "", fmt.Errorf("winobjdir: the name \"%s\" does not have a valid session ID", name)
+		}
+		return SessionDir(session) + `\` + n.Base(), nil
+	default:
+		var session uint32
+		if err := windows.ProcessIdToSessionId(windows.GetCurrentProcessId(), &session); err != nil {
+			return "", fmt.Errorf("winobjdir: failed to determine the current session: %w", err)
+		}
+		return SessionDir(session) + `\` + n.Base(), nil
+	}
+}
@@ -0,0 +1,113 @@
+//go:build windows
+
+package winobjdir
+
+import (
+	"context"
+	"time"
+)
+
+// Op identifies the kind of change that was observed in a directory.
+type Op int
+
+// Directory change operations.
+const (
+	Created Op = iota + 1 // An object was added to the directory
+	Removed               // An object was removed from the directory
+)
+
+// String returns a string representation of the operation.
+func (op Op) String() string {
+	switch op {
+	case Created:
+		return "created"
+	case Removed:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// Change describes an object that was added to or removed from a
+// directory.
+type Change struct {
+	Op    Op
+	Entry Entry
+}
+
+// Watch monitors the object directory with the given object manager path
+// and sends a Change to ch for each object that is added to or removed from
+// it. It blocks until ctx is cancelled or the directory can no longer be
+// listed, and returns the reason it stopped.
+//
+// The object manager does not provide change notifications, so Watch
+// lists the directory every interval and reports the differences between
+// successive listings. Objects that are created and destroyed within a
+// single interval will not be reported.
+//
+// Watch blocks while sending to ch, so slow receivers delay subsequent
+// polls rather than missing changes.
+func Watch(ctx context.Context, dir string, interval time.Duration, ch chan<- Change) error {
+	// Take an initial listing that subsequent listings will be compared
+	// against.
+	previous, err := listSet(dir)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		current, err := listSet(dir)
+		if err != nil {
+			return err
+		}
+
+		for entry := range current {
+			if _, ok := previous[entry]; !ok {
+				if err := send(ctx, ch, Change{Op: Created, Entry: entry}); err != nil {
+					return err
+				}
+			}
+		}
+		for entry := range previous {
+			if _, ok := current[entry]; !ok {
+				if err := send(ctx, ch, Change{Op: Removed, Entry: entry}); err != nil {
+					return err
+				}
+			}
+		}
+
+		previous = current
+	}
+}
+
+// listSet returns the entries of dir as a set.
+func listSet(dir string) (map[Entry]struct{}, error) {
+	entries, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[Entry]struct{}, len(entries))
+	for _, entry := range entries {
+		set[entry] = struct{}{}
+	}
+	return set, nil
+}
+
+// send sends change to ch unless ctx is cancelled first.
+func send(ctx context.Context, ch chan<- Change, change Change) error {
+	select {
+	case ch <- change:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
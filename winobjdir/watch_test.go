@@ -0,0 +1,64 @@
+//go:build windows
+
+package winobjdir_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"github.com/gentlemanautomaton/winobj/winobjdir"
+	"golang.org/x/sys/windows"
+)
+
+func TestWatchCreatedAndRemoved(t *testing.T) {
+	const name = "WinObj-WinObjDir-Test-WatchCreatedAndRemoved"
+
+	var session uint32
+	if err := windows.ProcessIdToSessionId(windows.GetCurrentProcessId(), &session); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	changes := make(chan winobjdir.Change)
+	done := make(chan error, 1)
+	go func() {
+		done <- winobjdir.Watch(ctx, winobjdir.SessionDir(session), 10*time.Millisecond, changes)
+	}()
+
+	// Give the watcher a chance to take its initial listing.
+	time.Sleep(100 * time.Millisecond)
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForChange(t, ctx, changes, winobjdir.Created, name)
+
+	if err := mutex.Close(); err != nil {
+		t.Fatal(err)
+	}
+	waitForChange(t, ctx, changes, winobjdir.Removed, name)
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Watch returned %v when it should have returned %v", err, context.Canceled)
+	}
+}
+
+func waitForChange(t *testing.T, ctx context.Context, changes <-chan winobjdir.Change, op winobjdir.Op, name string) {
+	t.Helper()
+	for {
+		select {
+		case change := <-changes:
+			if change.Op == op && change.Entry.Name == name {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatalf("The %s mutex was not reported as %s", name, op)
+		}
+	}
+}
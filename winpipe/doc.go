@@ -0,0 +1,9 @@
+//go:build windows
+
+// Package winpipe provides helpers for coordinating with named pipe
+// servers on Windows.
+//
+// It does not implement pipe I/O itself. Instead, it lets a client
+// determine whether a server's pipe exists and wait for it to become
+// connectable before dialing it with the package of its choice.
+package winpipe
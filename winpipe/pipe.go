@@ -0,0 +1,122 @@
+//go:build windows
+
+package winpipe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/api/namedpipeapi"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// pollInterval is how often Wait checks for a pipe that does not exist
+// yet, and the longest that a single wait for a busy pipe lasts before
+// the context is checked again.
+const pollInterval = 50 * time.Millisecond
+
+// State describes the availability of a named pipe.
+type State int
+
+// Named pipe states.
+const (
+	NotFound State = iota // No pipe with the name exists
+	Busy                  // The pipe exists but has no instance available for connection
+	Ready                 // The pipe exists and an instance is available for connection
+)
+
+// String returns a string representation of the state.
+func (s State) String() string {
+	switch s {
+	case NotFound:
+		return "not found"
+	case Busy:
+		return "busy"
+	case Ready:
+		return "ready"
+	default:
+		return "unknown"
+	}
+}
+
+// Probe reports the state of the named pipe with the given name without
+// connecting to it. If name does not begin with "\\", it is treated as a
+// local pipe name and the \\.\pipe\ prefix is added to it.
+func Probe(name string) (State, error) {
+	path := Path(name)
+	switch err := namedpipeapi.WaitNamedPipe(path, 1); err {
+	case nil:
+		return Ready, nil
+	case windows.ERROR_FILE_NOT_FOUND:
+		return NotFound, nil
+	case windows.ERROR_SEM_TIMEOUT:
+		return Busy, nil
+	default:
+		return NotFound, fmt.Errorf("winpipe: failed to probe %s: %w", path, err)
+	}
+}
+
+// Exists reports whether a named pipe with the given name exists.
+func Exists(name string) (bool, error) {
+	state, err := Probe(name)
+	return state != NotFound, err
+}
+
+// Wait blocks until the named pipe with the given name exists and has an
+// instance available for connection, or until ctx is cancelled. If name
+// does not begin with "\\", it is treated as a local pipe name and the
+// \\.\pipe\ prefix is added to it.
+//
+// A pipe instance that is available when Wait returns may still be taken
+// by another client before the caller connects to it, so callers should be
+// prepared to retry.
+func Wait(ctx context.Context, name string) error {
+	path := Path(name)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// A zero timeout asks for the pipe's default timeout and INFINITE
+		// asks for an unbounded wait, so the timeout must stay between
+		// them. A deadline that passes between the check above and this
+		// one would otherwise wrap around to an unbounded wait.
+		timeout := pollInterval
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return context.DeadlineExceeded
+			}
+			timeout = min(timeout, remaining)
+		}
+		milliseconds := max(synchapi.WaitMilliseconds(timeout), 1)
+
+		switch err := namedpipeapi.WaitNamedPipe(path, milliseconds); err {
+		case nil:
+			return nil
+		case windows.ERROR_FILE_NOT_FOUND:
+			// The server hasn't created the pipe yet.
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pollInterval):
+			}
+		case windows.ERROR_SEM_TIMEOUT:
+			// The pipe exists but every instance is busy.
+		default:
+			return fmt.Errorf("winpipe: failed to wait for %s: %w", path, err)
+		}
+	}
+}
+
+// Path returns the full path of the named pipe with the given name. If
+// name already begins with "\\", it is returned unchanged.
+func Path(name string) string {
+	if strings.HasPrefix(name, `\\`) {
+		return name
+	}
+	return `\\.\pipe\` + name
+}
@@ -0,0 +1,80 @@
+//go:build windows
+
+package winpipe_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winpipe"
+	"golang.org/x/sys/windows"
+)
+
+func TestProbe(t *testing.T) {
+	name := testPipeName("Probe")
+
+	state, err := winpipe.Probe(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != winpipe.NotFound {
+		t.Fatalf("Probe returned %s before the pipe was created", state)
+	}
+
+	pipe := createPipe(t, name)
+	defer windows.CloseHandle(pipe)
+
+	state, err = winpipe.Probe(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != winpipe.Ready {
+		t.Fatalf("Probe returned %s after the pipe was created", state)
+	}
+}
+
+func TestWaitForLateServer(t *testing.T) {
+	name := testPipeName("WaitForLateServer")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	created := make(chan windows.Handle, 1)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		created <- createPipe(t, name)
+	}()
+	defer func() { windows.CloseHandle(<-created) }()
+
+	if err := winpipe.Wait(ctx, name); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := winpipe.Wait(ctx, testPipeName("WaitTimeout")); err != context.DeadlineExceeded {
+		t.Fatalf("Wait returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+}
+
+func createPipe(t *testing.T, name string) windows.Handle {
+	path, err := windows.UTF16PtrFromString(winpipe.Path(name))
+	if err != nil {
+		t.Error(err)
+		return 0
+	}
+	pipe, err := windows.CreateNamedPipe(path, windows.PIPE_ACCESS_DUPLEX, windows.PIPE_TYPE_BYTE, 1, 4096, 4096, 0, nil)
+	if err != nil {
+		t.Error(err)
+		return 0
+	}
+	return pipe
+}
+
+func testPipeName(name string) string {
+	return `WinObj-WinPipe-Test-` + name
+}
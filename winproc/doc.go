@@ -0,0 +1,12 @@
+//go:build windows
+
+// Package winproc provides references to processes on Windows that are
+// safe from process ID reuse.
+//
+// Windows recycles process IDs soon after a process exits, so a process
+// ID alone cannot reliably identify a process that is observed over time
+// or shared between programs. This package pairs each process ID with the
+// time that the process was created. A Ref holding both can be stored or
+// sent to another process, and opening it later fails with ErrPidReused
+// if the ID now belongs to a different process.
+package winproc
@@ -0,0 +1,204 @@
+//go:build windows
+
+package winproc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// ErrPidReused is returned when a process ID no longer refers to the
+// process that it originally identified.
+var ErrPidReused = errors.New("winproc: the process ID has been reused by another process")
+
+// access is the access requested when opening a process.
+const access = windows.PROCESS_QUERY_LIMITED_INFORMATION | windows.SYNCHRONIZE
+
+// stillActive is the exit code reported for a process that is running.
+const stillActive = 259 // STILL_ACTIVE
+
+// Ref identifies a specific process by its ID and creation time. Unlike a
+// process ID alone, a Ref cannot be confused with a later process that
+// happens to be assigned the same ID.
+type Ref struct {
+	PID     uint32
+	Created time.Time
+}
+
+// String returns a string representation of the reference.
+func (r Ref) String() string {
+	return fmt.Sprintf("%d@%s", r.PID, r.Created.UTC().Format(time.RFC3339Nano))
+}
+
+// Open opens the process identified by r. If the process ID now belongs
+// to a different process, it returns ErrPidReused.
+//
+// It is the caller's responsibility to close the returned process.
+func (r Ref) Open() (*Process, error) {
+	p, err := Open(r.PID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.created.Equal(r.Created) {
+		p.Close()
+		return nil, fmt.Errorf("%w: process %d was created at %s rather than %s", ErrPidReused, r.PID, p.created.Format(time.RFC3339Nano), r.Created.Format(time.RFC3339Nano))
+	}
+	return p, nil
+}
+
+// Process is an open handle to a process, along with the identity that it
+// had when it was opened.
+//
+// While a process is open, Windows will not reuse its ID, even if the
+// process exits.
+type Process struct {
+	handle  windows.Handle
+	pid     uint32
+	created time.Time
+}
+
+// Open opens the process with the given ID and records its creation time.
+//
+// It is the caller's responsibility to close the returned process.
+func Open(pid uint32) (*Process, error) {
+	handle, err := windows.OpenProcess(access, false, pid)
+	if err != nil {
+		return nil, fmt.Errorf("winproc: failed to open process %d: %w", pid, err)
+	}
+
+	created, err := creationTime(handle)
+	if err != nil {
+		windows.CloseHandle(handle)
+		return nil, fmt.Errorf("winproc: failed to query process %d: %w", pid, err)
+	}
+
+	return &Process{handle: handle, pid: pid, created: created}, nil
+}
+
+// Current opens the calling process.
+//
+// It is the caller's responsibility to close the returned process.
+func Current() (*Process, error) {
+	return Open(windows.GetCurrentProcessId())
+}
+
+// PID returns the ID of the process.
+func (p *Process) PID() uint32 {
+	return p.pid
+}
+
+// Created returns the time at which the process was created.
+func (p *Process) Created() time.Time {
+	return p.created
+}
+
+// Ref returns a reference to the process that can be stored and opened
+// later.
+func (p *Process) Ref() Ref {
+	return Ref{PID: p.pid, Created: p.created}
+}
+
+// Verify confirms that the process handle still refers to the process
+// that was originally opened. It returns ErrPidReused if it does not.
+func (p *Process) Verify() error {
+	created, err := creationTime(p.handle)
+	if err != nil {
+		return fmt.Errorf("winproc: failed to query process %d: %w", p.pid, err)
+	}
+	if !created.Equal(p.created) {
+		return fmt.Errorf("%w: process %d", ErrPidReused, p.pid)
+	}
+	return nil
+}
+
+// Exited verifies the process and reports whether it has exited. If it
+// has, its exit code is returned.
+func (p *Process) Exited() (exited bool, code uint32, err error) {
+	if err := p.Verify(); err != nil {
+		return false, 0, err
+	}
+	if err := windows.GetExitCodeProcess(p.handle, &code); err != nil {
+		return false, 0, fmt.Errorf("winproc: failed to query the exit code of process %d: %w", p.pid, err)
+	}
+	if code == stillActive {
+		// A process can exit with the STILL_ACTIVE code, so confirm that
+		// the process is still running before reporting it as such.
+		event, err := windows.WaitForSingleObject(p.handle, 0)
+		if err != nil {
+			return false, 0, fmt.Errorf("winproc: failed to query process %d: %w", p.pid, err)
+		}
+		if event == uint32(windows.WAIT_TIMEOUT) {
+			return false, 0, nil
+		}
+	}
+	return true, code, nil
+}
+
+// Wait verifies the process and then waits for it to exit. It returns the
+// exit code of the process, or an error if ctx is cancelled first.
+func (p *Process) Wait(ctx context.Context) (code uint32, err error) {
+	if err := p.Verify(); err != nil {
+		return 0, err
+	}
+
+	// Prepare an event that will be signaled if ctx is cancelled, so that
+	// the wait can be interrupted.
+	cancelled, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return 0, fmt.Errorf("winproc: failed to create cancellation event: %w", err)
+	}
+	defer windows.CloseHandle(cancelled)
+
+	stop := context.AfterFunc(ctx, func() {
+		windows.SetEvent(cancelled)
+	})
+	defer stop()
+
+	event, err := windows.WaitForMultipleObjects([]windows.Handle{p.handle, cancelled}, false, windows.INFINITE)
+	switch event {
+	case windows.WAIT_OBJECT_0:
+	case windows.WAIT_OBJECT_0 + 1:
+		return 0, ctx.Err()
+	default:
+		if err == nil {
+			err = fmt.Errorf("unexpected wait result: %d", event)
+		}
+		return 0, fmt.Errorf("winproc: failed to wait for process %d: %w", p.pid, err)
+	}
+
+	if err := windows.GetExitCodeProcess(p.handle, &code); err != nil {
+		return 0, fmt.Errorf("winproc: failed to query the exit code of process %d: %w", p.pid, err)
+	}
+	return code, nil
+}
+
+// Handle returns the underlying handle of the process. It remains owned by
+// the process and must not be closed by the caller.
+func (p *Process) Handle() windows.Handle {
+	return p.handle
+}
+
+// Close closes the process handle. Once every handle to an exited process
+// has been closed, its ID may be reused.
+func (p *Process) Close() error {
+	if p.handle == 0 {
+		return nil
+	}
+	err := windows.CloseHandle(p.handle)
+	p.handle = 0
+	return err
+}
+
+// creationTime returns the time at which the process with the given
+// handle was created.
+func creationTime(handle windows.Handle) (time.Time, error) {
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, creation.Nanoseconds()), nil
+}
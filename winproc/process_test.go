@@ -0,0 +1,91 @@
+//go:build windows
+
+package winproc_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winproc"
+)
+
+const helperEnvVar = "WINOBJ_WINPROC_TEST_HELPER"
+
+func TestMain(m *testing.M) {
+	// When started as a helper, exit with a recognizable code.
+	if os.Getenv(helperEnvVar) != "" {
+		os.Exit(3)
+	}
+	os.Exit(m.Run())
+}
+
+func TestCurrent(t *testing.T) {
+	p, err := winproc.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	if pid := uint32(os.Getpid()); p.PID() != pid {
+		t.Fatalf("The current process has ID %d when it should have %d", p.PID(), pid)
+	}
+
+	exited, _, err := p.Exited()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exited {
+		t.Fatal("The current process was reported as having exited")
+	}
+
+	same, err := p.Ref().Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	same.Close()
+}
+
+func TestRefReused(t *testing.T) {
+	p, err := winproc.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	ref := p.Ref()
+	ref.Created = ref.Created.Add(-time.Second)
+
+	if _, err := ref.Open(); !errors.Is(err, winproc.ErrPidReused) {
+		t.Fatalf("Open returned %v when it should have returned %v", err, winproc.ErrPidReused)
+	}
+}
+
+func TestWait(t *testing.T) {
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), helperEnvVar+"=1")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Wait()
+
+	p, err := winproc.Open(uint32(cmd.Process.Pid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	code, err := p.Wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 3 {
+		t.Fatalf("The helper process exited with code %d when it should have exited with 3", code)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+// Package winprogress shares the progress of a long-running operation
+// between processes on Windows.
+//
+// A worker process, such as an installer or an agent, creates a Progress
+// and updates it as it works. A controlling process, such as a user
+// interface, opens the same Progress by name and subscribes to it. The
+// progress is held in a small named shared memory section, and named
+// events wake subscribers whenever it changes.
+package winprogress
@@ -0,0 +1,312 @@
+//go:build windows
+
+package winprogress
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/winshm"
+	"golang.org/x/sys/windows"
+)
+
+// Version is the version of the progress layout written by this package.
+const Version = 1
+
+// SignalSuffix is appended to the name of a progress, followed by 0 or 1,
+// to form the names of the events that announce changes to it.
+const SignalSuffix = "-Signal"
+
+// MaxStatusLength is the maximum length of a status message, in bytes.
+// Longer messages are truncated.
+const MaxStatusLength = 256
+
+// pollInterval is the number of milliseconds that a subscriber waits for
+// a change signal before checking for changes again. It bounds the delay
+// when several changes are made in quick succession.
+const pollInterval = 100
+
+// readTimeout is the longest that a reader waits for an update that is
+// being written to complete. An update that takes longer was most likely
+// interrupted by the exit of the process that was writing it.
+const readTimeout = time.Second
+
+// ErrTornWrite is returned when a progress cannot be read because an
+// update to it was never completed, such as when the process writing it
+// exited part way through. The next update repairs the progress.
+var ErrTornWrite = errors.New("winprogress: the progress was left partially written by an update that did not complete")
+
+// schema identifies a section that holds a progress record.
+var schema = winshm.Schema{Magic: "WOPROG", Version: Version}
+
+// Layout of the progress record.
+const (
+	offsetSequence  = 0 // Odd while an update is being written
+	offsetCurrent   = 8
+	offsetTotal     = 16
+	offsetStatusLen = 24
+	offsetStatus    = 32
+	recordSize      = offsetStatus + MaxStatusLength
+)
+
+// Snapshot is the state of a progress at a point in time.
+type Snapshot struct {
+	Current uint64
+	Total   uint64
+	Status  string
+}
+
+// Fraction returns the fraction of the work that has been completed, from
+// 0 to 1. If the total is unknown, it returns 0.
+func (s Snapshot) Fraction() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return min(float64(s.Current)/float64(s.Total), 1)
+}
+
+// String returns a string representation of the snapshot.
+func (s Snapshot) String() string {
+	if s.Status == "" {
+		return fmt.Sprintf("%d/%d", s.Current, s.Total)
+	}
+	return fmt.Sprintf("%d/%d: %s", s.Current, s.Total, s.Status)
+}
+
+// Progress is the progress of an operation, shared between processes.
+//
+// Only one process should update a progress at a time. Any number of
+// processes may read it and subscribe to it.
+type Progress struct {
+	name    string
+	section *winshm.Section
+	signals [2]windows.Handle
+}
+
+// Create creates a progress with the given name, or opens it if it already
+// exists. It is intended to be called by the worker process that updates
+// the progress. Options such as winobj.WithSecurityDescriptor are applied
+// to each of the objects that make up a new progress.
+//
+// It is the caller's responsibility to close the progress.
+func Create(name string, opts ...winobj.Option) (*Progress, error) {
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("winprogress: failed to create the %s progress: %w", name, err)
+	}
+	section, _, err := winshm.Create(name, schema, recordSize, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newProgress(name, section, options.SecurityAttributes())
+}
+
+// Open opens an existing progress with the given name. It is intended to
+// be called by processes that read the progress.
+//
+// It is the caller's responsibility to close the progress.
+func Open(name string) (*Progress, error) {
+	section, err := winshm.Open(name, schema)
+	if err != nil {
+		return nil, err
+	}
+	return newProgress(name, section, nil)
+}
+
+// newProgress creates or opens the change signals for a progress held in
+// section. Signals that do not exist are created with attrs. If it fails,
+// the section is closed.
+func newProgress(name string, section *winshm.Section, attrs *windows.SecurityAttributes) (*Progress, error) {
+	p := &Progress{name: name, section: section}
+	if len(section.Bytes()) < recordSize {
+		p.Close()
+		return nil, fmt.Errorf("winprogress: the %s progress is too small", name)
+	}
+
+	for i := range p.signals {
+		utf16Name, err := windows.UTF16PtrFromString(winobj.Qualify(fmt.Sprintf("%s%s%d", name, SignalSuffix, i)))
+		if err == nil {
+			p.signals[i], err = windows.CreateEvent(attrs, 1, 0, utf16Name)
+			if err == windows.ERROR_ALREADY_EXISTS {
+				err = nil
+			}
+		}
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("winprogress: failed to create the signal for the %s progress: %w", name, err)
+		}
+	}
+
+	return p, nil
+}
+
+// Name returns the name of the progress.
+func (p *Progress) Name() string {
+	return p.name
+}
+
+// Set updates the progress and wakes its subscribers. A status longer than
+// MaxStatusLength bytes is truncated.
+func (p *Progress) Set(s Snapshot) error {
+	status := truncateStatus(s.Status)
+
+	data := p.section.Bytes()
+	le := binary.LittleEndian
+
+	// Mark the record as being written, so that readers retry until the
+	// update is complete.
+	seq := p.sequence()
+	start := seq.Load() | 1
+	seq.Store(start)
+
+	le.PutUint64(data[offsetCurrent:], s.Current)
+	le.PutUint64(data[offsetTotal:], s.Total)
+	le.PutUint32(data[offsetStatusLen:], uint32(len(status)))
+	copy(data[offsetStatus:], status)
+
+	end := start + 1
+	seq.Store(end)
+
+	// Each update sets one signal and resets the other, so that
+	// subscribers waiting for the next update are woken while those that
+	// have already seen it are not.
+	generation := end / 2
+	if err := windows.ResetEvent(p.signals[(generation+1)%2]); err != nil {
+		return fmt.Errorf("winprogress: failed to reset the signal for the %s progress: %w", p.name, err)
+	}
+	if err := windows.SetEvent(p.signals[generation%2]); err != nil {
+		return fmt.Errorf("winprogress: failed to signal subscribers of the %s progress: %w", p.name, err)
+	}
+	return nil
+}
+
+// Get returns the current state of the progress. If an update is left
+// incomplete for too long, it returns ErrTornWrite.
+func (p *Progress) Get() (Snapshot, error) {
+	s, _, err := p.read()
+	return s, err
+}
+
+// Subscribe sends the current state of the progress to ch, and then sends
+// its new state each time it changes. It blocks until ctx is cancelled or
+// the progress can no longer be waited on, and returns the reason it
+// stopped.
+//
+// Changes that are made while Subscribe is sending to ch may be coalesced,
+// so that only the latest state is sent.
+func (p *Progress) Subscribe(ctx context.Context, ch chan<- Snapshot) error {
+	// Prepare an event that will be signaled if ctx is cancelled, so that
+	// the wait can be interrupted.
+	cancelled, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return fmt.Errorf("winprogress: failed to create cancellation event: %w", err)
+	}
+	defer windows.CloseHandle(cancelled)
+
+	stop := context.AfterFunc(ctx, func() {
+		windows.SetEvent(cancelled)
+	})
+	defer stop()
+
+	last := ^uint64(0)
+	for {
+		s, generation, err := p.read()
+		if err != nil {
+			return err
+		}
+		if generation != last {
+			last = generation
+			select {
+			case ch <- s:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		}
+
+		handles := []windows.Handle{p.signals[(generation+1)%2], cancelled}
+		event, err := windows.WaitForMultipleObjects(handles, false, pollInterval)
+		switch event {
+		case windows.WAIT_OBJECT_0, uint32(windows.WAIT_TIMEOUT):
+		case windows.WAIT_OBJECT_0 + 1:
+			return ctx.Err()
+		default:
+			if err == nil {
+				err = fmt.Errorf("unexpected wait result: %d", event)
+			}
+			return fmt.Errorf("winprogress: failed to wait for the %s progress: %w", p.name, err)
+		}
+	}
+}
+
+// Close closes the progress. It is destroyed once every process has closed
+// it.
+func (p *Progress) Close() error {
+	errs := []error{p.section.Close()}
+	for i, signal := range p.signals {
+		if signal != 0 {
+			errs = append(errs, windows.CloseHandle(signal))
+			p.signals[i] = 0
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// read returns a consistent copy of the progress record and the number of
+// updates that have been made to it. If it cannot obtain one within
+// readTimeout, it returns ErrTornWrite.
+func (p *Progress) read() (s Snapshot, generation uint64, err error) {
+	data := p.section.Bytes()
+	le := binary.LittleEndian
+	seq := p.sequence()
+
+	var deadline time.Time
+	for {
+		start := seq.Load()
+		if start%2 == 0 {
+			s.Current = le.Uint64(data[offsetCurrent:])
+			s.Total = le.Uint64(data[offsetTotal:])
+			n := min(int(le.Uint32(data[offsetStatusLen:])), MaxStatusLength)
+			s.Status = string(data[offsetStatus : offsetStatus+n])
+			if seq.Load() == start {
+				return s, start / 2, nil
+			}
+		}
+
+		// Only consult the clock once the first attempt has failed.
+		switch now := time.Now(); {
+		case deadline.IsZero():
+			deadline = now.Add(readTimeout)
+		case now.After(deadline):
+			return Snapshot{}, 0, fmt.Errorf("%w: %s", ErrTornWrite, p.name)
+		}
+		runtime.Gosched()
+	}
+}
+
+// sequence returns the sequence number of the progress record, which is
+// accessed atomically.
+func (p *Progress) sequence() *atomic.Uint64 {
+	return (*atomic.Uint64)(unsafe.Pointer(&p.section.Bytes()[offsetSequence]))
+}
+
+// truncateStatus shortens status to at most MaxStatusLength bytes without
+// splitting a UTF-8 sequence.
+func truncateStatus(status string) string {
+	if len(status) <= MaxStatusLength {
+		return status
+	}
+	end := MaxStatusLength
+	for end > 0 && !utf8.RuneStart(status[end]) {
+		end--
+	}
+	return status[:end]
+}
@@ -0,0 +1,153 @@
+//go:build windows
+
+package winprogress_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winprogress"
+	"github.com/gentlemanautomaton/winobj/winshm"
+)
+
+func TestProgressShared(t *testing.T) {
+	name := testProgressName("Shared")
+
+	writer, err := winprogress.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	reader, err := winprogress.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	want := winprogress.Snapshot{Current: 3, Total: 4, Status: "Copying files"}
+	if err := writer.Set(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := reader.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("Get returned %v when it should have returned %v", got, want)
+	}
+	if fraction := got.Fraction(); fraction != 0.75 {
+		t.Fatalf("Fraction returned %v when it should have returned 0.75", fraction)
+	}
+}
+
+func TestProgressSubscribe(t *testing.T) {
+	name := testProgressName("Subscribe")
+
+	writer, err := winprogress.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	reader, err := winprogress.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch := make(chan winprogress.Snapshot)
+	done := make(chan error, 1)
+	go func() {
+		done <- reader.Subscribe(ctx, ch)
+	}()
+
+	// The initial state is sent first.
+	if s := <-ch; s != (winprogress.Snapshot{}) {
+		t.Fatalf("Subscribe sent %v when it should have sent the initial state", s)
+	}
+
+	for i := uint64(1); i <= 3; i++ {
+		want := winprogress.Snapshot{Current: i, Total: 3}
+		if err := writer.Set(want); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case got := <-ch:
+			if got != want {
+				t.Fatalf("Subscribe sent %v when it should have sent %v", got, want)
+			}
+		case <-ctx.Done():
+			t.Fatalf("Subscribe did not send update %d", i)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Subscribe returned %v when it should have returned %v", err, context.Canceled)
+	}
+}
+
+func TestProgressStatusTruncated(t *testing.T) {
+	progress, err := winprogress.Create(testProgressName("StatusTruncated"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer progress.Close()
+
+	status := strings.Repeat("é", winprogress.MaxStatusLength)
+	if err := progress.Set(winprogress.Snapshot{Status: status}); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := progress.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := snapshot.Status
+	if len(got) > winprogress.MaxStatusLength || !strings.HasPrefix(status, got) {
+		t.Fatalf("The status was stored as %d bytes that are not a prefix of the original", len(got))
+	}
+}
+
+func TestProgressTornWrite(t *testing.T) {
+	name := testProgressName("TornWrite")
+
+	progress, err := winprogress.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer progress.Close()
+
+	// Leave the record marked as being written, as a writer that exited
+	// part way through an update would.
+	section, err := winshm.Open(name, winshm.Schema{Magic: "WOPROG", Version: winprogress.Version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer section.Close()
+	section.Bytes()[0] |= 1
+
+	if _, err := progress.Get(); !errors.Is(err, winprogress.ErrTornWrite) {
+		t.Fatalf("Get returned %v when it should have returned %v", err, winprogress.ErrTornWrite)
+	}
+
+	// The next update repairs the record.
+	want := winprogress.Snapshot{Current: 1, Total: 2}
+	if err := progress.Set(want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := progress.Get(); err != nil || got != want {
+		t.Fatalf("Get returned %v, %v after a repairing update when it should have returned %v", got, err, want)
+	}
+}
+
+func testProgressName(name string) string {
+	return "WinObj-WinProgress-Test-" + name
+}
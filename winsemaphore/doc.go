@@ -0,0 +1,15 @@
+//go:build windows
+
+// Package winsemaphore provides access to named semaphore objects on
+// Windows.
+//
+// A semaphore is a kernel object that maintains a count between zero and
+// a maximum. Acquiring the semaphore decrements its count, waiting while
+// the count is zero, and releasing it increments the count. Named
+// semaphores are commonly used to limit how many processes on a machine
+// perform an expensive operation at once.
+//
+// Unlike mutexes, semaphores are not owned by the thread that acquires
+// them, so they can be acquired and released from any goroutine, and even
+// from different processes.
+package winsemaphore
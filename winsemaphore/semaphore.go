@@ -0,0 +1,158 @@
+//go:build windows
+
+package winsemaphore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// ErrTooManyReleases is returned by Release when releasing the semaphore
+// would raise its count above its maximum.
+var ErrTooManyReleases = errors.New("winsemaphore: the release would exceed the maximum count of the semaphore")
+
+// Semaphore is a named or unnamed system semaphore.
+type Semaphore struct {
+	name   string
+	handle syscall.Handle
+}
+
+// Create returns a system semaphore with the given name, initial count and
+// maximum count. If name is empty, it returns an unnamed semaphore. If
+// name is not empty and a semaphore with the given name already exists,
+// it is opened and existed is true. The counts of an existing semaphore
+// are not changed, and opts are not applied to it.
+//
+// The name may be given as a plain string or as a winobj.Name. The
+// default prefix set by winobj.SetDefaultPrefix, if any, is applied to it.
+//
+// It is the caller's responsibility to close the semaphore.
+func Create[N winobj.ObjectName](name N, initial, max int, opts ...winobj.Option) (s *Semaphore, existed bool, err error) {
+	qualified := winobj.Qualify(name)
+	if max <= 0 || initial < 0 || initial > max {
+		return nil, false, fmt.Errorf("winsemaphore: invalid counts for %s: the initial count %d must be between zero and the maximum count %d, which must be positive", semaphoreDescription(qualified), initial, max)
+	}
+
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, false, fmt.Errorf("winsemaphore: failed to create %s: %w", semaphoreDescription(qualified), err)
+	}
+
+	handle, existed, err := synchapi.CreateSemaphore(qualified, int32(initial), int32(max), options.SyscallSecurityAttributes())
+	if err != nil {
+		return nil, false, fmt.Errorf("winsemaphore: failed to create %s: %w", semaphoreDescription(qualified), err)
+	}
+	return &Semaphore{name: qualified, handle: handle}, existed, nil
+}
+
+// Open opens an existing system semaphore with the given name.
+//
+// It is the caller's responsibility to close the semaphore.
+func Open[N winobj.ObjectName](name N) (*Semaphore, error) {
+	qualified := winobj.Qualify(name)
+	handle, err := synchapi.OpenSemaphore(qualified, windows.SYNCHRONIZE|synchapi.SemaphoreModifyState)
+	if err != nil {
+		return nil, fmt.Errorf("winsemaphore: failed to open %s: %w", semaphoreDescription(qualified), err)
+	}
+	return &Semaphore{name: qualified, handle: handle}, nil
+}
+
+// Name returns the name of the semaphore.
+//
+// If the semaphore is unnamed, it returns an empty string.
+func (s *Semaphore) Name() string {
+	return s.name
+}
+
+// Acquire decrements the count of the semaphore, waiting until it is
+// positive or ctx is cancelled.
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Prepare an event that will be signaled if ctx is cancelled, so that
+	// the wait can be interrupted.
+	cancelled, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return fmt.Errorf("winsemaphore: failed to create cancellation event: %w", err)
+	}
+	defer windows.CloseHandle(cancelled)
+
+	stop := context.AfterFunc(ctx, func() {
+		windows.SetEvent(cancelled)
+	})
+	defer stop()
+
+	handles := []windows.Handle{windows.Handle(s.handle), cancelled}
+	event, err := windows.WaitForMultipleObjects(handles, false, windows.INFINITE)
+	if err != nil {
+		return fmt.Errorf("winsemaphore: failed to wait for %s: %w", semaphoreDescription(s.name), err)
+	}
+
+	switch event {
+	case windows.WAIT_OBJECT_0:
+		return nil
+	case windows.WAIT_OBJECT_0 + 1:
+		return ctx.Err()
+	default:
+		return fmt.Errorf("winsemaphore: failed to wait for %s: unexpected wait result: %#x", semaphoreDescription(s.name), event)
+	}
+}
+
+// TryAcquire decrements the count of the semaphore if it is positive,
+// without waiting. It reports whether the count was decremented.
+func (s *Semaphore) TryAcquire() (bool, error) {
+	event, err := windows.WaitForSingleObject(windows.Handle(s.handle), 0)
+	if err != nil {
+		return false, fmt.Errorf("winsemaphore: failed to acquire %s: %w", semaphoreDescription(s.name), err)
+	}
+
+	switch event {
+	case windows.WAIT_OBJECT_0:
+		return true, nil
+	case synchapi.WaitTimeout:
+		return false, nil
+	default:
+		return false, fmt.Errorf("winsemaphore: failed to acquire %s: unexpected wait result: %#x", semaphoreDescription(s.name), event)
+	}
+}
+
+// Release increments the count of the semaphore by n and returns the
+// count that it had before. If that would raise the count above the
+// semaphore's maximum, the count is not changed and ErrTooManyReleases is
+// returned.
+func (s *Semaphore) Release(n int) (previous int, err error) {
+	count, err := synchapi.ReleaseSemaphore(s.handle, int32(n))
+	if err == windows.ERROR_TOO_MANY_POSTS {
+		return 0, ErrTooManyReleases
+	}
+	if err != nil {
+		return 0, fmt.Errorf("winsemaphore: failed to release %s: %w", semaphoreDescription(s.name), err)
+	}
+	return int(count), nil
+}
+
+// Close closes the semaphore's handle. The semaphore is destroyed once
+// every handle to it has been closed.
+func (s *Semaphore) Close() error {
+	if s.handle == 0 {
+		return nil
+	}
+	err := syscall.CloseHandle(s.handle)
+	s.handle = 0
+	return err
+}
+
+func semaphoreDescription(name string) string {
+	if name == "" {
+		return "an unnamed windows semaphore"
+	}
+	return fmt.Sprintf("the windows semaphore named \"%s\"", name)
+}
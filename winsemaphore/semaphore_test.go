@@ -0,0 +1,89 @@
+//go:build windows
+
+package winsemaphore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winsemaphore"
+)
+
+func TestSemaphoreShared(t *testing.T) {
+	name := testSemaphoreName("Shared")
+
+	s1, existed, err := winsemaphore.Create(name, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s1.Close()
+	if existed {
+		t.Fatal("The semaphore was reported as existing before it was created")
+	}
+
+	s2, err := winsemaphore.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s2.Close()
+
+	if ok, err := s2.TryAcquire(); err != nil || !ok {
+		t.Fatalf("TryAcquire returned %t (%v) when the semaphore had a count of 1", ok, err)
+	}
+	if ok, err := s1.TryAcquire(); err != nil || ok {
+		t.Fatalf("TryAcquire returned %t (%v) when the semaphore had a count of 0", ok, err)
+	}
+
+	previous, err := s1.Release(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previous != 0 {
+		t.Fatalf("Release returned a previous count of %d when it should have been 0", previous)
+	}
+
+	if _, err := s2.Release(1); !errors.Is(err, winsemaphore.ErrTooManyReleases) {
+		t.Fatalf("Release returned %v when it should have returned %v", err, winsemaphore.ErrTooManyReleases)
+	}
+}
+
+func TestSemaphoreAcquireCancelled(t *testing.T) {
+	s, _, err := winsemaphore.Create("", 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := s.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestSemaphoreAcquireReleased(t *testing.T) {
+	s, _, err := winsemaphore.Create("", 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		s.Release(1)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.Acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testSemaphoreName(name string) string {
+	return "WinObj-WinSemaphore-Test-" + name
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+// Package winsession reports on terminal services sessions on Windows.
+//
+// Named objects created without the "Global\" prefix live in the local
+// namespace of a particular session. When that session logs off, its
+// namespace is torn down and the objects within it become unreachable.
+// This package lets programs that hold session-scoped objects find out
+// when the session has ended, so that they can release or recreate those
+// objects cleanly.
+package winsession
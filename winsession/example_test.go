@@ -0,0 +1,35 @@
+//go:build windows
+
+package winsession_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"github.com/gentlemanautomaton/winobj/winsession"
+)
+
+func ExampleWaitForLogoff() {
+	const name = `Local\SessionScopedExample`
+
+	mutex, err := winmutex.New(name)
+	if err != nil {
+		fmt.Printf("Failed to open the %s system mutex: %v\n", name, err)
+		os.Exit(1)
+	}
+
+	session, _, err := winsession.Of(name)
+	if err != nil {
+		fmt.Printf("Failed to determine the session of %s: %v\n", name, err)
+		os.Exit(1)
+	}
+
+	// Release the mutex when its session ends.
+	go func() {
+		if err := winsession.WaitForLogoff(context.Background(), session); err == nil {
+			mutex.Close()
+		}
+	}()
+}
@@ -0,0 +1,178 @@
+//go:build windows
+
+package winsession
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// pollInterval is how often WaitForLogoff checks whether a session still
+// exists.
+const pollInterval = time.Second
+
+// State is the connection state of a session.
+type State uint32
+
+// Session connection states.
+const (
+	Active       State = windows.WTSActive
+	Connected    State = windows.WTSConnected
+	ConnectQuery State = windows.WTSConnectQuery
+	Shadow       State = windows.WTSShadow
+	Disconnected State = windows.WTSDisconnected
+	Idle         State = windows.WTSIdle
+	Listen       State = windows.WTSListen
+	Reset        State = windows.WTSReset
+	Down         State = windows.WTSDown
+	Init         State = windows.WTSInit
+)
+
+// String returns a string representation of the state.
+func (s State) String() string {
+	switch s {
+	case Active:
+		return "active"
+	case Connected:
+		return "connected"
+	case ConnectQuery:
+		return "connect query"
+	case Shadow:
+		return "shadow"
+	case Disconnected:
+		return "disconnected"
+	case Idle:
+		return "idle"
+	case Listen:
+		return "listen"
+	case Reset:
+		return "reset"
+	case Down:
+		return "down"
+	case Init:
+		return "init"
+	default:
+		return "unknown"
+	}
+}
+
+// Session describes a terminal services session.
+type Session struct {
+	ID      uint32
+	Station string // Window station name, such as "Console" or "RDP-Tcp#0"
+	State   State
+}
+
+// List returns the sessions on the local machine.
+func List() ([]Session, error) {
+	var (
+		info  *windows.WTS_SESSION_INFO
+		count uint32
+	)
+	if err := windows.WTSEnumerateSessions(0, 0, 1, &info, &count); err != nil {
+		return nil, fmt.Errorf("winsession: failed to enumerate sessions: %w", err)
+	}
+	defer windows.WTSFreeMemory(uintptr(unsafe.Pointer(info)))
+
+	sessions := make([]Session, 0, count)
+	for _, entry := range unsafe.Slice(info, count) {
+		sessions = append(sessions, Session{
+			ID:      entry.SessionID,
+			Station: windows.UTF16PtrToString(entry.WindowStationName),
+			State:   State(entry.State),
+		})
+	}
+	return sessions, nil
+}
+
+// Lookup returns the session with the given ID. It returns false if no
+// such session exists.
+func Lookup(id uint32) (Session, bool, error) {
+	sessions, err := List()
+	if err != nil {
+		return Session{}, false, err
+	}
+	for _, session := range sessions {
+		if session.ID == id {
+			return session, true, nil
+		}
+	}
+	return Session{}, false, nil
+}
+
+// Current returns the ID of the session that the calling process belongs
+// to.
+func Current() (uint32, error) {
+	var id uint32
+	if err := windows.ProcessIdToSessionId(windows.GetCurrentProcessId(), &id); err != nil {
+		return 0, fmt.Errorf("winsession: failed to determine the current session: %w", err)
+	}
+	return id, nil
+}
+
+// Of returns the ID of the session whose local namespace holds the named
+// object with the given name. It returns false if the name is in the
+// global namespace.
+//
+// Names with the "Session\<n>\" prefix belong to session n. Names with the
+// "Local\" prefix or no prefix belong to the current session.
+func Of(name string) (id uint32, ok bool, err error) {
+	switch {
+	case hasPrefixFold(name, `Global\`):
+		return 0, false, nil
+	case hasPrefixFold(name, `Session\`):
+		rest := name[len(`Session\`):]
+		end := strings.IndexByte(rest, '\\')
+		if end < 0 {
+			return 0, false, fmt.Errorf("winsession: the name \"%s\" is missing a backslash after its session ID", name)
+		}
+		n, err := strconv.ParseUint(rest[:end], 10, 32)
+		if err != nil {
+			return 0, false, fmt.Errorf("winsession: the name \"%s\" has an invalid session ID: %w", name, err)
+		}
+		return uint32(n), true, nil
+	default:
+		id, err := Current()
+		if err != nil {
+			return 0, false, err
+		}
+		return id, true, nil
+	}
+}
+
+// WaitForLogoff blocks until the session with the given ID has ended or
+// ctx is cancelled. It returns nil if the session ended.
+//
+// Sessions are polled periodically, so there may be a short delay between
+// the end of the session and the return of WaitForLogoff.
+func WaitForLogoff(ctx context.Context, id uint32) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		session, ok, err := Lookup(id)
+		if err != nil {
+			return err
+		}
+		if !ok || session.State == Down {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// hasPrefixFold reports whether s begins with prefix, ignoring case.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
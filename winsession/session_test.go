@@ -0,0 +1,60 @@
+//go:build windows
+
+package winsession_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/winsession"
+)
+
+func TestCurrentSessionIsListed(t *testing.T) {
+	id, err := winsession.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := winsession.Lookup(id); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatalf("The current session (%d) was not listed", id)
+	}
+}
+
+func TestWaitForLogoffMissingSession(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := winsession.WaitForLogoff(ctx, 0xFFFFFF00); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOf(t *testing.T) {
+	current, err := winsession.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		id   uint32
+		ok   bool
+	}{
+		{`Global\Example`, 0, false},
+		{`Session\3\Example`, 3, true},
+		{`Local\Example`, current, true},
+		{`Example`, current, true},
+	}
+	for _, test := range tests {
+		id, ok, err := winsession.Of(test.name)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if id != test.id || ok != test.ok {
+			t.Errorf("%s: Of returned (%d, %t) when it should have returned (%d, %t)", test.name, id, ok, test.id, test.ok)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+// Package winshm provides access to named shared memory sections on
+// Windows.
+//
+// A section is a kernel object that maps the same memory into the address
+// space of every process that opens it. Sections created by this package
+// are backed by the paging file, so they exist only as long as a process
+// holds them open.
+//
+// Every section begins with a header that records the schema of its
+// contents, the process that created it and when. The header is checked
+// whenever the section is opened, so that processes built against
+// different versions of a layout fail cleanly rather than corrupting each
+// other's data.
+//
+// Sections provide no synchronization of their own. Processes that share
+// a section should coordinate access to it, such as with a named mutex.
+package winshm
@@ -0,0 +1,144 @@
+//go:build windows
+
+package winshm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// HeaderSize is the number of bytes at the start of every section that
+// are reserved for its header. The memory returned by Section.Bytes
+// begins immediately after it.
+const HeaderSize = 64
+
+// MaxMagicLength is the maximum length of a schema's magic string.
+const MaxMagicLength = 8
+
+// initTimeout is the amount of time that a process opening a section will
+// wait for its creator to finish writing the header.
+const initTimeout = time.Second
+
+// Layout of the section header.
+const (
+	offsetMagic   = 0
+	offsetVersion = 8
+	offsetState   = 12
+	offsetCreator = 16
+	offsetCreated = 24
+)
+
+// Header states.
+const (
+	stateUninitialized = 0
+	stateReady         = 1
+)
+
+// Schema identifies the layout of the data stored in a section. Processes
+// that share a section must agree on its schema.
+type Schema struct {
+	Magic   string // Identifies the kind of data, up to MaxMagicLength bytes
+	Version uint32 // Identifies the revision of its layout
+}
+
+// String returns a string representation of the schema.
+func (s Schema) String() string {
+	return fmt.Sprintf("%s v%d", s.Magic, s.Version)
+}
+
+// validate returns an error if s cannot be stored in a header.
+func (s Schema) validate() error {
+	if s.Magic == "" {
+		return errors.New("winshm: the schema magic is empty")
+	}
+	if len(s.Magic) > MaxMagicLength {
+		return fmt.Errorf("winshm: the schema magic \"%s\" exceeds %d bytes", s.Magic, MaxMagicLength)
+	}
+	return nil
+}
+
+// Header describes a section. It is written when the section is created.
+type Header struct {
+	Schema  Schema
+	Creator uint32    // The ID of the process that created the section
+	Created time.Time // The time at which the section was created
+}
+
+// IncompatibleError is returned when a section's header does not match
+// the schema expected by the caller. Mixed-version deployments produce
+// this error instead of corrupting each other's data.
+type IncompatibleError struct {
+	Section string
+	Want    Schema
+	Got     Header // Empty if the header was never written
+}
+
+// Error returns a description of the incompatibility.
+func (e IncompatibleError) Error() string {
+	if e.Got.Schema.Magic == "" {
+		return fmt.Sprintf("winshm: the %s section does not have a valid header (wanted %s)", e.Section, e.Want)
+	}
+	return fmt.Sprintf("winshm: the %s section has schema %s (wanted %s), and was created by process %d at %s", e.Section, e.Got.Schema, e.Want, e.Got.Creator, e.Got.Created.Format(time.RFC3339))
+}
+
+// writeHeader writes the header of a newly created section.
+func (s *Section) writeHeader(schema Schema) {
+	le := binary.LittleEndian
+	copy(s.view[offsetMagic:offsetMagic+MaxMagicLength], schema.Magic)
+	le.PutUint32(s.view[offsetVersion:], schema.Version)
+	le.PutUint32(s.view[offsetCreator:], windows.GetCurrentProcessId())
+	le.PutUint64(s.view[offsetCreated:], uint64(time.Now().UnixNano()))
+
+	// Publish the header last, so that other processes do not read it
+	// before it is complete.
+	atomic.StoreUint32(s.state(), stateReady)
+}
+
+// readHeader waits for the section's header to be written and then
+// returns it. It returns false if the header was not written in time.
+func (s *Section) readHeader() (Header, bool) {
+	deadline := time.Now().Add(initTimeout)
+	for atomic.LoadUint32(s.state()) != stateReady {
+		if time.Now().After(deadline) {
+			return Header{}, false
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	le := binary.LittleEndian
+	magic := s.view[offsetMagic : offsetMagic+MaxMagicLength]
+	for len(magic) > 0 && magic[len(magic)-1] == 0 {
+		magic = magic[:len(magic)-1]
+	}
+
+	return Header{
+		Schema: Schema{
+			Magic:   string(magic),
+			Version: le.Uint32(s.view[offsetVersion:]),
+		},
+		Creator: le.Uint32(s.view[offsetCreator:]),
+		Created: time.Unix(0, int64(le.Uint64(s.view[offsetCreated:]))),
+	}, true
+}
+
+// checkHeader validates the header of an existing section against the
+// schema expected by the caller.
+func (s *Section) checkHeader(schema Schema) error {
+	header, ok := s.readHeader()
+	if !ok || header.Schema != schema {
+		return IncompatibleError{Section: s.name, Want: schema, Got: header}
+	}
+	return nil
+}
+
+// state returns a pointer to the state of the section's header, which is
+// accessed atomically.
+func (s *Section) state() *uint32 {
+	return (*uint32)(unsafe.Pointer(&s.view[offsetState]))
+}
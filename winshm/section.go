@@ -0,0 +1,248 @@
+//go:build windows
+
+package winshm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/memoryapi"
+	"golang.org/x/sys/windows"
+)
+
+// ReadOnlyError is returned when an attempt is made to modify a section
+// that was opened with OpenReadOnly.
+type ReadOnlyError struct {
+	Section string
+}
+
+// Error returns a description of the failed modification.
+func (e ReadOnlyError) Error() string {
+	return fmt.Sprintf("winshm: the %s section was opened for reading only", e.Section)
+}
+
+// Section is a mapped view of a named shared memory section.
+type Section struct {
+	name    string
+	handle  windows.Handle
+	address uintptr
+	view    []byte // The entire view, including the header
+	data    []byte // The view following the header
+	header  Header
+	write   bool // True if the view was mapped for writing
+}
+
+// Create creates a shared memory section with the given name, schema and
+// size in bytes, or opens it if it already exists, and maps a view of it
+// into memory. It reports whether the section already existed.
+//
+// A header describing the schema, the creating process and the time of
+// creation is written to the start of a newly created section. If the
+// section already existed, its header is validated against schema instead,
+// and an IncompatibleError is returned if they do not match.
+//
+// The memory of a newly created section is zeroed. If the section already
+// existed, its existing size is retained and size and opts are ignored.
+//
+// It is the caller's responsibility to close the section.
+func Create(name string, schema Schema, size int, opts ...winobj.Option) (s *Section, existed bool, err error) {
+	name = winobj.Qualify(name)
+
+	if size <= 0 {
+		return nil, false, fmt.Errorf("winshm: the size of the %s section must be positive", name)
+	}
+	if err := schema.validate(); err != nil {
+		return nil, false, err
+	}
+	size += HeaderSize
+
+	utf16Name, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, false, fmt.Errorf("winshm: invalid section name \"%s\": %w", name, err)
+	}
+
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, false, fmt.Errorf("winshm: failed to create the %s section: %w", name, err)
+	}
+
+	h, err := windows.CreateFileMapping(windows.InvalidHandle, options.SecurityAttributes(), windows.PAGE_READWRITE, uint32(uint64(size)>>32), uint32(size), utf16Name)
+	switch err {
+	case nil:
+	case windows.ERROR_ALREADY_EXISTS:
+		existed = true
+	default:
+		return nil, false, fmt.Errorf("winshm: failed to create the %s section: %w", name, err)
+	}
+
+	s, err = mapSection(name, h, windows.FILE_MAP_READ|windows.FILE_MAP_WRITE)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !existed {
+		s.writeHeader(schema)
+	} else if err := s.checkHeader(schema); err != nil {
+		s.Close()
+		return nil, false, err
+	}
+	s.header, _ = s.readHeader()
+
+	return s, existed, nil
+}
+
+// Open opens an existing shared memory section with the given name and
+// maps a view of it into memory for reading and writing. If the section's
+// header does not match schema, it returns an IncompatibleError.
+//
+// It is the caller's responsibility to close the section.
+func Open(name string, schema Schema) (*Section, error) {
+	return open(name, schema, windows.FILE_MAP_READ|windows.FILE_MAP_WRITE)
+}
+
+// OpenReadOnly opens an existing shared memory section with the given name
+// and maps a view of it into memory for reading only. If the section's
+// header does not match schema, it returns an IncompatibleError.
+//
+// Only read access to the section is requested, so OpenReadOnly succeeds
+// for callers that the section's security descriptor does not permit to
+// write to it. The WriteAt method of the returned section fails with a
+// ReadOnlyError, and the memory returned by its Bytes method must not be
+// modified, as doing so causes an access violation.
+//
+// It is the caller's responsibility to close the section.
+func OpenReadOnly(name string, schema Schema) (*Section, error) {
+	return open(name, schema, windows.FILE_MAP_READ)
+}
+
+// open opens an existing shared memory section with the given name and
+// maps a view of it with the given access.
+func open(name string, schema Schema, access uint32) (*Section, error) {
+	name = winobj.Qualify(name)
+
+	h, err := memoryapi.OpenFileMapping(name, access, false)
+	if err != nil {
+		return nil, fmt.Errorf("winshm: failed to open the %s section: %w", name, err)
+	}
+
+	s, err := mapSection(name, windows.Handle(h), access)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkHeader(schema); err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.header, _ = s.readHeader()
+
+	return s, nil
+}
+
+// mapSection maps a view of the entire section with the given handle. If
+// it fails, the handle is closed.
+func mapSection(name string, h windows.Handle, access uint32) (*Section, error) {
+	address, err := windows.MapViewOfFile(h, access, 0, 0, 0)
+	if err != nil {
+		windows.CloseHandle(h)
+		return nil, fmt.Errorf("winshm: failed to map the %s section: %w", name, err)
+	}
+
+	// Determine the size of the view, which is the size of the section
+	// rounded up to a whole number of pages.
+	var info windows.MemoryBasicInformation
+	if err := windows.VirtualQuery(address, &info, unsafe.Sizeof(info)); err != nil {
+		windows.UnmapViewOfFile(address)
+		windows.CloseHandle(h)
+		return nil, fmt.Errorf("winshm: failed to determine the size of the %s section: %w", name, err)
+	}
+
+	view := unsafe.Slice(*(**byte)(unsafe.Pointer(&address)), info.RegionSize)
+	return &Section{
+		name:    name,
+		handle:  h,
+		address: address,
+		view:    view,
+		data:    view[HeaderSize:],
+		write:   access&windows.FILE_MAP_WRITE != 0,
+	}, nil
+}
+
+// Name returns the name of the section.
+func (s *Section) Name() string {
+	return s.name
+}
+
+// Header returns the header of the section.
+func (s *Section) Header() Header {
+	return s.header
+}
+
+// Bytes returns the mapped memory of the section that follows its header.
+// Its length is at least the size requested when the section was created.
+//
+// The returned slice is shared with other processes and must not be used
+// after the section is closed. If the section is read-only, the slice must
+// not be modified.
+func (s *Section) Bytes() []byte {
+	return s.data
+}
+
+// ReadOnly reports whether the section was opened for reading only.
+func (s *Section) ReadOnly() bool {
+	return !s.write
+}
+
+// ReadAt copies the memory of the section that begins at offset off into
+// p. Offsets are relative to the memory returned by Bytes. It implements
+// io.ReaderAt.
+func (s *Section) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("winshm: negative offset %d in the %s section", off, s.name)
+	}
+	if off >= int64(len(s.data)) {
+		return 0, io.EOF
+	}
+	n = copy(p, s.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// WriteAt copies p into the memory of the section that begins at offset
+// off. Offsets are relative to the memory returned by Bytes. It implements
+// io.WriterAt.
+//
+// If the section is read-only, it returns a ReadOnlyError.
+func (s *Section) WriteAt(p []byte, off int64) (n int, err error) {
+	if !s.write {
+		return 0, ReadOnlyError{Section: s.name}
+	}
+	if off < 0 {
+		return 0, fmt.Errorf("winshm: negative offset %d in the %s section", off, s.name)
+	}
+	if off > int64(len(s.data)) {
+		return 0, io.ErrShortWrite
+	}
+	n = copy(s.data[off:], p)
+	if n < len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+// Close unmaps the view of the section and closes its handle. The section
+// is destroyed once every process has closed it.
+func (s *Section) Close() error {
+	if s.handle == 0 {
+		return nil
+	}
+	err1 := windows.UnmapViewOfFile(s.address)
+	err2 := windows.CloseHandle(s.handle)
+	s.handle, s.address, s.view, s.data = 0, 0, nil, nil
+	return errors.Join(err1, err2)
+}
@@ -0,0 +1,141 @@
+//go:build windows
+
+package winshm_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/gentlemanautomaton/winobj/winshm"
+)
+
+func TestSectionShared(t *testing.T) {
+	name := testSectionName("Shared")
+
+	section1, existed, err := winshm.Create(name, testSchema, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer section1.Close()
+	if existed {
+		t.Fatal("The section was reported as existing before it was created")
+	}
+	if size := len(section1.Bytes()); size < 100 {
+		t.Fatalf("The section is %d bytes when it should be at least 100", size)
+	}
+
+	section2, err := winshm.Open(name, testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer section2.Close()
+
+	copy(section1.Bytes(), "hello")
+	if got := string(section2.Bytes()[:5]); got != "hello" {
+		t.Fatalf("The second view read %q when it should have read %q", got, "hello")
+	}
+}
+
+func TestSectionOpenMissing(t *testing.T) {
+	if _, err := winshm.Open(testSectionName("Missing"), testSchema); err == nil {
+		t.Fatal("A section was opened when it should not exist")
+	}
+}
+
+func TestSectionHeader(t *testing.T) {
+	name := testSectionName("Header")
+
+	section1, _, err := winshm.Create(name, testSchema, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer section1.Close()
+
+	section2, err := winshm.Open(name, testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer section2.Close()
+
+	header := section2.Header()
+	if header.Schema != testSchema {
+		t.Fatalf("The section has schema %s when it should have %s", header.Schema, testSchema)
+	}
+	if pid := uint32(os.Getpid()); header.Creator != pid {
+		t.Fatalf("The section was created by process %d when it should have been created by %d", header.Creator, pid)
+	}
+	if header.Created.IsZero() {
+		t.Fatal("The section does not have a creation time")
+	}
+}
+
+func TestSectionIncompatible(t *testing.T) {
+	name := testSectionName("Incompatible")
+
+	section, _, err := winshm.Create(name, testSchema, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer section.Close()
+
+	newer := winshm.Schema{Magic: testSchema.Magic, Version: testSchema.Version + 1}
+
+	var incompatible winshm.IncompatibleError
+	if _, err := winshm.Open(name, newer); !errors.As(err, &incompatible) {
+		t.Fatalf("Open returned %v when it should have returned an incompatible error", err)
+	}
+	if incompatible.Got.Schema != testSchema {
+		t.Fatalf("The incompatible error reported schema %s when it should have reported %s", incompatible.Got.Schema, testSchema)
+	}
+	if _, _, err := winshm.Create(name, newer, 100); !errors.As(err, &incompatible) {
+		t.Fatalf("Create returned %v when it should have returned an incompatible error", err)
+	}
+}
+
+func TestSectionReadOnly(t *testing.T) {
+	name := testSectionName("ReadOnly")
+
+	section1, _, err := winshm.Create(name, testSchema, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer section1.Close()
+
+	section2, err := winshm.OpenReadOnly(name, testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer section2.Close()
+
+	if section1.ReadOnly() {
+		t.Fatal("The created section was reported as read-only")
+	}
+	if !section2.ReadOnly() {
+		t.Fatal("The read-only section was not reported as read-only")
+	}
+
+	if _, err := section1.WriteAt([]byte("hello"), 10); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 5)
+	if _, err := section2.ReadAt(buf, 10); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf); got != "hello" {
+		t.Fatalf("The read-only view read %q when it should have read %q", got, "hello")
+	}
+
+	_, err = section2.WriteAt([]byte("world"), 10)
+	var readOnly winshm.ReadOnlyError
+	if !errors.As(err, &readOnly) {
+		t.Fatalf("WriteAt returned %v when it should have returned a ReadOnlyError", err)
+	}
+}
+
+var testSchema = winshm.Schema{Magic: "WOTEST", Version: 1}
+
+func testSectionName(name string) string {
+	return "WinObj-WinSHM-Test-" + name
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+// Package wintimer provides access to waitable timers on Windows, along
+// with a scheduler that runs recurring work on top of them.
+//
+// Unlike the timers provided by the time package, a waitable timer can
+// wake the system from suspend when it becomes due, which makes it
+// suitable for scheduled maintenance that must run even while a machine
+// is asleep.
+package wintimer
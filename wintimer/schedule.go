@@ -0,0 +1,221 @@
+//go:build windows
+
+package wintimer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule describes a recurring series of times.
+type Schedule interface {
+	// Next returns the first time in the schedule that is after t. If the
+	// schedule has no more times, it returns the zero time.
+	Next(t time.Time) time.Time
+}
+
+// Every returns a schedule that recurs at a fixed interval. Times are
+// aligned to multiples of the interval since the zero time, so a schedule
+// of every 15 minutes runs on the hour and at quarter hours. It panics if
+// interval is not positive.
+func Every(interval time.Duration) Schedule {
+	if interval <= 0 {
+		panic("wintimer: Every() called with a non-positive interval")
+	}
+	return every(interval)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Duration(e)).Add(time.Duration(e))
+	if !next.After(t) {
+		next = next.Add(time.Duration(e))
+	}
+	return next
+}
+
+// cronSearchLimit bounds the search for the next time in a cron schedule,
+// so that schedules that can never match, such as February 30th, end
+// instead of searching forever.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// ParseCron parses a cron specification and returns the schedule that it
+// describes. Times are interpreted in the location of the time passed to
+// Next.
+//
+// A specification has five space-separated fields: minute (0-59), hour
+// (0-23), day of month (1-31), month (1-12) and day of week (0-6, where
+// both 0 and 7 are Sunday). Each field is a comma-separated list of
+// values, ranges such as "1-5", or "*", any of which may be followed by a
+// step such as "/15". As in traditional cron, when both the day of month
+// and day of week are restricted, a day matches if either field does.
+//
+// The following shorthands are also accepted: @yearly (or @annually),
+// @monthly, @weekly, @daily (or @midnight), @hourly, and "@every <d>",
+// where <d> is a duration accepted by time.ParseDuration.
+func ParseCron(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("wintimer: invalid interval in cron specification \"%s\": %w", spec, err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("wintimer: the interval in cron specification \"%s\" must be positive", spec)
+		}
+		return Every(interval), nil
+	}
+
+	switch spec {
+	case "@yearly", "@annually":
+		spec = "0 0 1 1 *"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@hourly":
+		spec = "0 * * * *"
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("wintimer: the cron specification \"%s\" does not have 5 fields", spec)
+	}
+
+	var (
+		c   cron
+		err error
+	)
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("wintimer: invalid minute in cron specification \"%s\": %w", spec, err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("wintimer: invalid hour in cron specification \"%s\": %w", spec, err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("wintimer: invalid day of month in cron specification \"%s\": %w", spec, err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("wintimer: invalid month in cron specification \"%s\": %w", spec, err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("wintimer: invalid day of week in cron specification \"%s\": %w", spec, err)
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 << 0 // Both 0 and 7 are Sunday
+	}
+	c.domAny = fields[2] == "*"
+	c.dowAny = fields[4] == "*"
+
+	return c, nil
+}
+
+// cron is a parsed cron specification. Each field is a bit set of the
+// values that it matches.
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+func (c cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(cronSearchLimit)
+
+	// Start at the beginning of the next minute.
+	next := t.Truncate(time.Minute).Add(time.Minute)
+
+	for next.Before(limit) {
+		if c.month&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.matchDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(next.Hour())) == 0 {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+
+	return time.Time{}
+}
+
+// matchDay reports whether the day of t matches the day of month and day
+// of week fields.
+func (c cron) matchDay(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// parseCronField parses a single cron field with values between min and
+// max inclusive, and returns the set of values it matches.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step \"%s\"", stepPart)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = min, max
+		case strings.Contains(rangePart, "-"):
+			loPart, hiPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(loPart); err != nil {
+				return 0, fmt.Errorf("invalid value \"%s\"", loPart)
+			}
+			if hi, err = strconv.Atoi(hiPart); err != nil {
+				return 0, fmt.Errorf("invalid value \"%s\"", hiPart)
+			}
+		default:
+			var err error
+			if lo, err = strconv.Atoi(rangePart); err != nil {
+				return 0, fmt.Errorf("invalid value \"%s\"", rangePart)
+			}
+			hi = lo
+			if hasStep {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("the range %d-%d is outside of %d-%d", lo, hi, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
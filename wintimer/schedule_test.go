@@ -0,0 +1,89 @@
+//go:build windows
+
+package wintimer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/wintimer"
+)
+
+func TestEvery(t *testing.T) {
+	schedule := wintimer.Every(15 * time.Minute)
+
+	start := time.Date(2024, time.March, 5, 10, 7, 30, 0, time.UTC)
+	want := time.Date(2024, time.March, 5, 10, 15, 0, 0, time.UTC)
+	if next := schedule.Next(start); !next.Equal(want) {
+		t.Fatalf("Next(%s): got %s, want %s", start, next, want)
+	}
+	want2 := want.Add(15 * time.Minute)
+	if next := schedule.Next(want); !next.Equal(want2) {
+		t.Fatalf("Next(%s): got %s, want %s", want, next, want2)
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	// Tuesday, March 5th, 2024
+	start := time.Date(2024, time.March, 5, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		Spec string
+		Want time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 5, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.March, 5, 10, 15, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, time.March, 6, 2, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * 1-5", time.Date(2024, time.March, 5, 11, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 4", time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.March, 5, 11, 0, 0, 0, time.UTC)},
+		{"@every 1h", time.Date(2024, time.March, 5, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		schedule, err := wintimer.ParseCron(test.Spec)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", test.Spec, err)
+			continue
+		}
+		if next := schedule.Next(start); !next.Equal(test.Want) {
+			t.Errorf("ParseCron(%q).Next(%s): got %s, want %s", test.Spec, start, next, test.Want)
+		}
+	}
+}
+
+func TestParseCronNever(t *testing.T) {
+	schedule, err := wintimer.ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := schedule.Next(time.Now()); !next.IsZero() {
+		t.Fatalf("A schedule for February 30th returned %s", next)
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"@every",
+		"@every -1m",
+	}
+
+	for _, spec := range tests {
+		if _, err := wintimer.ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) succeeded when it should have failed", spec)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+//go:build windows
+
+package wintimer
+
+import (
+	"context"
+	"time"
+)
+
+// Run invokes fn at each time in the schedule until ctx is cancelled or
+// the schedule has no more times. It returns the reason it stopped, which
+// is nil if the schedule was exhausted.
+//
+// Each occurrence is scheduled by arming a waitable timer for it. If
+// resume is true, the system is woken from suspend for each occurrence.
+// If an occurrence is missed because the system was asleep and could not
+// be woken, fn is called once when it resumes, rather than once for each
+// missed occurrence.
+//
+// fn is called synchronously with the scheduled time of the occurrence,
+// so occurrences that fall due while fn is running are skipped.
+func Run(ctx context.Context, schedule Schedule, resume bool, fn func(scheduled time.Time)) error {
+	timer, err := New("")
+	if err != nil {
+		return err
+	}
+	defer timer.Close()
+
+	for {
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			return nil
+		}
+
+		if err := timer.Set(next, resume); err != nil {
+			return err
+		}
+		if err := timer.Wait(ctx); err != nil {
+			return err
+		}
+
+		fn(next)
+	}
+}
+
+// Tick sends the scheduled time of each occurrence in the schedule to ch,
+// until ctx is cancelled or the schedule has no more times. It returns the
+// reason it stopped, which is nil if the schedule was exhausted.
+//
+// Tick schedules occurrences in the same way as Run. It blocks while
+// sending to ch, so occurrences that fall due while a receiver is busy
+// are skipped.
+func Tick(ctx context.Context, schedule Schedule, resume bool, ch chan<- time.Time) error {
+	err := Run(ctx, schedule, resume, func(scheduled time.Time) {
+		select {
+		case ch <- scheduled:
+		case <-ctx.Done():
+		}
+	})
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package wintimer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/wintimer"
+)
+
+func TestTick(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan time.Time)
+	done := make(chan error, 1)
+	go func() {
+		done <- wintimer.Tick(ctx, wintimer.Every(50*time.Millisecond), false, ch)
+	}()
+
+	var previous time.Time
+	for range 3 {
+		select {
+		case scheduled := <-ch:
+			if !scheduled.After(previous) {
+				t.Fatalf("The scheduled time %s did not follow %s", scheduled, previous)
+			}
+			previous = scheduled
+		case <-time.After(5 * time.Second):
+			t.Fatal("No tick was received")
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Tick returned %v when it should have returned %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,157 @@
+//go:build windows
+
+package wintimer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"syscall"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// Timer is a waitable timer. It is an auto-reset timer: each time it
+// becomes due, it releases a single call to Wait.
+type Timer struct {
+	name   string
+	handle syscall.Handle
+}
+
+// New returns a waitable timer with the given name. If name is empty, it
+// returns an unnamed timer. If name is not empty and a timer with the
+// given name does not already exist, it is created and opts are applied to
+// it.
+//
+// It is the caller's responsibility to close the timer when finished with
+// it.
+func New(name string, opts ...winobj.Option) (*Timer, error) {
+	name = winobj.Qualify(name)
+
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("wintimer: failed to create %s: %w", timerDescription(name), err)
+	}
+
+	handle, _, err := synchapi.CreateWaitableTimerEx(name, options.SyscallSecurityAttributes(), 0, synchapi.TimerAllAccess)
+	if err != nil {
+		return nil, fmt.Errorf("wintimer: failed to create %s: %w", timerDescription(name), err)
+	}
+	return &Timer{name: name, handle: handle}, nil
+}
+
+// Open opens an existing waitable timer with the given name.
+//
+// It is the caller's responsibility to close the timer when finished with
+// it.
+func Open(name string) (*Timer, error) {
+	name = winobj.Qualify(name)
+
+	handle, err := synchapi.OpenWaitableTimer(name, synchapi.TimerAllAccess)
+	if err != nil {
+		return nil, fmt.Errorf("wintimer: failed to open %s: %w", timerDescription(name), err)
+	}
+	return &Timer{name: name, handle: handle}, nil
+}
+
+// Name returns the name of the timer.
+//
+// If the timer is unnamed, it returns an empty string.
+func (t *Timer) Name() string {
+	return t.name
+}
+
+// Set arms the timer so that it becomes due at the given time. If resume
+// is true, the system will be woken from suspend when the timer becomes
+// due, provided that the system and its power settings support it.
+//
+// Setting a timer that is already armed replaces its due time.
+func (t *Timer) Set(due time.Time, resume bool) error {
+	return t.set(due, 0, resume)
+}
+
+// SetPeriodic arms the timer so that it becomes due at the given time, and
+// then again after every period until it is cancelled. The period is
+// rounded down to a whole number of milliseconds.
+//
+// Setting a timer that is already armed replaces its due time and period.
+func (t *Timer) SetPeriodic(due time.Time, period time.Duration, resume bool) error {
+	ms := period.Milliseconds()
+	if ms <= 0 || ms > math.MaxInt32 {
+		return fmt.Errorf("wintimer: invalid period for %s: %s", timerDescription(t.name), period)
+	}
+	return t.set(due, int32(ms), resume)
+}
+
+func (t *Timer) set(due time.Time, period int32, resume bool) error {
+	ft := windows.NsecToFiletime(due.UnixNano())
+	dueTime := int64(ft.HighDateTime)<<32 | int64(ft.LowDateTime)
+	if err := synchapi.SetWaitableTimer(t.handle, dueTime, period, resume); err != nil {
+		return fmt.Errorf("wintimer: failed to set %s: %w", timerDescription(t.name), err)
+	}
+	return nil
+}
+
+// Cancel disarms the timer. It does not release goroutines that are
+// waiting for it.
+func (t *Timer) Cancel() error {
+	if err := synchapi.CancelWaitableTimer(t.handle); err != nil {
+		return fmt.Errorf("wintimer: failed to cancel %s: %w", timerDescription(t.name), err)
+	}
+	return nil
+}
+
+// Wait blocks until the timer becomes due or ctx is cancelled.
+func (t *Timer) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Prepare an event that will be signaled if ctx is cancelled, so that
+	// the wait can be interrupted.
+	cancelled, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return fmt.Errorf("wintimer: failed to create cancellation event: %w", err)
+	}
+	defer windows.CloseHandle(cancelled)
+
+	stop := context.AfterFunc(ctx, func() {
+		windows.SetEvent(cancelled)
+	})
+	defer stop()
+
+	handles := []windows.Handle{windows.Handle(t.handle), cancelled}
+	event, err := windows.WaitForMultipleObjects(handles, false, windows.INFINITE)
+	if err != nil {
+		return fmt.Errorf("wintimer: failed to wait for %s: %w", timerDescription(t.name), err)
+	}
+
+	switch event {
+	case windows.WAIT_OBJECT_0:
+		return nil
+	case windows.WAIT_OBJECT_0 + 1:
+		return ctx.Err()
+	default:
+		return fmt.Errorf("wintimer: failed to wait for %s: unexpected wait result: %#x", timerDescription(t.name), event)
+	}
+}
+
+// Close disarms the timer and closes its handle.
+func (t *Timer) Close() error {
+	if t.handle == 0 {
+		return nil
+	}
+	err := syscall.CloseHandle(t.handle)
+	t.handle = 0
+	return err
+}
+
+func timerDescription(name string) string {
+	if name == "" {
+		return "an unnamed windows timer"
+	}
+	return fmt.Sprintf("the windows timer named \"%s\"", name)
+}
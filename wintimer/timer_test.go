@@ -0,0 +1,109 @@
+//go:build windows
+
+package wintimer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/wintimer"
+)
+
+func TestTimerWait(t *testing.T) {
+	timer, err := wintimer.New(testTimerName("Wait"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer timer.Close()
+
+	start := time.Now()
+	if err := timer.Set(start.Add(50*time.Millisecond), false); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := timer.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("The timer became due after %s when it should have taken at least 50ms", elapsed)
+	}
+}
+
+func TestTimerWaitCancelled(t *testing.T) {
+	timer, err := wintimer.New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer timer.Close()
+
+	if err := timer.Set(time.Now().Add(time.Hour), false); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := timer.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Wait returned %v when it should have returned %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTimerSetPeriodic(t *testing.T) {
+	timer, err := wintimer.New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer timer.Close()
+
+	if err := timer.SetPeriodic(time.Now(), 20*time.Millisecond, false); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for range 3 {
+		if err := timer.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTimerOpen(t *testing.T) {
+	name := testTimerName("Open")
+
+	if _, err := wintimer.Open(name); err == nil {
+		t.Fatal("A timer was opened before it was created")
+	}
+
+	timer, err := wintimer.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer timer.Close()
+
+	opened, err := wintimer.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer opened.Close()
+
+	if err := opened.Set(time.Now(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := timer.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testTimerName(name string) string {
+	return "WinObj-WinTimer-Test-" + name
+}
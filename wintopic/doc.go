@@ -0,0 +1,21 @@
+//go:build windows
+
+// Package wintopic provides a publish and subscribe topic that is shared
+// between processes on Windows, without a broker.
+//
+// A topic is a ring of fixed-size message slots that lives in a named
+// shared memory section, together with a table of named subscribers. Any
+// process can publish to a topic, and every subscriber receives each
+// message that is published after it first subscribes. Each subscriber
+// has a named event of its own, which publishers signal to wake it.
+//
+// Publishers never wait for subscribers. When the ring is full, the oldest
+// messages are overwritten, and subscribers that fall behind are told how
+// many messages they missed.
+//
+// Delivery is at least once. A subscriber acknowledges the messages it
+// has handled, and its position is recorded in the topic, so a subscriber
+// that exits or crashes before acknowledging a message receives it again
+// when it subscribes with the same name, as long as the message has not
+// been overwritten.
+package wintopic
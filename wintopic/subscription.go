@@ -0,0 +1,297 @@
+//go:build windows
+
+package wintopic
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"golang.org/x/sys/windows"
+)
+
+// LagError is returned by a Subscription that has fallen so far behind
+// that messages it had not yet received were overwritten. The
+// subscription skips ahead to the oldest message that is retained, and
+// can continue receiving.
+type LagError struct {
+	Topic      string
+	Subscriber string
+	Missed     uint64 // The number of messages that were missed
+}
+
+// Error returns a description of the lag.
+func (e LagError) Error() string {
+	return fmt.Sprintf("wintopic: the %s subscriber fell behind the %s topic and missed %d messages", e.Subscriber, e.Topic, e.Missed)
+}
+
+// Message is a message received from a topic.
+type Message struct {
+	Sequence uint64 // The sequence number of the message
+	Data     []byte
+}
+
+// Subscription receives the messages published to a topic on behalf of a
+// named subscriber.
+//
+// A Subscription is not safe for concurrent use.
+type Subscription struct {
+	topic    *Topic
+	name     string
+	index    int            // The subscriber's entry in the subscriber table
+	event    syscall.Handle // Signaled by publishers
+	position uint64         // Sequence number of the next message to receive
+}
+
+// Subscribe subscribes to the topic on behalf of the subscriber with the
+// given name, which identifies it across processes and restarts. The name
+// may be up to MaxSubscriberNameLength bytes long and must not contain a
+// backslash.
+//
+// A subscriber that is new to the topic receives the messages published
+// after it subscribes. A subscriber that has subscribed before resumes
+// from the oldest message it has not acknowledged, so messages that were
+// received but not acknowledged are received again.
+//
+// Only one subscription can be open for each subscriber at a time. If the
+// subscriber is already subscribed, in this process or another, it
+// returns ErrSubscribed. If the topic has MaxSubscribers subscribers
+// already, it returns ErrTooManySubscribers.
+//
+// Options such as winobj.WithSecurityDescriptor are applied to the event
+// that publishers signal to wake the subscription.
+//
+// It is the caller's responsibility to close the subscription.
+func (t *Topic) Subscribe(name string, opts ...winobj.Option) (*Subscription, error) {
+	if !validSubscriber(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSubscriber, name)
+	}
+
+	options, err := winobj.ApplyOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("wintopic: failed to subscribe to the %s topic: %w", t.name, err)
+	}
+
+	release, err := t.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// The event exists for as long as a subscription holds it open, so
+	// it also records whether the subscriber is already subscribed.
+	event, existed, err := synchapi.CreateEventEx(t.eventName(name), options.SyscallSecurityAttributes(), 0, synchapi.EventAllAccess)
+	if err != nil {
+		return nil, fmt.Errorf("wintopic: failed to create the event for the %s subscriber of the %s topic: %w", name, t.name, err)
+	}
+	if existed {
+		syscall.CloseHandle(event)
+		return nil, fmt.Errorf("%w: %s of the %s topic", ErrSubscribed, name, t.name)
+	}
+
+	index, found := t.findSubscriber(name)
+	if index < 0 {
+		syscall.CloseHandle(event)
+		return nil, ErrTooManySubscribers
+	}
+
+	entry := t.subscriberAt(index)
+	le := binary.LittleEndian
+	if !found {
+		le.PutUint64(entry[offsetCursor:], t.next())
+		copy(entry[offsetName:], name)
+		le.PutUint32(entry[offsetNameLength:], uint32(len(name)))
+	}
+
+	return &Subscription{
+		topic:    t,
+		name:     name,
+		index:    index,
+		event:    event,
+		position: le.Uint64(entry[offsetCursor:]),
+	}, nil
+}
+
+// Name returns the name of the subscriber.
+func (s *Subscription) Name() string {
+	return s.name
+}
+
+// TryReceive returns the next message without waiting. If there are no
+// new messages, ok is false.
+//
+// If messages were overwritten before the subscription could receive
+// them, it returns a LagError and moves to the oldest message that is
+// retained.
+func (s *Subscription) TryReceive() (msg Message, ok bool, err error) {
+	release, err := s.topic.lock()
+	if err != nil {
+		return Message{}, false, err
+	}
+	defer release()
+
+	return s.receive()
+}
+
+// Receive returns the next message, waiting for one to be published if
+// necessary. It returns an error if ctx is cancelled before a message is
+// available.
+//
+// If messages were overwritten before the subscription could receive
+// them, it returns a LagError and moves to the oldest message that is
+// retained.
+func (s *Subscription) Receive(ctx context.Context) (Message, error) {
+	var cancelled syscall.Handle
+	defer func() {
+		if cancelled != 0 {
+			syscall.CloseHandle(cancelled)
+		}
+	}()
+
+	for {
+		msg, ok, err := s.TryReceive()
+		if err != nil || ok {
+			return msg, err
+		}
+
+		// Prepare an event that will be signaled if ctx is cancelled, so
+		// that the wait can be interrupted.
+		if cancelled == 0 {
+			cancelled, _, err = synchapi.CreateEventEx("", nil, synchapi.CreateEventManualReset, synchapi.EventAllAccess)
+			if err != nil {
+				return Message{}, fmt.Errorf("wintopic: failed to create cancellation event: %w", err)
+			}
+			stop := context.AfterFunc(ctx, func() {
+				synchapi.SetEvent(cancelled)
+			})
+			defer stop()
+		}
+
+		// Publishers signal the event after the message is written, so a
+		// message published since TryReceive looked leaves it signaled.
+		event, err := synchapi.WaitForMultipleObjects([]syscall.Handle{s.event, cancelled}, false, windows.INFINITE)
+		switch {
+		case err != nil:
+			return Message{}, fmt.Errorf("wintopic: failed to wait for the %s topic: %w", s.topic.name, err)
+		case event == windows.WAIT_OBJECT_0:
+		case event == windows.WAIT_OBJECT_0+1:
+			return Message{}, ctx.Err()
+		default:
+			return Message{}, fmt.Errorf("wintopic: failed to wait for the %s topic: unexpected wait result: %#x", s.topic.name, event)
+		}
+	}
+}
+
+// Ack acknowledges every message up to and including the one with the
+// given sequence number, so that they are not received again when the
+// subscriber next subscribes. Acknowledging a message that has already
+// been acknowledged has no effect.
+//
+// If the subscriber has been removed from the topic, it returns
+// ErrSubscriptionRemoved.
+func (s *Subscription) Ack(sequence uint64) error {
+	release, err := s.topic.lock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	entry, err := s.entry()
+	if err != nil {
+		return err
+	}
+
+	le := binary.LittleEndian
+	cursor := min(sequence+1, s.topic.next())
+	if cursor > le.Uint64(entry[offsetCursor:]) {
+		le.PutUint64(entry[offsetCursor:], cursor)
+	}
+	return nil
+}
+
+// Lag returns the number of messages that have been published but not
+// yet received by the subscription, including any that were overwritten.
+func (s *Subscription) Lag() (uint64, error) {
+	release, err := s.topic.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	return s.topic.next() - min(s.position, s.topic.next()), nil
+}
+
+// Unsubscribe removes the subscriber from the topic, discarding its
+// position, and closes the subscription. A later subscription with the
+// same name starts afresh with the messages published after it.
+func (s *Subscription) Unsubscribe() error {
+	release, err := s.topic.lock()
+	if err != nil {
+		return err
+	}
+
+	if entry, err := s.entry(); err == nil {
+		clear(entry)
+	}
+	release()
+
+	return s.Close()
+}
+
+// Close closes the subscription. The subscriber remains subscribed to the
+// topic, and the messages it has not acknowledged are retained for it
+// until they are overwritten.
+func (s *Subscription) Close() error {
+	if s.event == 0 {
+		return nil
+	}
+	err := syscall.CloseHandle(s.event)
+	s.event = 0
+	return err
+}
+
+// receive returns a copy of the next message. The caller must hold the
+// topic's mutex.
+func (s *Subscription) receive() (msg Message, ok bool, err error) {
+	t := s.topic
+
+	if oldest := t.oldest(); s.position < oldest {
+		missed := oldest - s.position
+		s.position = oldest
+		return Message{}, false, LagError{Topic: t.name, Subscriber: s.name, Missed: missed}
+	}
+	if s.position >= t.next() {
+		return Message{}, false, nil
+	}
+
+	// A slot that does not hold the expected message was torn by a
+	// publisher that did not finish writing it.
+	le := binary.LittleEndian
+	slot := t.slotAt(s.position)
+	sequence := s.position
+	s.position++
+	if le.Uint64(slot[offsetSequence:]) != sequence {
+		return Message{}, false, LagError{Topic: t.name, Subscriber: s.name, Missed: 1}
+	}
+
+	length := uint64(le.Uint32(slot[offsetLength:]))
+	if length > t.messageSize {
+		return Message{}, false, LagError{Topic: t.name, Subscriber: s.name, Missed: 1}
+	}
+	data := make([]byte, length)
+	copy(data, slot[slotHeaderSize:])
+	return Message{Sequence: sequence, Data: data}, true, nil
+}
+
+// entry returns the subscriber's entry in the subscriber table. It
+// returns ErrSubscriptionRemoved if the entry no longer belongs to the
+// subscriber. The caller must hold the topic's mutex.
+func (s *Subscription) entry() ([]byte, error) {
+	if name, ok := s.topic.subscriberName(s.index); !ok || name != s.name {
+		return nil, fmt.Errorf("%w: %s of the %s topic", ErrSubscriptionRemoved, s.name, s.topic.name)
+	}
+	return s.topic.subscriberAt(s.index), nil
+}
@@ -0,0 +1,417 @@
+//go:build windows
+
+package wintopic
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winobj"
+	"github.com/gentlemanautomaton/winobj/api/synchapi"
+	"github.com/gentlemanautomaton/winobj/winmutex"
+	"github.com/gentlemanautomaton/winobj/winshm"
+)
+
+// Errors returned by the topic.
+var (
+	ErrIncompatible        = errors.New("wintopic: the shared memory section does not hold a compatible topic")
+	ErrMessageTooLarge     = errors.New("wintopic: the message is too large to fit in a slot of the topic")
+	ErrTooManySubscribers  = errors.New("wintopic: the topic has no room for another subscriber")
+	ErrSubscribed          = errors.New("wintopic: the subscriber is already subscribed in another subscription")
+	ErrInvalidSubscriber   = errors.New("wintopic: the subscriber name is empty, too long or contains a backslash")
+	ErrSubscriptionRemoved = errors.New("wintopic: the subscription has been removed from the topic")
+)
+
+// Version is the version of the topic layout written by this package.
+// Topics with a different version are rejected with ErrIncompatible.
+const Version = 1
+
+// Suffixes appended to the name of a topic to form the names of the
+// objects that accompany it. The event of each subscriber is named by
+// appending SubscriberSuffix and the subscriber's name to the name of the
+// topic.
+const (
+	LockSuffix       = "-Lock"
+	SubscriberSuffix = "-Subscriber-"
+)
+
+// Limits on the size of a topic.
+const (
+	MaxSlots                = 1 << 16 // The maximum number of messages retained by a topic
+	MaxMessageSize          = 1 << 16 // The maximum size of a message, in bytes
+	MaxSubscribers          = 32      // The maximum number of subscribers recorded by a topic
+	MaxSubscriberNameLength = 48      // The maximum length of a subscriber name, in bytes
+)
+
+// schema identifies a section that holds a topic.
+var schema = winshm.Schema{Magic: "WINOBJTP", Version: Version}
+
+// Layout of the topic header, which is followed by the subscriber table
+// and then by the message slots.
+const (
+	offsetSlots       = 0  // The number of message slots
+	offsetMessageSize = 4  // The capacity of each slot, in bytes
+	offsetNext        = 8  // Sequence number of the next message to be published
+	offsetSubscribers = 16 // The subscriber table
+	headerSize        = offsetSubscribers + MaxSubscribers*subscriberSize
+)
+
+// Layout of each entry in the subscriber table.
+const (
+	offsetCursor     = 0  // Sequence number of the oldest unacknowledged message
+	offsetNameLength = 8  // Zero if the entry is free
+	offsetName       = 16 // The name of the subscriber
+	subscriberSize   = offsetName + MaxSubscriberNameLength
+)
+
+// Layout of each message slot, which is followed by its data and padded
+// to a multiple of 8 bytes.
+const (
+	offsetSequence   = 0 // Sequence number of the message held by the slot
+	offsetLength     = 8
+	slotHeaderSize   = 16
+	slotAlignedMask  = 7
+	sequenceUnstable = ^uint64(0) // Marks a slot that is being written
+)
+
+// Topic is a publish and subscribe topic in a named shared memory section.
+//
+// Messages are numbered with sequence numbers that increase for the
+// lifetime of the topic, starting at zero. Each message is stored in the
+// slot given by the remainder of its sequence number with the number of
+// slots.
+type Topic struct {
+	name        string
+	section     *winshm.Section
+	mutex       *winmutex.Mutex
+	slots       uint64
+	messageSize uint64
+	slotSize    uint64
+}
+
+// Create creates a topic with the given name, or opens it if it already
+// exists. The topic retains the last slots messages, each of which may
+// hold up to messageSize bytes.
+//
+// If the topic already exists with a different number of slots or message
+// size, or with an incompatible layout, it returns ErrIncompatible.
+//
+// Options such as winobj.WithSecurityDescriptor are applied to the section
+// and mutex that make up a new topic.
+//
+// It is the caller's responsibility to close the topic.
+func Create(name string, slots, messageSize int, opts ...winobj.Option) (*Topic, error) {
+	if slots <= 0 || slots > MaxSlots {
+		return nil, fmt.Errorf("wintopic: the number of slots in the %s topic must be between 1 and %d", name, MaxSlots)
+	}
+	if messageSize <= 0 || messageSize > MaxMessageSize {
+		return nil, fmt.Errorf("wintopic: the message size of the %s topic must be between 1 and %d bytes", name, MaxMessageSize)
+	}
+
+	t := &Topic{
+		name:        name,
+		slots:       uint64(slots),
+		messageSize: uint64(messageSize),
+	}
+	t.slotSize = slotSizeFor(t.messageSize)
+
+	err := t.init(func() (bool, error) {
+		section, existed, err := winshm.Create(name, schema, headerSize+int(t.slots*t.slotSize), opts...)
+		if err != nil {
+			return false, err
+		}
+		t.section = section
+		return existed, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Open opens an existing topic with the given name. Its number of slots
+// and message size are read from the topic.
+//
+// It is the caller's responsibility to close the topic.
+func Open(name string) (*Topic, error) {
+	t := &Topic{name: name}
+
+	err := t.init(func() (bool, error) {
+		section, err := winshm.Open(name, schema)
+		if err != nil {
+			return false, err
+		}
+		t.section = section
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// init opens the topic's mutex, and while holding it, calls open to
+// create or open the section. It then writes the header of a new topic,
+// or validates the header of an existing one. The mutex is created with
+// opts if it does not exist.
+func (t *Topic) init(open func() (existed bool, err error), opts ...winobj.Option) (err error) {
+	t.mutex, err = winmutex.New(t.name+LockSuffix, opts...)
+	if err != nil {
+		return err
+	}
+
+	release, err := t.mutex.Acquire(context.Background())
+	if err != nil {
+		t.mutex.Close()
+		return err
+	}
+
+	existed, err := open()
+	if err != nil {
+		release()
+		t.mutex.Close()
+		var incompatible winshm.IncompatibleError
+		if errors.As(err, &incompatible) {
+			return fmt.Errorf("%w: %w", ErrIncompatible, err)
+		}
+		return err
+	}
+
+	if err := t.initHeader(existed); err != nil {
+		release()
+		t.section.Close()
+		t.mutex.Close()
+		return err
+	}
+
+	release()
+	return nil
+}
+
+// initHeader writes the header of a new topic or validates the header of
+// an existing one. The caller must hold the topic's mutex.
+func (t *Topic) initHeader(existed bool) error {
+	data := t.section.Bytes()
+	if len(data) < headerSize {
+		return ErrIncompatible
+	}
+
+	le := binary.LittleEndian
+
+	if !existed {
+		le.PutUint32(data[offsetSlots:], uint32(t.slots))
+		le.PutUint32(data[offsetMessageSize:], uint32(t.messageSize))
+		le.PutUint64(data[offsetNext:], 0)
+		return nil
+	}
+
+	slots := uint64(le.Uint32(data[offsetSlots:]))
+	messageSize := uint64(le.Uint32(data[offsetMessageSize:]))
+
+	// When creating, the existing topic must match the requested layout.
+	if t.slots != 0 && (slots != t.slots || messageSize != t.messageSize) {
+		return ErrIncompatible
+	}
+
+	t.slots, t.messageSize = slots, messageSize
+	t.slotSize = slotSizeFor(messageSize)
+	if slots == 0 || slots > MaxSlots || messageSize == 0 || messageSize > MaxMessageSize || headerSize+slots*t.slotSize > uint64(len(data)) {
+		return ErrIncompatible
+	}
+
+	return nil
+}
+
+// Name returns the name of the topic.
+func (t *Topic) Name() string {
+	return t.name
+}
+
+// Slots returns the number of messages retained by the topic.
+func (t *Topic) Slots() int {
+	return int(t.slots)
+}
+
+// MessageSize returns the maximum size of a message, in bytes.
+func (t *Topic) MessageSize() int {
+	return int(t.messageSize)
+}
+
+// Publish adds a message holding a copy of data to the topic and wakes
+// its subscribers. It returns the sequence number of the message. If the
+// topic is full, the oldest message is overwritten.
+//
+// If data is larger than the topic's message size, it returns
+// ErrMessageTooLarge.
+//
+// The message is published even if a subscriber cannot be woken, in
+// which case the error is returned along with its sequence number.
+// Subscribers that are not running are skipped.
+func (t *Topic) Publish(data []byte) (sequence uint64, err error) {
+	if uint64(len(data)) > t.messageSize {
+		return 0, ErrMessageTooLarge
+	}
+
+	release, err := t.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	// Mark the slot as unstable while it is written, so that a publisher
+	// that crashes part way through does not leave a torn message behind
+	// under the sequence number of the message it overwrote.
+	sequence = t.next()
+	slot := t.slotAt(sequence)
+	le := binary.LittleEndian
+	le.PutUint64(slot[offsetSequence:], sequenceUnstable)
+	le.PutUint32(slot[offsetLength:], uint32(len(data)))
+	copy(slot[slotHeaderSize:], data)
+	le.PutUint64(slot[offsetSequence:], sequence)
+	le.PutUint64(t.section.Bytes()[offsetNext:], sequence+1)
+
+	var errs []error
+	for i := range MaxSubscribers {
+		name, ok := t.subscriberName(i)
+		if !ok {
+			continue
+		}
+		if err := t.signal(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return sequence, errors.Join(errs...)
+}
+
+// Subscribers returns the names of the topic's subscribers.
+func (t *Topic) Subscribers() ([]string, error) {
+	release, err := t.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var names []string
+	for i := range MaxSubscribers {
+		if name, ok := t.subscriberName(i); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Close closes the topic's section and mutex. The topic is destroyed once
+// every process has closed it.
+func (t *Topic) Close() error {
+	err1 := t.section.Close()
+	err2 := t.mutex.Close()
+	return errors.Join(err1, err2)
+}
+
+// lock acquires the topic's mutex. A mutex abandoned by a publisher that
+// crashed is acquired as usual, because a torn message is detected by its
+// sequence number.
+func (t *Topic) lock() (release func(), err error) {
+	release, err = t.mutex.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("wintopic: failed to lock the %s topic: %w", t.name, err)
+	}
+	return release, nil
+}
+
+// signal wakes the subscriber with the given name. If the subscriber is
+// not running, its event does not exist and it is skipped. The caller
+// must hold the topic's mutex.
+func (t *Topic) signal(subscriber string) error {
+	event, err := synchapi.OpenEvent(t.eventName(subscriber), synchapi.EventModifyState)
+	if err == syscall.ERROR_FILE_NOT_FOUND {
+		return nil
+	}
+	if err == nil {
+		err = synchapi.SetEvent(event)
+		syscall.CloseHandle(event)
+	}
+	if err != nil {
+		return fmt.Errorf("wintopic: failed to wake the %s subscriber of the %s topic: %w", subscriber, t.name, err)
+	}
+	return nil
+}
+
+// eventName returns the qualified name of the event of the subscriber
+// with the given name.
+func (t *Topic) eventName(subscriber string) string {
+	return winobj.Qualify(t.name + SubscriberSuffix + subscriber)
+}
+
+// next returns the sequence number of the next message to be published.
+// The caller must hold the topic's mutex.
+func (t *Topic) next() uint64 {
+	return binary.LittleEndian.Uint64(t.section.Bytes()[offsetNext:])
+}
+
+// oldest returns the sequence number of the oldest message that is
+// retained. The caller must hold the topic's mutex.
+func (t *Topic) oldest() uint64 {
+	next := t.next()
+	return next - min(next, t.slots)
+}
+
+// slotAt returns the slot that holds the message with the given sequence
+// number. The caller must hold the topic's mutex.
+func (t *Topic) slotAt(sequence uint64) []byte {
+	start := headerSize + (sequence%t.slots)*t.slotSize
+	return t.section.Bytes()[start : start+t.slotSize]
+}
+
+// subscriberAt returns the entry of the subscriber table with the given
+// index. The caller must hold the topic's mutex.
+func (t *Topic) subscriberAt(i int) []byte {
+	start := offsetSubscribers + i*subscriberSize
+	return t.section.Bytes()[start : start+subscriberSize]
+}
+
+// subscriberName returns the name of the subscriber with the given index.
+// If the entry is free, ok is false. The caller must hold the topic's
+// mutex.
+func (t *Topic) subscriberName(i int) (name string, ok bool) {
+	entry := t.subscriberAt(i)
+	length := binary.LittleEndian.Uint32(entry[offsetNameLength:])
+	if length == 0 || length > MaxSubscriberNameLength {
+		return "", false
+	}
+	return string(entry[offsetName : offsetName+length]), true
+}
+
+// findSubscriber returns the index of the subscriber with the given name.
+// If there is no such subscriber, it returns the index of a free entry
+// and found is false. If there is no free entry either, index is -1. The
+// caller must hold the topic's mutex.
+func (t *Topic) findSubscriber(name string) (index int, found bool) {
+	index = -1
+	for i := range MaxSubscribers {
+		existing, ok := t.subscriberName(i)
+		switch {
+		case ok && existing == name:
+			return i, true
+		case !ok && index < 0:
+			index = i
+		}
+	}
+	return index, false
+}
+
+// validSubscriber reports whether name can be used as the name of a
+// subscriber.
+func validSubscriber(name string) bool {
+	return name != "" && len(name) <= MaxSubscriberNameLength && !strings.Contains(name, `\`)
+}
+
+// slotSizeFor returns the size of a slot that holds messages of up to
+// messageSize bytes.
+func slotSizeFor(messageSize uint64) uint64 {
+	return (slotHeaderSize + messageSize + slotAlignedMask) &^ slotAlignedMask
+}
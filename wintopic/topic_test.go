@@ -0,0 +1,238 @@
+//go:build windows
+
+package wintopic_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winobj/wintopic"
+)
+
+func TestTopicFanOut(t *testing.T) {
+	name := testTopicName("FanOut")
+
+	publisher, err := wintopic.Create(name, 16, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer publisher.Close()
+
+	topic, err := wintopic.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer topic.Close()
+
+	var subs []*wintopic.Subscription
+	for _, subscriber := range []string{"A", "B"} {
+		sub, err := topic.Subscribe(subscriber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sub.Close()
+		subs = append(subs, sub)
+	}
+
+	for i := range 3 {
+		if _, err := publisher.Publish([]byte(fmt.Sprintf("message %d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for _, sub := range subs {
+		for i := range 3 {
+			msg, err := sub.Receive(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := fmt.Sprintf("message %d", i); string(msg.Data) != want || msg.Sequence != uint64(i) {
+				t.Fatalf("Subscriber %s received %q (%d) when it should have received %q (%d)", sub.Name(), msg.Data, msg.Sequence, want, i)
+			}
+		}
+		if _, ok, err := sub.TryReceive(); err != nil || ok {
+			t.Fatalf("TryReceive returned %t (%v) when subscriber %s should have been caught up", ok, err, sub.Name())
+		}
+	}
+
+	names, err := publisher.Subscribers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{"A", "B"}) {
+		t.Fatalf("Subscribers returned %v", names)
+	}
+}
+
+func TestTopicReceiveWaits(t *testing.T) {
+	topic, err := wintopic.Create(testTopicName("ReceiveWaits"), 16, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer topic.Close()
+
+	sub, err := topic.Subscribe("Waiter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		topic.Publish([]byte("wake"))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msg, err := sub.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(msg.Data) != "wake" {
+		t.Fatalf("Receive returned %q", msg.Data)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := sub.Receive(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Receive returned %v instead of waiting for the context", err)
+	}
+}
+
+func TestTopicAtLeastOnce(t *testing.T) {
+	topic, err := wintopic.Create(testTopicName("AtLeastOnce"), 16, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer topic.Close()
+
+	sub, err := topic.Subscribe("Durable")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := topic.Subscribe("Durable"); !errors.Is(err, wintopic.ErrSubscribed) {
+		t.Fatalf("A second subscription for the same subscriber returned %v instead of ErrSubscribed", err)
+	}
+
+	for i := range 3 {
+		if _, err := topic.Publish([]byte(fmt.Sprintf("message %d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Receive every message but only acknowledge the first one, as a
+	// subscriber that crashed while handling the second would.
+	for range 3 {
+		msg, ok, err := sub.TryReceive()
+		if err != nil || !ok {
+			t.Fatalf("TryReceive returned %t (%v)", ok, err)
+		}
+		if msg.Sequence == 0 {
+			if err := sub.Ack(msg.Sequence); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := sub.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err = topic.Subscribe("Durable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	for i := 1; i < 3; i++ {
+		msg, ok, err := sub.TryReceive()
+		if err != nil || !ok {
+			t.Fatalf("TryReceive returned %t (%v) after resubscribing", ok, err)
+		}
+		if msg.Sequence != uint64(i) {
+			t.Fatalf("Message %d was redelivered when message %d was expected", msg.Sequence, i)
+		}
+	}
+
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatal(err)
+	}
+	names, err := topic.Subscribers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 0 {
+		t.Fatalf("Subscribers returned %v after the subscriber unsubscribed", names)
+	}
+}
+
+func TestTopicLag(t *testing.T) {
+	topic, err := wintopic.Create(testTopicName("Lag"), 4, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer topic.Close()
+
+	sub, err := topic.Subscribe("Slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	for i := range 6 {
+		if _, err := topic.Publish([]byte(fmt.Sprintf("message %d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if lag, err := sub.Lag(); err != nil || lag != 6 {
+		t.Fatalf("Lag returned %d (%v) when 6 messages were waiting", lag, err)
+	}
+
+	_, _, err = sub.TryReceive()
+	var lagErr wintopic.LagError
+	if !errors.As(err, &lagErr) {
+		t.Fatalf("TryReceive returned %v instead of a LagError", err)
+	}
+	if lagErr.Missed != 2 {
+		t.Fatalf("The subscriber missed %d messages when it should have missed 2", lagErr.Missed)
+	}
+
+	msg, ok, err := sub.TryReceive()
+	if err != nil || !ok {
+		t.Fatalf("TryReceive returned %t (%v) after the lag was reported", ok, err)
+	}
+	if string(msg.Data) != "message 2" {
+		t.Fatalf("TryReceive returned %q after the lag was reported", msg.Data)
+	}
+}
+
+func TestTopicLimits(t *testing.T) {
+	topic, err := wintopic.Create(testTopicName("Limits"), 4, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer topic.Close()
+
+	if _, err := topic.Publish(make([]byte, 9)); !errors.Is(err, wintopic.ErrMessageTooLarge) {
+		t.Fatalf("Publish returned %v instead of ErrMessageTooLarge", err)
+	}
+	if _, err := wintopic.Create(topic.Name(), 8, 8); !errors.Is(err, wintopic.ErrIncompatible) {
+		t.Fatalf("Create returned %v instead of ErrIncompatible for a different number of slots", err)
+	}
+	if _, err := topic.Subscribe(`Invalid\Name`); !errors.Is(err, wintopic.ErrInvalidSubscriber) {
+		t.Fatalf("Subscribe returned %v instead of ErrInvalidSubscriber", err)
+	}
+}
+
+func testTopicName(name string) string {
+	return "WinObj-WinTopic-Test-" + name
+}